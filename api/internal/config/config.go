@@ -9,14 +9,26 @@ import (
 // Config centralises every runtime setting so the rest of the codebase can remain deterministic
 // and easy to test. All fields can be overridden using environment variables.
 type Config struct {
-	AppName   string         `env:"APP_NAME" envDefault:"fast-pin-pon-api"`
-	Env       string         `env:"APP_ENV" envDefault:"development"`
-	LogLevel  string         `env:"LOG_LEVEL" envDefault:"info"`
-	LogFile   string         `env:"LOG_FILE" envDefault:"logs/api.log"`
-	EngineURL string         `env:"ENGINE_CALLBACK_URL" envDefault:"http://engine:8081"`
-	HTTP      HTTPConfig     `envPrefix:"HTTP_"`
-	Database  DatabaseConfig `envPrefix:"DB_"`
-	Keycloak  KeycloakConfig `envPrefix:"KEYCLOAK_"`
+	AppName  string `env:"APP_NAME" envDefault:"fast-pin-pon-api"`
+	Env      string `env:"APP_ENV" envDefault:"development"`
+	LogLevel string `env:"LOG_LEVEL" envDefault:"info"`
+	LogFile  string `env:"LOG_FILE" envDefault:"logs/api.log"`
+	// LogFileRequired stops startup when LogFile cannot be opened instead of logging to stdout only.
+	LogFileRequired bool               `env:"LOG_FILE_REQUIRED" envDefault:"false"`
+	EngineURLs      []string           `env:"ENGINE_CALLBACK_URL" envDefault:"http://engine:8081"`
+	HTTP            HTTPConfig         `envPrefix:"HTTP_"`
+	Database        DatabaseConfig     `envPrefix:"DB_"`
+	Keycloak        KeycloakConfig     `envPrefix:"KEYCLOAK_"`
+	Rebalance       RebalanceConfig    `envPrefix:"REBALANCE_"`
+	Routing         RoutingConfig      `envPrefix:"ROUTING_"`
+	Sync            SyncConfig         `envPrefix:"SYNC_"`
+	Intervention    InterventionConfig `envPrefix:"INTERVENTION_"`
+	Telemetry       TelemetryConfig    `envPrefix:"TELEMETRY_"`
+	Dispatch        DispatchConfig     `envPrefix:"DISPATCH_"`
+	Event           EventConfig        `envPrefix:"EVENT_"`
+	Stream          StreamConfig       `envPrefix:"STREAM_"`
+	Unit            UnitConfig         `envPrefix:"UNIT_"`
+	SLO             SLOConfig          `envPrefix:"SLO_"`
 }
 
 // KeycloakConfig holds Keycloak authentication settings.
@@ -25,6 +37,10 @@ type KeycloakConfig struct {
 	PublicURL string `env:"PUBLIC_URL" envDefault:"http://localhost:8082"`
 	Realm     string `env:"REALM" envDefault:"sdmis-realm"`
 	ClientID  string `env:"CLIENT_ID" envDefault:"sdmis-api"`
+	// JWKSRetryInterval is the delay between JWKS fetch attempts while no keys are loaded.
+	JWKSRetryInterval time.Duration `env:"JWKS_RETRY_INTERVAL" envDefault:"5s"`
+	// JWKSStartupGrace is how long startup waits for the JWKS before serving without it.
+	JWKSStartupGrace time.Duration `env:"JWKS_STARTUP_GRACE" envDefault:"10s"`
 }
 
 // HTTPConfig controls the HTTP server behaviour.
@@ -33,6 +49,12 @@ type HTTPConfig struct {
 	ReadTimeout  time.Duration `env:"READ_TIMEOUT" envDefault:"15s"`
 	WriteTimeout time.Duration `env:"WRITE_TIMEOUT" envDefault:"15s"`
 	IdleTimeout  time.Duration `env:"IDLE_TIMEOUT" envDefault:"60s"`
+	// LogRedactParams lists query parameters whose values are replaced by *** in request logs.
+	LogRedactParams []string `env:"LOG_REDACT_PARAMS" envDefault:"access_token,token,password"`
+	// RequireIfMatch rejects unit and event PATCH requests that carry neither an If-Match header nor a version field.
+	// It is opt-in so that existing clients keep working; writers that do not read before writing
+	// send If-Match: * to update unconditionally.
+	RequireIfMatch bool `env:"REQUIRE_IF_MATCH" envDefault:"false"`
 }
 
 // DatabaseConfig groups the Postgres/PostGIS settings.
@@ -45,6 +67,161 @@ type DatabaseConfig struct {
 	MaxConnLifetime time.Duration `env:"MAX_CONN_LIFETIME" envDefault:"30m"`
 }
 
+// RebalanceConfig tunes the advisory base rebalancing suggestions.
+type RebalanceConfig struct {
+	MinImbalance int `env:"MIN_IMBALANCE" envDefault:"2"`
+	MaxMoves     int `env:"MAX_MOVES" envDefault:"10"`
+}
+
+// RoutingConfig controls routing graph related behaviour.
+type RoutingConfig struct {
+	NetworkStatsTTL time.Duration `env:"NETWORK_STATS_TTL" envDefault:"10m"`
+	// MaxRouteAge is the age after which an active assignment route is recalculated; 0 disables the worker.
+	MaxRouteAge time.Duration `env:"MAX_ROUTE_AGE" envDefault:"0"`
+	// RefreshInterval is how often the worker looks for stale routes.
+	RefreshInterval time.Duration `env:"REFRESH_INTERVAL" envDefault:"1m"`
+	// RefreshConcurrency bounds the number of routes recalculated in parallel.
+	RefreshConcurrency int `env:"REFRESH_CONCURRENCY" envDefault:"4"`
+	// RefreshBatchSize bounds the number of routes recalculated per pass.
+	RefreshBatchSize int32 `env:"REFRESH_BATCH_SIZE" envDefault:"50"`
+	// AllowZeroLengthRoutes returns a zero-length route instead of "no route found"
+	// when origin and destination snap to the same graph vertex.
+	AllowZeroLengthRoutes bool `env:"ALLOW_ZERO_LENGTH_ROUTES" envDefault:"true"`
+	// NearestMaxUnits caps the units, closest first by straight line, routed by /v1/routing/nearest-by-time.
+	NearestMaxUnits int32 `env:"NEAREST_MAX_UNITS" envDefault:"20"`
+	// NearestConcurrency bounds the number of routes computed in parallel for one nearest-by-time request.
+	NearestConcurrency int `env:"NEAREST_CONCURRENCY" envDefault:"4"`
+	// ArchiveRoutes keeps the last route of each assignment in unit_route_history when it is removed.
+	ArchiveRoutes bool `env:"ARCHIVE_ROUTES" envDefault:"false"`
+	// RouteHistoryRetention is how long archived routes are kept; 0 keeps them forever.
+	RouteHistoryRetention time.Duration `env:"ROUTE_HISTORY_RETENTION" envDefault:"720h"`
+	// RouteHistoryPruneInterval is how often archived routes past the retention are deleted.
+	RouteHistoryPruneInterval time.Duration `env:"ROUTE_HISTORY_PRUNE_INTERVAL" envDefault:"1h"`
+	// MaxConcurrentRepairs caps route repairs and recalculations running at once across the fleet.
+	// Manual repairs beyond it are rejected; background ones wait for a slot.
+	MaxConcurrentRepairs int `env:"MAX_CONCURRENT_REPAIRS" envDefault:"8"`
+	// AutoRouteOnAssign calculates the unit route in the background whenever an assignment is created.
+	AutoRouteOnAssign bool `env:"AUTO_ROUTE_ON_ASSIGN" envDefault:"true"`
+	// AssignRouteConcurrency bounds the assignment routes calculated in parallel in the background.
+	AssignRouteConcurrency int `env:"ASSIGN_ROUTE_CONCURRENCY" envDefault:"4"`
+	// RouteDeviationMeters is the distance from its route past which a unit location update
+	// triggers a route recalculation from the new position; 0 disables the check.
+	RouteDeviationMeters float64 `env:"ROUTE_DEVIATION_METERS" envDefault:"0"`
+	// RouteCacheTTL is how long a route between two points (rounded to ~10 m) is reused; 0 (the default) disables the cache.
+	RouteCacheTTL time.Duration `env:"ROUTE_CACHE_TTL" envDefault:"0"`
+}
+
+// SyncConfig controls the defaults of the /v1/sync dashboard endpoint.
+type SyncConfig struct {
+	// DefaultDenyStatuses lists intervention statuses hidden when the request has no deny_status.
+	DefaultDenyStatuses []string `env:"DEFAULT_DENY_STATUSES" envDefault:"completed,cancelled"`
+	// DefaultLimitLogs is the number of recent logs returned when the request has no limit_logs.
+	DefaultLimitLogs int `env:"DEFAULT_LIMIT_LOGS" envDefault:"10"`
+	// MaxLimitLogs caps limit_logs; larger values are clamped.
+	MaxLimitLogs int `env:"MAX_LIMIT_LOGS" envDefault:"100"`
+	// PollDefaultWait is how long /v1/sync/poll holds a request without ?wait=.
+	PollDefaultWait time.Duration `env:"POLL_DEFAULT_WAIT" envDefault:"25s"`
+	// PollMaxWait caps ?wait=; keep it below the 60s request timeout.
+	PollMaxWait time.Duration `env:"POLL_MAX_WAIT" envDefault:"55s"`
+	// UnitFetchConcurrency bounds the assigned-unit queries run in parallel while building a sync response.
+	UnitFetchConcurrency int `env:"UNIT_FETCH_CONCURRENCY" envDefault:"8"`
+}
+
+// InterventionConfig controls automatic intervention lifecycle transitions.
+type InterventionConfig struct {
+	// AutoCompleteOnRelease completes an on_site intervention once its last active assignment is released.
+	AutoCompleteOnRelease bool `env:"AUTO_COMPLETE_ON_RELEASE" envDefault:"false"`
+	// EngineClientIDs are Keycloak clients whose tokens identify the dispatch engine;
+	// interventions they create default to auto_suggested instead of manual.
+	EngineClientIDs []string `env:"ENGINE_CLIENT_IDS" envDefault:"sdmis-engine"`
+	// TrustSourceHeader also treats requests carrying "X-Request-Source: engine" as engine requests.
+	// Any client can set the header, so enable it only where the engine has no service account.
+	TrustSourceHeader bool `env:"TRUST_SOURCE_HEADER" envDefault:"false"`
+	// SingletonRoles are assignment roles held by at most one active assignment per intervention.
+	SingletonRoles []string `env:"SINGLETON_ROLES" envDefault:"command"`
+	// LogAssignmentChanges writes unit_dispatched/unit_released entries on the event timeline.
+	LogAssignmentChanges bool `env:"LOG_ASSIGNMENT_CHANGES" envDefault:"true"`
+	// MaxPerEvent caps the non-cancelled interventions of one event; 0 disables the cap.
+	// Users with the superieur role may exceed it.
+	MaxPerEvent int64 `env:"MAX_PER_EVENT" envDefault:"10"`
+	// SeverityPriorities maps event severity to the priority given to interventions
+	// created without one ("1:1,...,5:5"); every severity 1-5 must be mapped.
+	SeverityPriorities map[int32]int32 `env:"SEVERITY_PRIORITIES" envDefault:"1:1,2:2,3:3,4:4,5:5"`
+	// StatusTransitions are the "from:to" status changes allowed through the status PATCH;
+	// an empty list allows any change. Users with the superieur role may bypass it.
+	StatusTransitions []string `env:"STATUS_TRANSITIONS" envDefault:"created:on_site,created:completed,created:cancelled,on_site:completed,on_site:cancelled"`
+}
+
+// TelemetryConfig holds plausibility checks applied to incoming telemetry.
+type TelemetryConfig struct {
+	// MaxSpeedKMH is the highest plausible speed; 0 disables the check.
+	MaxSpeedKMH float64 `env:"MAX_SPEED_KMH" envDefault:"250"`
+	// SpeedCeilingMode is "reject" (400) or "clamp" (store MaxSpeedKMH and flag the response).
+	SpeedCeilingMode string `env:"SPEED_CEILING_MODE" envDefault:"reject"`
+	// SnapToRoute projects telemetry and location updates onto the unit's active route and advances its progress.
+	SnapToRoute bool `env:"SNAP_TO_ROUTE" envDefault:"false"`
+	// SnapMaxDistanceMeters is the farthest a raw position may be from the route to be snapped.
+	SnapMaxDistanceMeters float64 `env:"SNAP_MAX_DISTANCE_METERS" envDefault:"50"`
+}
+
+// DispatchConfig controls dispatch endpoints behaviour.
+type DispatchConfig struct {
+	// StaticCacheTTL is how long /v1/dispatch/static is served from memory; 0 disables caching.
+	StaticCacheTTL time.Duration `env:"STATIC_CACHE_TTL" envDefault:"5m"`
+	// EngineRefreshDebounce coalesces config edits made within this window into one engine refresh; 0 disables it.
+	EngineRefreshDebounce time.Duration `env:"ENGINE_REFRESH_DEBOUNCE" envDefault:"2s"`
+	// HealthInterval is how often /v1/dispatch/health/stream pushes and how long the summary is cached.
+	HealthInterval time.Duration `env:"HEALTH_INTERVAL" envDefault:"5s"`
+	// EngineRetries is how many times a failed engine notification is retried, per engine.
+	EngineRetries int `env:"ENGINE_RETRIES" envDefault:"2"`
+	// EngineRetryBackoff is the delay before the first retry; it doubles on each attempt.
+	EngineRetryBackoff time.Duration `env:"ENGINE_RETRY_BACKOFF" envDefault:"500ms"`
+}
+
+// EventConfig bounds client-supplied event timestamps and controls stale event handling.
+type EventConfig struct {
+	// ReportedAtMaxSkew is how far in the future reported_at may be, to absorb client clock drift.
+	ReportedAtMaxSkew time.Duration `env:"REPORTED_AT_MAX_SKEW" envDefault:"2m"`
+	// ReportedAtMaxAge is how far in the past reported_at may be back-dated.
+	ReportedAtMaxAge time.Duration `env:"REPORTED_AT_MAX_AGE" envDefault:"8760h"`
+	// DisallowedCombinations lists "event_type_code:severity" pairs refused by policy
+	// (e.g. "false_alarm:5,false_alarm:4").
+	DisallowedCombinations []string `env:"DISALLOWED_COMBINATIONS"`
+	// StaleAfter is how long an open event may go without an open or recent intervention before
+	// it is flagged stale; 0 disables the stale event worker.
+	StaleAfter time.Duration `env:"STALE_AFTER" envDefault:"0"`
+	// StaleCheckInterval is how often the stale event worker runs.
+	StaleCheckInterval time.Duration `env:"STALE_CHECK_INTERVAL" envDefault:"10m"`
+	// AutoCloseStale closes stale events instead of only flagging them.
+	AutoCloseStale bool `env:"AUTO_CLOSE_STALE" envDefault:"false"`
+}
+
+// StreamConfig protects the realtime (SSE) endpoints.
+type StreamConfig struct {
+	// MaxConnectionsPerUser caps simultaneous stream connections per JWT subject; 0 disables the limit.
+	MaxConnectionsPerUser int `env:"MAX_CONNECTIONS_PER_USER" envDefault:"5"`
+	// KeepAlive is the interval between SSE keep-alive comments.
+	KeepAlive time.Duration `env:"KEEP_ALIVE" envDefault:"15s"`
+	// SubscriberBuffer is the number of pending messages kept per stream client before new ones are dropped.
+	SubscriberBuffer int `env:"SUBSCRIBER_BUFFER" envDefault:"64"`
+}
+
+// UnitConfig controls which unit statuses the API accepts.
+type UnitConfig struct {
+	// Statuses are the unit statuses accepted on create, status updates and check-ins.
+	// Each must be a value of the unit_status enum (migration 027 adds maintenance and returning).
+	Statuses []string `env:"STATUSES" envDefault:"available,available_hidden,under_way,on_site,unavailable,offline"`
+	// AwayDistanceMeters is how far from its home base an available unit must be to be listed by /v1/units/away.
+	AwayDistanceMeters float64 `env:"AWAY_DISTANCE_METERS" envDefault:"500"`
+}
+
+// SLOConfig holds the operational response-time targets tracked in metrics.
+type SLOConfig struct {
+	// ResponseTargets maps event severity to the target dispatch-to-arrival time ("5:8m,4:10m");
+	// severities without a target are not tracked.
+	ResponseTargets map[int32]time.Duration `env:"RESPONSE_TARGETS" envDefault:"5:8m,4:10m,3:15m"`
+}
+
 // Load reads configuration from the environment, applying defaults defined above.
 func Load() (Config, error) {
 	var cfg Config
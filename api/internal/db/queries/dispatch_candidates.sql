@@ -15,6 +15,7 @@ SELECT
     e.severity AS event_severity,
     e.event_type_code,
     et.recommended_unit_types,
+    et.recommended_unit_counts,
     (ST_X(e.location::geometry))::double precision AS longitude,
     (ST_Y(e.location::geometry))::double precision AS latitude
 FROM interventions i
@@ -23,7 +24,8 @@ JOIN event_types et ON e.event_type_code = et.code
 WHERE i.id = $1;
 
 -- name: ListPendingInterventions :many
--- Lists interventions awaiting dispatch, ordered by severity and age
+-- Lists interventions awaiting dispatch, uncovered ones first, then by severity, priority and age;
+-- only_uncovered keeps the interventions without an active assignment
 SELECT 
     i.id AS intervention_id,
     i.event_id,
@@ -41,7 +43,10 @@ JOIN events e ON i.event_id = e.id
 JOIN event_types et ON e.event_type_code = et.code
 WHERE i.status = 'created'
   AND e.auto_simulated = true
-ORDER BY e.severity DESC, i.created_at ASC;
+  AND (NOT sqlc.arg(only_uncovered)::boolean OR NOT EXISTS (
+      SELECT 1 FROM intervention_assignments ia WHERE ia.intervention_id = i.id AND ia.status IN ('dispatched', 'arrived')
+  ))
+ORDER BY assigned_units_count ASC, event_severity DESC, i.priority DESC, i.created_at ASC;
 
 -- name: ListDispatchCandidates :many
 -- Finds candidate units for dispatch using distance-based estimation
@@ -69,10 +74,7 @@ SELECT
 FROM units u
 LEFT JOIN locations l ON u.location_id = l.id
 CROSS JOIN (
-    SELECT ev.location
-    FROM interventions iv
-    JOIN events ev ON iv.event_id = ev.id
-    WHERE iv.id = sqlc.arg(intervention_id)
+    SELECT ev.location FROM events ev WHERE ev.id = sqlc.arg(event_id)
 ) e
 LEFT JOIN intervention_assignments a 
     ON a.unit_id = u.id AND a.status = 'dispatched'
@@ -86,6 +88,44 @@ WHERE u.status IN ('available', 'available_hidden', 'under_way')
 ORDER BY ST_Distance(u.location, e.location) ASC
 LIMIT sqlc.arg(max_candidates)::int;
 
+-- name: ListNearestAvailableUnitsByType :many
+-- Nearest available unit of each requested type to an event
+-- Uses the same distance-based travel time estimate as ListDispatchCandidates
+SELECT DISTINCT ON (u.unit_type_code)
+    u.id,
+    u.call_sign,
+    u.unit_type_code,
+    l.name AS home_base_name,
+    u.status,
+    (ST_X(u.location::geometry))::double precision AS longitude,
+    (ST_Y(u.location::geometry))::double precision AS latitude,
+    (ST_Distance(u.location, e.location) / 13.89)::double precision AS travel_time_seconds,
+    ST_Distance(u.location, e.location)::double precision AS distance_meters,
+    (SELECT COUNT(*) FROM units u2 
+     WHERE u2.location_id = u.location_id 
+       AND u2.status = 'available' 
+       AND u2.id != u.id)::int AS other_units_at_base
+FROM units u
+LEFT JOIN locations l ON u.location_id = l.id
+CROSS JOIN (
+    SELECT ev.location FROM events ev WHERE ev.id = sqlc.arg(event_id)
+) e
+WHERE u.status IN ('available', 'available_hidden')
+  AND u.location IS NOT NULL
+  AND u.unit_type_code = ANY(sqlc.arg(unit_types)::text[])
+ORDER BY u.unit_type_code, ST_Distance(u.location, e.location) ASC;
+
+-- name: GetEventStaffingNeeds :one
+-- Recommended unit types and counts of an event's type
+SELECT
+    e.id AS event_id,
+    e.event_type_code,
+    et.recommended_unit_types,
+    et.recommended_unit_counts
+FROM events e
+JOIN event_types et ON e.event_type_code = et.code
+WHERE e.id = $1;
+
 -- name: GetUnitsAtBase :one
 -- Count available units at a specific base (for coverage calculations)
 SELECT 
@@ -101,3 +141,35 @@ SET
     status = 'released',
     released_at = NOW()
 WHERE id = $1;
+
+-- name: ListAssignmentsMissingRoute :many
+-- Lists active dispatched assignments whose unit has no stored route,
+-- i.e. route calculation failed after the unit was assigned
+SELECT
+    ia.id AS assignment_id,
+    ia.intervention_id,
+    i.event_id,
+    i.status AS intervention_status,
+    ia.unit_id,
+    u.call_sign,
+    u.unit_type_code,
+    u.status AS unit_status,
+    e.severity AS event_severity,
+    ia.dispatched_at
+FROM intervention_assignments ia
+JOIN interventions i ON i.id = ia.intervention_id
+JOIN events e ON e.id = i.event_id
+JOIN units u ON u.id = ia.unit_id
+LEFT JOIN unit_routes ur ON ur.unit_id = ia.unit_id
+WHERE ia.released_at IS NULL
+  AND ia.status = 'dispatched'
+  AND ur.unit_id IS NULL
+ORDER BY e.severity DESC, ia.dispatched_at ASC;
+
+-- name: GetDispatchHealth :one
+-- Aggregated dispatch health counters for the wall display
+SELECT
+    (SELECT COUNT(*) FROM interventions WHERE status = 'created')::bigint AS pending_interventions,
+    (SELECT MIN(created_at) FROM interventions WHERE status = 'created')::timestamptz AS oldest_pending_at,
+    (SELECT COUNT(*) FROM units WHERE status = 'available')::bigint AS available_units,
+    (SELECT COUNT(*) FROM intervention_assignments WHERE released_at IS NULL AND status IN ('dispatched', 'arrived'))::bigint AS active_assignments;
@@ -9,7 +9,8 @@ SELECT
     description, 
     min_value, 
     max_value, 
-    updated_at
+    updated_at,
+    default_value
 FROM dispatch_config
 ORDER BY key;
 
@@ -20,7 +21,8 @@ SELECT
     description, 
     min_value, 
     max_value, 
-    updated_at
+    updated_at,
+    default_value
 FROM dispatch_config
 WHERE key = $1;
 
@@ -30,7 +32,7 @@ SET
     value = $2,
     updated_at = NOW()
 WHERE key = $1
-RETURNING key, value, description, min_value, max_value, updated_at;
+RETURNING key, value, description, min_value, max_value, updated_at, default_value;
 
 -- name: BatchUpdateDispatchConfig :exec
 UPDATE dispatch_config
@@ -62,3 +64,22 @@ ORDER BY l.name;
 SELECT COALESCE(value, 1)::int AS minReserve
 FROM dispatch_config
 WHERE key = 'min_reserve_per_base';
+
+-- name: ResetDispatchConfigToDefaults :execrows
+UPDATE dispatch_config
+SET
+    value = default_value,
+    updated_at = NOW()
+WHERE value IS DISTINCT FROM default_value;
+
+-- name: InsertDispatchConfigHistory :exec
+INSERT INTO dispatch_config_history (config_key, old_value, new_value, actor)
+VALUES (sqlc.arg(config_key), sqlc.arg(old_value), sqlc.arg(new_value), sqlc.narg(actor));
+
+-- name: ListDispatchConfigHistory :many
+-- Fetch change history for a single dispatch config key, newest first
+SELECT id, config_key, old_value, new_value, actor, changed_at
+FROM dispatch_config_history
+WHERE config_key = sqlc.arg(config_key)
+ORDER BY changed_at DESC, id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
@@ -6,7 +6,8 @@ INSERT INTO events (
     address,
     location,
     severity,
-    event_type_code
+    event_type_code,
+    reported_at
 ) VALUES (
     sqlc.arg(title),
     sqlc.arg(description),
@@ -20,7 +21,8 @@ INSERT INTO events (
         4326
     )::geography,
     sqlc.arg(severity),
-    sqlc.arg(event_type_code)
+    sqlc.arg(event_type_code),
+    COALESCE(sqlc.narg(reported_at)::timestamptz, NOW())
 ) RETURNING
     id,
     title,
@@ -56,12 +58,153 @@ SELECT
     i.id AS intervention_id,
     i.status AS intervention_status,
     i.started_at AS intervention_started_at,
-    i.completed_at AS intervention_completed_at
+    i.completed_at AS intervention_completed_at,
+    e.version
+FROM events e
+JOIN event_types et ON et.code = e.event_type_code
+LEFT JOIN interventions i ON i.event_id = e.id
+WHERE (sqlc.arg(include_deleted)::boolean OR e.deleted_at IS NULL)
+ORDER BY e.reported_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListEventsAfter :many
+-- Keyset page of ListEvents: the events strictly older than the (reported_at, id) cursor
+SELECT
+    e.id,
+    e.title,
+    e.description,
+    e.report_source,
+    e.address,
+    ST_X(e.location::geometry)::double precision AS longitude,
+    ST_Y(e.location::geometry)::double precision AS latitude,
+    e.severity,
+    e.event_type_code,
+    et.name AS event_type_name,
+    et.default_severity,
+    e.auto_simulated,
+    e.reported_at,
+    e.updated_at,
+    e.closed_at,
+    i.id AS intervention_id,
+    i.status AS intervention_status,
+    i.started_at AS intervention_started_at,
+    i.completed_at AS intervention_completed_at,
+    e.version
+FROM events e
+JOIN event_types et ON et.code = e.event_type_code
+LEFT JOIN interventions i ON i.event_id = e.id
+WHERE (e.reported_at, e.id) < (sqlc.arg(cursor_reported_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+  AND (sqlc.arg(include_deleted)::boolean OR e.deleted_at IS NULL)
+ORDER BY e.reported_at DESC, e.id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: ListEventsFiltered :many
+-- Same columns as ListEvents; every filter is optional and they are ANDed. status is derived:
+-- open (not closed), acknowledged (open and acknowledged) or closed
+-- responded_by_type keeps events that had a unit of that type assigned
+SELECT
+    e.id,
+    e.title,
+    e.description,
+    e.report_source,
+    e.address,
+    ST_X(e.location::geometry)::double precision AS longitude,
+    ST_Y(e.location::geometry)::double precision AS latitude,
+    e.severity,
+    e.event_type_code,
+    et.name AS event_type_name,
+    et.default_severity,
+    e.auto_simulated,
+    e.reported_at,
+    e.updated_at,
+    e.closed_at,
+    i.id AS intervention_id,
+    i.status AS intervention_status,
+    i.started_at AS intervention_started_at,
+    i.completed_at AS intervention_completed_at,
+    e.version
+FROM events e
+JOIN event_types et ON et.code = e.event_type_code
+LEFT JOIN interventions i ON i.event_id = e.id
+WHERE (sqlc.narg(status)::text IS NULL
+       OR (sqlc.narg(status)::text = 'open' AND e.closed_at IS NULL)
+       OR (sqlc.narg(status)::text = 'acknowledged' AND e.closed_at IS NULL AND e.acknowledged_at IS NOT NULL)
+       OR (sqlc.narg(status)::text = 'closed' AND e.closed_at IS NOT NULL))
+  AND (sqlc.narg(min_severity)::int IS NULL OR e.severity >= sqlc.narg(min_severity)::int)
+  AND (sqlc.narg(max_severity)::int IS NULL OR e.severity <= sqlc.narg(max_severity)::int)
+  AND (COALESCE(cardinality(sqlc.arg(event_type_codes)::text[]), 0) = 0 OR e.event_type_code = ANY(sqlc.arg(event_type_codes)::text[]))
+  AND (sqlc.narg(responded_by_type)::text IS NULL OR EXISTS (
+        SELECT 1
+        FROM interventions ri
+        JOIN intervention_assignments ia ON ia.intervention_id = ri.id
+        JOIN units u ON u.id = ia.unit_id
+        WHERE ri.event_id = e.id AND u.unit_type_code = sqlc.narg(responded_by_type)::text))
+  AND (sqlc.arg(include_deleted)::boolean OR e.deleted_at IS NULL)
+ORDER BY e.reported_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: SearchEvents :many
+-- Same columns as ListEvents, matching title, description and address; the document expression
+-- must stay in sync with events_search_idx
+SELECT
+    e.id,
+    e.title,
+    e.description,
+    e.report_source,
+    e.address,
+    ST_X(e.location::geometry)::double precision AS longitude,
+    ST_Y(e.location::geometry)::double precision AS latitude,
+    e.severity,
+    e.event_type_code,
+    et.name AS event_type_name,
+    et.default_severity,
+    e.auto_simulated,
+    e.reported_at,
+    e.updated_at,
+    e.closed_at,
+    i.id AS intervention_id,
+    i.status AS intervention_status,
+    i.started_at AS intervention_started_at,
+    i.completed_at AS intervention_completed_at,
+    e.version
 FROM events e
 JOIN event_types et ON et.code = e.event_type_code
 LEFT JOIN interventions i ON i.event_id = e.id
+WHERE to_tsvector('simple'::regconfig, e.title || ' ' || COALESCE(e.description, '') || ' ' || COALESCE(e.address, '')) @@ plainto_tsquery('simple'::regconfig, sqlc.arg(query)::text)
+  AND (sqlc.arg(include_deleted)::boolean OR e.deleted_at IS NULL)
+ORDER BY ts_rank(to_tsvector('simple'::regconfig, e.title || ' ' || COALESCE(e.description, '') || ' ' || COALESCE(e.address, '')), plainto_tsquery('simple'::regconfig, sqlc.arg(query)::text)) DESC, e.reported_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListEventsInBounds :many
+-- Same columns as ListEvents, restricted to a lon/lat envelope; the && on location::geometry uses events_location_geom_idx
+SELECT
+    e.id,
+    e.title,
+    e.description,
+    e.report_source,
+    e.address,
+    ST_X(e.location::geometry)::double precision AS longitude,
+    ST_Y(e.location::geometry)::double precision AS latitude,
+    e.severity,
+    e.event_type_code,
+    et.name AS event_type_name,
+    et.default_severity,
+    e.auto_simulated,
+    e.reported_at,
+    e.updated_at,
+    e.closed_at,
+    i.id AS intervention_id,
+    i.status AS intervention_status,
+    i.started_at AS intervention_started_at,
+    i.completed_at AS intervention_completed_at,
+    e.version
+FROM events e
+JOIN event_types et ON et.code = e.event_type_code
+LEFT JOIN interventions i ON i.event_id = e.id
+WHERE e.location::geometry && ST_MakeEnvelope(sqlc.arg(min_lon)::double precision, sqlc.arg(min_lat)::double precision, sqlc.arg(max_lon)::double precision, sqlc.arg(max_lat)::double precision, 4326)
+  AND (sqlc.arg(include_deleted)::boolean OR e.deleted_at IS NULL)
 ORDER BY e.reported_at DESC
-LIMIT $1 OFFSET $2;
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: GetEvent :one
 SELECT
@@ -81,6 +224,10 @@ SELECT
     e.reported_at,
     e.updated_at,
     e.closed_at,
+    e.acknowledged_at,
+    e.acknowledged_by,
+    e.version,
+    e.deleted_at,
     i.id AS intervention_id,
     i.status AS intervention_status
 FROM events e
@@ -99,14 +246,185 @@ SELECT
     e.event_type_code,
     e.reported_at
 FROM events e
+WHERE e.deleted_at IS NULL
 ORDER BY e.reported_at DESC;
 
 -- name: UpdateEventAutoSimulated :one
 UPDATE events
-SET auto_simulated = $2,
+SET auto_simulated = sqlc.arg(auto_simulated),
     updated_at = NOW()
-WHERE id = $1
+WHERE id = sqlc.arg(id) AND (sqlc.narg(expected_version)::int IS NULL OR version = sqlc.narg(expected_version)::int)
 RETURNING
     id,
     auto_simulated,
-    updated_at;
+    updated_at,
+    version;
+
+-- name: CountEventsByTimeBucket :many
+-- Counts reported events per time bucket; empty buckets are returned with a zero count
+SELECT
+    b.bucket_start::timestamptz AS bucket_start,
+    COUNT(e.id)::bigint AS event_count
+FROM generate_series(
+    date_bin(sqlc.arg(bucket)::interval, sqlc.arg(from_time)::timestamptz, TIMESTAMPTZ '2000-01-01'),
+    sqlc.arg(to_time)::timestamptz - INTERVAL '1 microsecond',
+    sqlc.arg(bucket)::interval
+) AS b(bucket_start)
+LEFT JOIN events e
+    ON e.reported_at >= GREATEST(b.bucket_start, sqlc.arg(from_time)::timestamptz)
+    AND e.reported_at < LEAST(b.bucket_start + sqlc.arg(bucket)::interval, sqlc.arg(to_time)::timestamptz)
+    AND (sqlc.arg(include_deleted)::boolean OR e.deleted_at IS NULL)
+GROUP BY b.bucket_start
+ORDER BY b.bucket_start;
+
+-- name: CountEventsByTimeBucketAndCell :many
+-- Counts events in [from_time, to_time) per time bucket, aligned like CountEventsByTimeBucket,
+-- and per 0.001° grid cell, truncated like the Prometheus heatmap (through int so that no -0 cell
+-- appears); only non-empty cells are returned
+SELECT
+    date_bin(sqlc.arg(bucket)::interval, e.reported_at, TIMESTAMPTZ '2000-01-01')::timestamptz AS bucket_start,
+    (trunc(ST_Y(e.location::geometry) * 1000)::int / 1000.0)::double precision AS lat_cell,
+    (trunc(ST_X(e.location::geometry) * 1000)::int / 1000.0)::double precision AS lon_cell,
+    COUNT(*)::bigint AS event_count
+FROM events e
+WHERE e.reported_at >= sqlc.arg(from_time)::timestamptz
+  AND e.reported_at < sqlc.arg(to_time)::timestamptz
+  AND (sqlc.narg(min_lon)::double precision IS NULL OR ST_Intersects(
+      e.location::geometry,
+      ST_MakeEnvelope(sqlc.narg(min_lon)::double precision, sqlc.narg(min_lat)::double precision, sqlc.narg(max_lon)::double precision, sqlc.narg(max_lat)::double precision, 4326)
+  ))
+  AND (sqlc.arg(include_deleted)::boolean OR e.deleted_at IS NULL)
+GROUP BY bucket_start, lat_cell, lon_cell
+ORDER BY bucket_start, event_count DESC, lat_cell, lon_cell;
+
+-- name: CountEventsByTimeBucketAndType :many
+-- Same as CountEventsByTimeBucket, split by event type (every type appears in every bucket)
+SELECT
+    b.bucket_start::timestamptz AS bucket_start,
+    et.code AS event_type_code,
+    COUNT(e.id)::bigint AS event_count
+FROM generate_series(
+    date_bin(sqlc.arg(bucket)::interval, sqlc.arg(from_time)::timestamptz, TIMESTAMPTZ '2000-01-01'),
+    sqlc.arg(to_time)::timestamptz - INTERVAL '1 microsecond',
+    sqlc.arg(bucket)::interval
+) AS b(bucket_start)
+CROSS JOIN event_types et
+LEFT JOIN events e
+    ON e.event_type_code = et.code
+    AND e.reported_at >= GREATEST(b.bucket_start, sqlc.arg(from_time)::timestamptz)
+    AND e.reported_at < LEAST(b.bucket_start + sqlc.arg(bucket)::interval, sqlc.arg(to_time)::timestamptz)
+    AND (sqlc.arg(include_deleted)::boolean OR e.deleted_at IS NULL)
+GROUP BY b.bucket_start, et.code
+ORDER BY b.bucket_start, et.code;
+
+-- name: CloseEvent :one
+UPDATE events
+SET closed_at = COALESCE(closed_at, NOW()),
+    updated_at = NOW()
+WHERE id = $1
+RETURNING
+    id,
+    closed_at;
+
+-- name: LockEvent :one
+-- Row-locks an event so concurrent intervention creation checks are serialised
+SELECT id FROM events WHERE id = sqlc.arg(event_id) FOR UPDATE;
+
+-- name: AcknowledgeEvent :one
+-- Only open, not deleted and not yet acknowledged events are updated; no row means the caller must check why
+UPDATE events
+SET acknowledged_at = NOW(),
+    acknowledged_by = sqlc.narg(acknowledged_by),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND closed_at IS NULL
+  AND deleted_at IS NULL
+  AND acknowledged_at IS NULL
+RETURNING
+    id,
+    acknowledged_at;
+
+-- name: GetEventsExtent :one
+-- Convex hull and bounding box of event locations; open filters on closed_at IS NULL when set
+SELECT
+    COUNT(*)::bigint AS event_count,
+    COALESCE(ST_AsGeoJSON(ST_ConvexHull(ST_Collect(e.location::geometry)))::text, '')::text AS hull_geojson,
+    COALESCE(ST_XMin(ST_Extent(e.location::geometry)), 0)::double precision AS min_lon,
+    COALESCE(ST_YMin(ST_Extent(e.location::geometry)), 0)::double precision AS min_lat,
+    COALESCE(ST_XMax(ST_Extent(e.location::geometry)), 0)::double precision AS max_lon,
+    COALESCE(ST_YMax(ST_Extent(e.location::geometry)), 0)::double precision AS max_lat
+FROM events e
+WHERE (sqlc.narg(open)::boolean IS NULL OR (e.closed_at IS NULL) = sqlc.narg(open)::boolean)
+  AND (sqlc.arg(include_deleted)::boolean OR e.deleted_at IS NULL);
+
+-- name: UpdateEvent :one
+-- Applies only the non-null fields; the location changes only when both coordinates are given
+UPDATE events
+SET severity = COALESCE(sqlc.narg(severity)::int, severity),
+    location = CASE
+        WHEN sqlc.narg(longitude)::double precision IS NULL OR sqlc.narg(latitude)::double precision IS NULL THEN location
+        ELSE ST_SetSRID(ST_MakePoint(sqlc.narg(longitude)::double precision, sqlc.narg(latitude)::double precision), 4326)::geography
+    END,
+    address = COALESCE(sqlc.narg(address)::text, address),
+    description = COALESCE(sqlc.narg(description)::text, description),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id) AND (sqlc.narg(expected_version)::int IS NULL OR version = sqlc.narg(expected_version)::int)
+RETURNING
+    id,
+    updated_at,
+    version;
+
+-- name: SoftDeleteEvent :one
+-- Hides an event from the lists; deleting twice keeps the first deleted_at
+UPDATE events
+SET deleted_at = COALESCE(deleted_at, NOW()),
+    updated_at = NOW()
+WHERE id = $1
+RETURNING
+    id,
+    deleted_at;
+
+-- name: ListStaleOpenEvents :many
+-- Open events untouched since cutoff: no open intervention, no intervention created or completed
+-- since cutoff, and not already flagged stale since their last update
+SELECT
+    e.id,
+    e.title,
+    e.reported_at,
+    e.updated_at,
+    e.acknowledged_at
+FROM events e
+WHERE e.closed_at IS NULL
+  AND e.deleted_at IS NULL
+  AND e.updated_at < sqlc.arg(cutoff)::timestamptz
+  AND NOT EXISTS (
+      SELECT 1 FROM interventions i
+      WHERE i.event_id = e.id
+        AND (i.status IN ('created', 'on_site')
+             OR GREATEST(i.created_at, i.completed_at) >= sqlc.arg(cutoff)::timestamptz)
+  )
+  AND NOT EXISTS (
+      SELECT 1 FROM activity_logs al
+      WHERE al.activity_type = 'stale_event'
+        AND al.entity_type = 'event'
+        AND al.entity_id = e.id
+        AND al.created_at >= e.updated_at
+  )
+ORDER BY e.updated_at
+LIMIT sqlc.arg('limit');
+
+-- name: IsStaleOpenEvent :one
+-- Re-checks the ListStaleOpenEvents conditions for one event once it is locked
+SELECT EXISTS (
+    SELECT 1 FROM events e
+    WHERE e.id = sqlc.arg(event_id)
+      AND e.closed_at IS NULL
+      AND e.deleted_at IS NULL
+      AND e.updated_at < sqlc.arg(cutoff)::timestamptz
+      AND NOT EXISTS (
+          SELECT 1 FROM interventions i
+          WHERE i.event_id = e.id
+            AND (i.status IN ('created', 'on_site')
+                 OR GREATEST(i.created_at, i.completed_at) >= sqlc.arg(cutoff)::timestamptz)
+      )
+)::boolean AS stale;
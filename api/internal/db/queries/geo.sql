@@ -0,0 +1,34 @@
+-- =============================================================================
+-- Geo Queries - coordinate system helpers
+-- =============================================================================
+
+-- name: IsSupportedSRID :one
+-- Checks whether PostGIS knows the given spatial reference system
+SELECT EXISTS (
+    SELECT 1 FROM spatial_ref_sys WHERE srid = sqlc.arg(srid)::int
+) AS supported;
+
+-- name: TransformPoints :many
+-- Reprojects WGS84 lon/lat pairs to the target SRID, preserving input order
+SELECT
+    ST_X(pts.geom)::double precision AS x,
+    ST_Y(pts.geom)::double precision AS y
+FROM (
+    SELECT
+        t.ord,
+        ST_Transform(ST_SetSRID(ST_MakePoint(t.lon, t.lat), 4326), sqlc.arg(srid)::int) AS geom
+    FROM unnest(sqlc.arg(longitudes)::float8[], sqlc.arg(latitudes)::float8[]) WITH ORDINALITY AS t(lon, lat, ord)
+) pts
+ORDER BY pts.ord;
+
+-- name: TransformGeoJSON :one
+-- Reprojects a GeoJSON geometry between two SRIDs and returns its bounding box in the target SRID
+SELECT
+    ST_AsGeoJSON(g.geom)::text AS geojson,
+    ST_XMin(g.geom)::double precision AS min_x,
+    ST_YMin(g.geom)::double precision AS min_y,
+    ST_XMax(g.geom)::double precision AS max_x,
+    ST_YMax(g.geom)::double precision AS max_y
+FROM (
+    SELECT ST_Transform(ST_SetSRID(ST_GeomFromGeoJSON(sqlc.arg(geojson)::text), sqlc.arg(from_srid)::int), sqlc.arg(to_srid)::int) AS geom
+) g;
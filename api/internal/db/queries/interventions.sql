@@ -97,11 +97,12 @@ INSERT INTO intervention_assignments (
     released_at;
 
 -- name: UpdateAssignmentStatus :one
+-- arrived_at and released_at are set on the first transition only
 UPDATE intervention_assignments
 SET
     status = $2::assignment_status,
-    arrived_at = CASE WHEN $2::assignment_status = 'arrived' THEN NOW() ELSE arrived_at END,
-    released_at = CASE WHEN $2::assignment_status = 'released' THEN NOW() ELSE released_at END
+    arrived_at = CASE WHEN $2::assignment_status = 'arrived' THEN COALESCE(arrived_at, NOW()) ELSE arrived_at END,
+    released_at = CASE WHEN $2::assignment_status = 'released' THEN COALESCE(released_at, NOW()) ELSE released_at END
 WHERE id = $1
 RETURNING
     id,
@@ -113,6 +114,11 @@ RETURNING
     arrived_at,
     released_at;
 
+-- name: LockAssignment :one
+-- Row-locks an assignment and returns its timestamps before the update, so arrival
+-- and release are only observed once
+SELECT status, arrived_at, released_at FROM intervention_assignments WHERE id = $1 FOR UPDATE;
+
 -- name: ListAssignmentsByIntervention :many
 SELECT
     ia.id,
@@ -198,3 +204,121 @@ FROM interventions i
 JOIN events e ON e.id = i.event_id
 WHERE i.id = $1;
 
+
+-- name: CountActiveAssignments :one
+SELECT COUNT(*)::bigint AS active_count
+FROM intervention_assignments
+WHERE intervention_id = $1
+  AND released_at IS NULL
+  AND status IN ('dispatched', 'arrived');
+
+-- name: ListActiveAssignments :many
+SELECT
+    ia.id,
+    ia.intervention_id,
+    ia.unit_id,
+    ia.role,
+    ia.status,
+    ia.dispatched_at,
+    ia.arrived_at,
+    u.call_sign,
+    u.unit_type_code,
+    u.status AS unit_status,
+    i.status AS intervention_status,
+    i.event_id,
+    e.title AS event_title,
+    e.severity AS event_severity
+FROM intervention_assignments ia
+JOIN units u ON u.id = ia.unit_id
+JOIN interventions i ON i.id = ia.intervention_id
+JOIN events e ON e.id = i.event_id
+WHERE ia.released_at IS NULL
+  AND ia.status IN ('dispatched', 'arrived')
+  AND (sqlc.narg('unit_type')::text IS NULL OR u.unit_type_code = sqlc.narg('unit_type'))
+ORDER BY e.severity DESC, ia.dispatched_at ASC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: LockIntervention :one
+-- Row-locks an intervention so concurrent assignment checks are serialised
+SELECT status FROM interventions WHERE id = $1 FOR UPDATE;
+
+-- name: CountActiveAssignmentsWithRole :one
+SELECT COUNT(*)::bigint
+FROM intervention_assignments
+WHERE intervention_id = sqlc.arg(intervention_id)
+  AND lower(role) = lower(sqlc.arg(role)::text)
+  AND released_at IS NULL
+  AND status NOT IN ('released', 'cancelled');
+
+-- name: CountActiveAssignmentsForUnit :one
+SELECT COUNT(*)::bigint
+FROM intervention_assignments
+WHERE unit_id = sqlc.arg(unit_id)
+  AND released_at IS NULL
+  AND status NOT IN ('released', 'cancelled');
+
+-- name: CountOpenInterventionsForEvent :one
+SELECT COUNT(*)::bigint
+FROM interventions
+WHERE event_id = sqlc.arg(event_id)
+  AND status <> 'cancelled';
+
+-- name: ListInterventionsCreatedBetween :many
+-- Interventions created in [created_from, created_to), with event context, for shift reports
+SELECT
+    i.id,
+    i.event_id,
+    i.status,
+    i.priority,
+    i.decision_mode,
+    i.created_by,
+    i.notes,
+    i.created_at,
+    i.started_at,
+    i.completed_at,
+    e.title AS event_title,
+    e.event_type_code,
+    e.severity AS event_severity,
+    e.address AS event_address
+FROM interventions i
+JOIN events e ON e.id = i.event_id
+WHERE (sqlc.narg('created_from')::timestamptz IS NULL OR i.created_at >= sqlc.narg('created_from')::timestamptz)
+  AND (sqlc.narg('created_to')::timestamptz IS NULL OR i.created_at < sqlc.narg('created_to')::timestamptz)
+  AND (sqlc.narg('status')::intervention_status IS NULL OR i.status = sqlc.narg('status')::intervention_status)
+  AND (sqlc.narg('priority')::integer IS NULL OR i.priority = sqlc.narg('priority')::integer)
+ORDER BY i.created_at ASC, i.id ASC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListOverlappingInterventions :many
+-- Pairs of active interventions on different events reported within radius_meters and window of each other.
+-- Each pair is returned once, closest first; they are candidates for merging.
+SELECT
+    a.id AS intervention_id,
+    a.status AS intervention_status,
+    ea.id AS event_id,
+    ea.title AS event_title,
+    ea.event_type_code,
+    ea.severity AS event_severity,
+    ea.reported_at AS event_reported_at,
+    b.id AS other_intervention_id,
+    b.status AS other_intervention_status,
+    eb.id AS other_event_id,
+    eb.title AS other_event_title,
+    eb.event_type_code AS other_event_type_code,
+    eb.severity AS other_event_severity,
+    eb.reported_at AS other_event_reported_at,
+    ST_Distance(ea.location, eb.location)::double precision AS distance_meters,
+    ABS(EXTRACT(EPOCH FROM (ea.reported_at - eb.reported_at)))::double precision AS time_gap_seconds
+FROM interventions a
+JOIN events ea ON ea.id = a.event_id
+JOIN interventions b ON b.id > a.id
+JOIN events eb ON eb.id = b.event_id
+WHERE a.status IN ('created', 'on_site')
+  AND b.status IN ('created', 'on_site')
+  AND ea.id <> eb.id
+  AND ea.deleted_at IS NULL
+  AND eb.deleted_at IS NULL
+  AND ST_DWithin(ea.location, eb.location, sqlc.arg('radius_meters')::double precision)
+  AND eb.reported_at BETWEEN ea.reported_at - sqlc.arg('window')::interval AND ea.reported_at + sqlc.arg('window')::interval
+ORDER BY distance_meters ASC, time_gap_seconds ASC
+LIMIT sqlc.arg('limit');
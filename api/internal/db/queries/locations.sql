@@ -51,3 +51,37 @@ ORDER BY ST_Distance(
     ST_SetSRID(ST_MakePoint(sqlc.arg(longitude)::double precision, sqlc.arg(latitude)::double precision), 4326)::geography
 ) ASC
 LIMIT 1;
+
+-- name: ListStationCoverage :many
+-- Lists every station with its unit counts, including stations without units (for rebalancing)
+SELECT
+    l.id,
+    l.name,
+    (COALESCE(ST_X(l.location::geometry)::double precision, 0::double precision))::double precision AS longitude,
+    (COALESCE(ST_Y(l.location::geometry)::double precision, 0::double precision))::double precision AS latitude,
+    COUNT(u.id) FILTER (WHERE u.status = 'available')::bigint AS available_units,
+    COUNT(u.id)::bigint AS total_units
+FROM locations l
+LEFT JOIN units u ON u.location_id = l.id
+WHERE l.type = 'station'
+GROUP BY l.id, l.name, l.location
+ORDER BY l.name;
+
+-- name: ListBaseResponseTimes :many
+-- Dispatch-to-arrival travel times per home base for assignments dispatched in [from_time, to_time).
+-- Units are grouped by their current home base.
+SELECT
+    l.id,
+    l.name,
+    COUNT(*)::bigint AS arrived_assignments,
+    AVG(EXTRACT(EPOCH FROM (ia.arrived_at - ia.dispatched_at)))::double precision AS avg_travel_seconds,
+    (percentile_cont(0.9) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM (ia.arrived_at - ia.dispatched_at))))::double precision AS p90_travel_seconds
+FROM intervention_assignments ia
+JOIN units u ON u.id = ia.unit_id
+JOIN locations l ON l.id = u.location_id
+WHERE l.type = 'station'
+  AND ia.arrived_at IS NOT NULL
+  AND ia.dispatched_at >= sqlc.arg(from_time)
+  AND ia.dispatched_at < sqlc.arg(to_time)
+GROUP BY l.id, l.name
+ORDER BY p90_travel_seconds DESC, l.name;
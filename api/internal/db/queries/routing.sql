@@ -19,6 +19,7 @@ ON CONFLICT (unit_id) DO UPDATE SET
     route_length_meters = EXCLUDED.route_length_meters,
     estimated_duration_seconds = EXCLUDED.estimated_duration_seconds,
     progress_percent = 0,
+    calculated_at = NOW(),
     updated_at = NOW()
 RETURNING 
     unit_id,
@@ -134,3 +135,177 @@ ORDER BY ia.dispatched_at DESC
 LIMIT 1;
 
 
+-- name: ResetUnitRoute :one
+-- Deletes a unit's route and returns what was cleared (intervention link and progress)
+DELETE FROM unit_routes
+WHERE unit_id = sqlc.arg(unit_id)
+RETURNING unit_id, intervention_id, progress_percent;
+
+-- name: ListStaleAssignmentRoutes :many
+-- Finds routes of units still heading to an intervention whose geometry is older than max_age,
+-- leaving out skip_unit_ids
+SELECT
+    ur.unit_id,
+    ur.intervention_id,
+    ur.calculated_at
+FROM unit_routes ur
+JOIN intervention_assignments ia
+    ON ia.unit_id = ur.unit_id
+    AND ia.intervention_id = ur.intervention_id
+    AND ia.released_at IS NULL
+    AND ia.status = 'dispatched'
+WHERE ur.calculated_at < NOW() - sqlc.arg(max_age)::interval
+  AND NOT (ur.unit_id = ANY(sqlc.arg(skip_unit_ids)::uuid[]))
+ORDER BY ur.calculated_at ASC
+LIMIT sqlc.arg(max_routes);
+
+-- name: SnapPositionToUnitRoute :one
+-- Projects a raw position onto the unit's route and advances progress when the position
+-- is within max_distance_meters of the route. Progress never moves backwards so GPS noise
+-- cannot pull the unit back. Returns no row when the unit has no route or is too far from it.
+UPDATE unit_routes ur
+SET
+    progress_percent = GREATEST(ur.progress_percent, LEAST(100, snap.fraction * 100)),
+    updated_at = NOW()
+FROM (
+    SELECT
+        unit_id,
+        ST_LineLocatePoint(route_geometry, ST_SetSRID(ST_MakePoint(sqlc.arg(longitude)::float8, sqlc.arg(latitude)::float8), 4326)) AS fraction,
+        ST_Distance(route_geometry::geography, ST_SetSRID(ST_MakePoint(sqlc.arg(longitude)::float8, sqlc.arg(latitude)::float8), 4326)::geography) AS distance_meters
+    FROM unit_routes
+    WHERE unit_id = sqlc.arg(unit_id)
+) AS snap
+WHERE ur.unit_id = snap.unit_id
+    AND snap.distance_meters <= sqlc.arg(max_distance_meters)::float8
+RETURNING
+    ur.progress_percent,
+    ST_X(ST_LineInterpolatePoint(ur.route_geometry, ur.progress_percent / 100.0))::float8 AS snapped_lon,
+    ST_Y(ST_LineInterpolatePoint(ur.route_geometry, ur.progress_percent / 100.0))::float8 AS snapped_lat,
+    snap.distance_meters::float8 AS distance_meters;
+
+-- name: ArchiveUnitRoute :execrows
+-- Copies a unit's intervention route into unit_route_history before it is removed or replaced
+INSERT INTO unit_route_history (
+    assignment_id,
+    unit_id,
+    intervention_id,
+    route_geometry,
+    route_length_meters,
+    estimated_duration_seconds,
+    progress_percent,
+    calculated_at
+)
+SELECT
+    (
+        SELECT ia.id
+        FROM intervention_assignments ia
+        WHERE ia.unit_id = ur.unit_id AND ia.intervention_id = ur.intervention_id
+        ORDER BY ia.dispatched_at DESC
+        LIMIT 1
+    ),
+    ur.unit_id,
+    ur.intervention_id,
+    ur.route_geometry,
+    ur.route_length_meters,
+    ur.estimated_duration_seconds,
+    ur.progress_percent,
+    ur.calculated_at
+FROM unit_routes ur
+WHERE ur.unit_id = sqlc.arg(unit_id)
+  AND ur.intervention_id IS NOT NULL;
+
+-- name: ListAssignmentRouteHistory :many
+-- Archived routes of an assignment, oldest first
+SELECT
+    id,
+    assignment_id,
+    unit_id,
+    intervention_id,
+    ST_AsGeoJSON(route_geometry)::text AS route_geojson,
+    route_length_meters,
+    estimated_duration_seconds,
+    progress_percent,
+    calculated_at,
+    archived_at
+FROM unit_route_history
+WHERE assignment_id = sqlc.arg(assignment_id)
+ORDER BY archived_at;
+
+-- name: PruneUnitRouteHistory :execrows
+-- Deletes archived routes older than the retention cutoff
+DELETE FROM unit_route_history
+WHERE archived_at < sqlc.arg(archived_before);
+
+-- name: GetCachedRoute :one
+-- Unexpired cached route between two rounded point pairs
+SELECT
+    route_geojson,
+    route_length_meters,
+    estimated_duration_seconds,
+    already_at_destination
+FROM route_cache
+WHERE from_lat_key = sqlc.arg(from_lat_key)
+  AND from_lon_key = sqlc.arg(from_lon_key)
+  AND to_lat_key = sqlc.arg(to_lat_key)
+  AND to_lon_key = sqlc.arg(to_lon_key)
+  AND expires_at > NOW();
+
+-- name: UpsertCachedRoute :exec
+-- Stores a calculated route, replacing any previous entry for the same rounded point pair
+INSERT INTO route_cache (
+    from_lat_key,
+    from_lon_key,
+    to_lat_key,
+    to_lon_key,
+    route_geojson,
+    route_length_meters,
+    estimated_duration_seconds,
+    already_at_destination,
+    calculated_at,
+    expires_at
+) VALUES (
+    sqlc.arg(from_lat_key),
+    sqlc.arg(from_lon_key),
+    sqlc.arg(to_lat_key),
+    sqlc.arg(to_lon_key),
+    sqlc.arg(route_geojson),
+    sqlc.arg(route_length_meters),
+    sqlc.arg(estimated_duration_seconds),
+    sqlc.arg(already_at_destination),
+    NOW(),
+    NOW() + sqlc.arg(ttl)::interval
+)
+ON CONFLICT (from_lat_key, from_lon_key, to_lat_key, to_lon_key) DO UPDATE
+SET route_geojson = EXCLUDED.route_geojson,
+    route_length_meters = EXCLUDED.route_length_meters,
+    estimated_duration_seconds = EXCLUDED.estimated_duration_seconds,
+    already_at_destination = EXCLUDED.already_at_destination,
+    calculated_at = EXCLUDED.calculated_at,
+    expires_at = EXCLUDED.expires_at;
+
+-- name: PruneRouteCache :execrows
+-- Deletes expired cached routes
+DELETE FROM route_cache
+WHERE expires_at <= NOW();
+
+-- name: GetUnitRouteDeviation :one
+-- Distance from a position to the unit's active intervention route; no row when the unit has none
+SELECT
+    ur.intervention_id,
+    u.call_sign,
+    ST_Distance(ur.route_geometry::geography, ST_SetSRID(ST_MakePoint(sqlc.arg(longitude)::float8, sqlc.arg(latitude)::float8), 4326)::geography)::float8 AS deviation_meters
+FROM unit_routes ur
+JOIN units u ON u.id = ur.unit_id
+WHERE ur.unit_id = sqlc.arg(unit_id)
+    AND ur.intervention_id IS NOT NULL;
+
+-- name: SnapPointToUnitRoute :one
+-- Nearest point of the unit's route to a position and the progress it implies, without updating the route
+SELECT
+    ST_X(ST_ClosestPoint(route_geometry, ST_SetSRID(ST_MakePoint(sqlc.arg(longitude)::float8, sqlc.arg(latitude)::float8), 4326)))::float8 AS snapped_lon,
+    ST_Y(ST_ClosestPoint(route_geometry, ST_SetSRID(ST_MakePoint(sqlc.arg(longitude)::float8, sqlc.arg(latitude)::float8), 4326)))::float8 AS snapped_lat,
+    LEAST(100, ST_LineLocatePoint(route_geometry, ST_SetSRID(ST_MakePoint(sqlc.arg(longitude)::float8, sqlc.arg(latitude)::float8), 4326)) * 100)::float8 AS progress_percent,
+    ST_Distance(route_geometry::geography, ST_SetSRID(ST_MakePoint(sqlc.arg(longitude)::float8, sqlc.arg(latitude)::float8), 4326)::geography)::float8 AS distance_meters,
+    progress_percent AS current_progress_percent
+FROM unit_routes
+WHERE unit_id = sqlc.arg(unit_id);
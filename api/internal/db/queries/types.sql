@@ -6,7 +6,8 @@ SELECT
     default_severity,
     recommended_unit_types,
     created_at,
-    updated_at
+    updated_at,
+    recommended_unit_counts
 FROM event_types
 ORDER BY default_severity DESC, name;
 
@@ -22,3 +23,8 @@ SELECT
     updated_at
 FROM unit_types
 ORDER BY name;
+
+-- name: GetEventTypeDefaultSeverity :one
+SELECT default_severity
+FROM event_types
+WHERE code = $1;
@@ -11,7 +11,8 @@ SELECT
     (COALESCE(ST_Y(u.location::geometry)::double precision, 0::double precision))::double precision AS latitude,
     u.last_contact_at,
     u.created_at,
-    u.updated_at
+    u.updated_at,
+    u.version
 FROM units u
 LEFT JOIN locations l ON u.location_id = l.id
 ORDER BY u.call_sign;
@@ -55,6 +56,7 @@ WHERE u.status != 'available_hidden'
 ORDER BY u.call_sign;
 
 -- name: ListAvailableUnitsNearby :many
+-- Units closest first; statuses defaults to available and available_hidden, radius_meters to no limit
 SELECT
     u.id,
     u.call_sign,
@@ -71,8 +73,10 @@ SELECT
     ST_Distance(u.location, ST_SetSRID(ST_MakePoint(sqlc.arg(longitude)::double precision, sqlc.arg(latitude)::double precision), 4326)::geography)::double precision AS distance
 FROM units u
 LEFT JOIN locations l ON u.location_id = l.id
-WHERE (u.status = 'available' OR u.status = 'available_hidden')
+WHERE (sqlc.narg(statuses)::text[] IS NULL OR u.status::text = ANY(sqlc.narg(statuses)::text[]))
+AND (sqlc.narg(statuses)::text[] IS NOT NULL OR u.status = 'available' OR u.status = 'available_hidden')
 AND (sqlc.narg(unit_types)::text[] IS NULL OR u.unit_type_code = ANY(sqlc.narg(unit_types)::text[]))
+AND (sqlc.narg(radius_meters)::double precision IS NULL OR ST_DWithin(u.location, ST_SetSRID(ST_MakePoint(sqlc.arg(longitude)::double precision, sqlc.arg(latitude)::double precision), 4326)::geography, sqlc.narg(radius_meters)::double precision))
 ORDER BY distance ASC;
 
 -- name: CreateUnit :one
@@ -130,11 +134,16 @@ SELECT
     (COALESCE(ST_Y(u.location::geometry)::double precision, 0::double precision))::double precision AS latitude,
     u.last_contact_at,
     u.created_at,
-    u.updated_at
+    u.updated_at,
+    u.version
 FROM units u
 LEFT JOIN locations l ON u.location_id = l.id
 WHERE u.id = $1;
 
+-- name: LockUnit :one
+-- Row-locks a unit so concurrent status transitions are serialised
+SELECT status FROM units WHERE id = $1 FOR UPDATE;
+
 -- name: DeleteUnitAssignments :exec
 DELETE FROM intervention_assignments WHERE unit_id = $1;
 
@@ -147,9 +156,9 @@ DELETE FROM units WHERE id = $1;
 -- name: UpdateUnitStatus :one
 UPDATE units
 SET
-    status = $2,
+    status = sqlc.arg(status),
     updated_at = NOW()
-WHERE units.id = $1
+WHERE units.id = sqlc.arg(id) AND (sqlc.narg(expected_version)::int IS NULL OR units.version = sqlc.narg(expected_version)::int)
 RETURNING
     id,
     call_sign,
@@ -162,7 +171,8 @@ RETURNING
     (COALESCE(ST_Y(location::geometry)::double precision, 0::double precision))::double precision AS latitude,
     last_contact_at,
     created_at,
-    updated_at;
+    updated_at,
+    version;
 
 -- name: UpdateUnitLocation :one
 UPDATE units
@@ -176,7 +186,7 @@ SET
     )::geography,
     last_contact_at = COALESCE(sqlc.arg(contact_time), NOW()),
     updated_at = NOW()
-WHERE units.id = sqlc.arg(id)
+WHERE units.id = sqlc.arg(id) AND (sqlc.narg(expected_version)::int IS NULL OR units.version = sqlc.narg(expected_version)::int)
 RETURNING
     id,
     call_sign,
@@ -189,7 +199,8 @@ RETURNING
     (COALESCE(ST_Y(location::geometry)::double precision, 0::double precision))::double precision AS latitude,
     last_contact_at,
     created_at,
-    updated_at;
+    updated_at,
+    version;
 
 -- name: AssignMicrobit :one
 UPDATE units
@@ -331,7 +342,7 @@ UPDATE units
 SET
     location_id = sqlc.narg(location_id),
     updated_at = NOW()
-WHERE units.id = sqlc.arg(id)
+WHERE units.id = sqlc.arg(id) AND (sqlc.narg(expected_version)::int IS NULL OR units.version = sqlc.narg(expected_version)::int)
 RETURNING
     id,
     call_sign,
@@ -344,4 +355,169 @@ RETURNING
     (COALESCE(ST_Y(location::geometry)::double precision, 0::double precision))::double precision AS latitude,
     last_contact_at,
     created_at,
-    updated_at;
+    updated_at,
+    version;
+
+-- name: ListUnitsNearPoint :many
+SELECT
+    u.id,
+    u.call_sign,
+    u.unit_type_code,
+    u.status,
+    ST_X(u.location::geometry)::double precision AS longitude,
+    ST_Y(u.location::geometry)::double precision AS latitude,
+    ST_Distance(u.location, ST_SetSRID(ST_MakePoint(sqlc.arg(longitude)::double precision, sqlc.arg(latitude)::double precision), 4326)::geography)::double precision AS distance_meters
+FROM units u
+WHERE u.location IS NOT NULL
+  AND (sqlc.narg(unit_types)::text[] IS NULL OR u.unit_type_code = ANY(sqlc.narg(unit_types)::text[]))
+  AND u.status::text = ANY(sqlc.arg(statuses)::text[])
+ORDER BY distance_meters ASC
+LIMIT sqlc.arg(max_units);
+
+-- name: ListUnitTelemetryGaps :many
+-- Intervals longer than threshold without telemetry in [from_time, to_time); the range bounds count as samples so leading and trailing silences are reported too
+WITH samples AS (
+    SELECT ut.recorded_at
+    FROM unit_telemetry ut
+    WHERE ut.unit_id = sqlc.arg(unit_id)
+      AND ut.recorded_at >= sqlc.arg(from_time)::timestamptz
+      AND ut.recorded_at < sqlc.arg(to_time)::timestamptz
+    UNION ALL SELECT sqlc.arg(from_time)::timestamptz
+    UNION ALL SELECT sqlc.arg(to_time)::timestamptz
+),
+ordered AS (
+    SELECT
+        LAG(recorded_at) OVER (ORDER BY recorded_at) AS gap_start,
+        recorded_at AS gap_end
+    FROM samples
+)
+SELECT
+    gap_start::timestamptz AS gap_start,
+    gap_end::timestamptz AS gap_end,
+    EXTRACT(EPOCH FROM gap_end - gap_start)::double precision AS gap_seconds
+FROM ordered
+WHERE gap_start IS NOT NULL
+  AND gap_end - gap_start > sqlc.arg(threshold)::interval
+ORDER BY gap_start
+LIMIT sqlc.arg(max_gaps);
+
+-- name: CountUnitsByStatus :many
+SELECT
+    u.status,
+    u.unit_type_code,
+    COUNT(*)::bigint AS unit_count
+FROM units u
+GROUP BY u.status, u.unit_type_code
+ORDER BY u.status, u.unit_type_code;
+
+-- name: ListUnitStatusValues :many
+-- Values of the unit_status enum, used to check UNIT_STATUSES at startup
+SELECT unnest(enum_range(NULL::unit_status))::text AS status;
+
+-- name: ListUnitTelemetry :many
+-- Telemetry snapshots of a unit, newest first; since and until are optional bounds on recorded_at
+SELECT
+    id,
+    unit_id,
+    recorded_at,
+    (COALESCE(ST_X(location::geometry)::double precision, 0::double precision))::double precision AS longitude,
+    (COALESCE(ST_Y(location::geometry)::double precision, 0::double precision))::double precision AS latitude,
+    heading,
+    speed_kmh,
+    status_snapshot
+FROM unit_telemetry
+WHERE unit_id = sqlc.arg(unit_id)
+  AND (sqlc.narg(since)::timestamptz IS NULL OR recorded_at >= sqlc.narg(since)::timestamptz)
+  AND (sqlc.narg(until)::timestamptz IS NULL OR recorded_at < sqlc.narg(until)::timestamptz)
+ORDER BY recorded_at DESC, id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: GetUnitTrackLine :one
+-- Telemetry positions of a unit in [since, until) joined in time order; line_geojson is null
+-- when there are fewer than two points
+SELECT
+    COUNT(*)::bigint AS point_count,
+    (CASE WHEN COUNT(*) >= 2
+        THEN ST_AsGeoJSON(ST_MakeLine(location::geometry ORDER BY recorded_at, id))
+    END)::text AS line_geojson
+FROM unit_telemetry
+WHERE unit_id = sqlc.arg(unit_id)
+  AND recorded_at >= sqlc.arg(since)
+  AND recorded_at < sqlc.arg(until);
+
+-- name: ListUnitTrackPoints :many
+-- Telemetry points of a unit in [since, until), oldest first
+SELECT
+    recorded_at,
+    (COALESCE(ST_X(location::geometry)::double precision, 0::double precision))::double precision AS longitude,
+    (COALESCE(ST_Y(location::geometry)::double precision, 0::double precision))::double precision AS latitude,
+    heading,
+    speed_kmh
+FROM unit_telemetry
+WHERE unit_id = sqlc.arg(unit_id)
+  AND recorded_at >= sqlc.arg(since)
+  AND recorded_at < sqlc.arg(until)
+ORDER BY recorded_at, id
+LIMIT sqlc.arg('limit');
+
+-- name: UpdateUnitLocationsBatch :batchone
+-- Same update as UpdateUnitLocation, queued once per unit by PATCH /v1/units/locations
+UPDATE units
+SET
+    location = ST_SetSRID(
+        ST_MakePoint(
+            sqlc.arg(longitude)::double precision,
+            sqlc.arg(latitude)::double precision
+        ),
+        4326
+    )::geography,
+    last_contact_at = COALESCE(sqlc.arg(contact_time), NOW()),
+    updated_at = NOW()
+WHERE units.id = sqlc.arg(id) AND (sqlc.narg(expected_version)::int IS NULL OR units.version = sqlc.narg(expected_version)::int)
+RETURNING
+    id,
+    version;
+
+-- name: ListUnitsAwayFromBase :many
+-- Available units farther than min_distance meters from their home base, farthest first
+SELECT
+    u.id,
+    u.call_sign,
+    u.unit_type_code,
+    u.status,
+    u.microbit_id,
+    u.location_id,
+    l.name AS home_base_name,
+    (COALESCE(ST_X(u.location::geometry)::double precision, 0::double precision))::double precision AS longitude,
+    (COALESCE(ST_Y(u.location::geometry)::double precision, 0::double precision))::double precision AS latitude,
+    (ST_X(l.location::geometry))::double precision AS home_base_longitude,
+    (ST_Y(l.location::geometry))::double precision AS home_base_latitude,
+    u.last_contact_at,
+    u.created_at,
+    u.updated_at,
+    ST_Distance(u.location, l.location)::double precision AS distance_from_base
+FROM units u
+JOIN locations l ON u.location_id = l.id
+WHERE u.status IN ('available', 'available_hidden')
+  AND u.location IS NOT NULL
+  AND NOT ST_DWithin(u.location, l.location, sqlc.arg(min_distance)::double precision)
+ORDER BY distance_from_base DESC, u.call_sign;
+
+-- name: ListUnitTypeObservedSpeeds :many
+-- Average telemetry speed per unit type for readings in [from_time, to_time) faster than min_speed_kmh.
+-- Units are grouped by their current unit type.
+SELECT
+    ut.code AS unit_type_code,
+    ut.speed_kmh AS configured_speed_kmh,
+    COUNT(DISTINCT t.unit_id)::bigint AS unit_count,
+    COUNT(*)::bigint AS moving_readings,
+    AVG(t.speed_kmh)::double precision AS avg_speed_kmh
+FROM unit_telemetry t
+JOIN units u ON u.id = t.unit_id
+JOIN unit_types ut ON ut.code = u.unit_type_code
+WHERE t.recorded_at >= sqlc.arg(from_time)
+  AND t.recorded_at < sqlc.arg(to_time)
+  AND t.speed_kmh > sqlc.arg(min_speed_kmh)::double precision
+  AND (sqlc.narg(unit_type)::text IS NULL OR ut.code = sqlc.narg(unit_type)::text)
+GROUP BY ut.code, ut.speed_kmh
+ORDER BY ut.code;
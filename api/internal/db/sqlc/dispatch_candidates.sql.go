@@ -11,6 +11,65 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const getDispatchHealth = `-- name: GetDispatchHealth :one
+SELECT
+    (SELECT COUNT(*) FROM interventions WHERE status = 'created')::bigint AS pending_interventions,
+    (SELECT MIN(created_at) FROM interventions WHERE status = 'created')::timestamptz AS oldest_pending_at,
+    (SELECT COUNT(*) FROM units WHERE status = 'available')::bigint AS available_units,
+    (SELECT COUNT(*) FROM intervention_assignments WHERE released_at IS NULL AND status IN ('dispatched', 'arrived'))::bigint AS active_assignments
+`
+
+type GetDispatchHealthRow struct {
+	PendingInterventions int64              `json:"pending_interventions"`
+	OldestPendingAt      pgtype.Timestamptz `json:"oldest_pending_at"`
+	AvailableUnits       int64              `json:"available_units"`
+	ActiveAssignments    int64              `json:"active_assignments"`
+}
+
+// Aggregated dispatch health counters for the wall display
+func (q *Queries) GetDispatchHealth(ctx context.Context) (GetDispatchHealthRow, error) {
+	row := q.db.QueryRow(ctx, getDispatchHealth)
+	var i GetDispatchHealthRow
+	err := row.Scan(
+		&i.PendingInterventions,
+		&i.OldestPendingAt,
+		&i.AvailableUnits,
+		&i.ActiveAssignments,
+	)
+	return i, err
+}
+
+const getEventStaffingNeeds = `-- name: GetEventStaffingNeeds :one
+SELECT
+    e.id AS event_id,
+    e.event_type_code,
+    et.recommended_unit_types,
+    et.recommended_unit_counts
+FROM events e
+JOIN event_types et ON e.event_type_code = et.code
+WHERE e.id = $1
+`
+
+type GetEventStaffingNeedsRow struct {
+	EventID               pgtype.UUID `json:"event_id"`
+	EventTypeCode         string      `json:"event_type_code"`
+	RecommendedUnitTypes  []string    `json:"recommended_unit_types"`
+	RecommendedUnitCounts []byte      `json:"recommended_unit_counts"`
+}
+
+// Recommended unit types and counts of an event's type
+func (q *Queries) GetEventStaffingNeeds(ctx context.Context, id pgtype.UUID) (GetEventStaffingNeedsRow, error) {
+	row := q.db.QueryRow(ctx, getEventStaffingNeeds, id)
+	var i GetEventStaffingNeedsRow
+	err := row.Scan(
+		&i.EventID,
+		&i.EventTypeCode,
+		&i.RecommendedUnitTypes,
+		&i.RecommendedUnitCounts,
+	)
+	return i, err
+}
+
 const getInterventionForDispatch = `-- name: GetInterventionForDispatch :one
 
 SELECT 
@@ -23,6 +82,7 @@ SELECT
     e.severity AS event_severity,
     e.event_type_code,
     et.recommended_unit_types,
+    et.recommended_unit_counts,
     (ST_X(e.location::geometry))::double precision AS longitude,
     (ST_Y(e.location::geometry))::double precision AS latitude
 FROM interventions i
@@ -32,17 +92,18 @@ WHERE i.id = $1
 `
 
 type GetInterventionForDispatchRow struct {
-	InterventionID       pgtype.UUID        `json:"intervention_id"`
-	EventID              pgtype.UUID        `json:"event_id"`
-	InterventionStatus   InterventionStatus `json:"intervention_status"`
-	Priority             int32              `json:"priority"`
-	DecisionMode         DecisionMode       `json:"decision_mode"`
-	EventTitle           string             `json:"event_title"`
-	EventSeverity        int32              `json:"event_severity"`
-	EventTypeCode        string             `json:"event_type_code"`
-	RecommendedUnitTypes []string           `json:"recommended_unit_types"`
-	Longitude            float64            `json:"longitude"`
-	Latitude             float64            `json:"latitude"`
+	InterventionID        pgtype.UUID        `json:"intervention_id"`
+	EventID               pgtype.UUID        `json:"event_id"`
+	InterventionStatus    InterventionStatus `json:"intervention_status"`
+	Priority              int32              `json:"priority"`
+	DecisionMode          DecisionMode       `json:"decision_mode"`
+	EventTitle            string             `json:"event_title"`
+	EventSeverity         int32              `json:"event_severity"`
+	EventTypeCode         string             `json:"event_type_code"`
+	RecommendedUnitTypes  []string           `json:"recommended_unit_types"`
+	RecommendedUnitCounts []byte             `json:"recommended_unit_counts"`
+	Longitude             float64            `json:"longitude"`
+	Latitude              float64            `json:"latitude"`
 }
 
 // =============================================================================
@@ -63,6 +124,7 @@ func (q *Queries) GetInterventionForDispatch(ctx context.Context, id pgtype.UUID
 		&i.EventSeverity,
 		&i.EventTypeCode,
 		&i.RecommendedUnitTypes,
+		&i.RecommendedUnitCounts,
 		&i.Longitude,
 		&i.Latitude,
 	)
@@ -90,6 +152,75 @@ func (q *Queries) GetUnitsAtBase(ctx context.Context, locationID pgtype.UUID) (G
 	return i, err
 }
 
+const listAssignmentsMissingRoute = `-- name: ListAssignmentsMissingRoute :many
+SELECT
+    ia.id AS assignment_id,
+    ia.intervention_id,
+    i.event_id,
+    i.status AS intervention_status,
+    ia.unit_id,
+    u.call_sign,
+    u.unit_type_code,
+    u.status AS unit_status,
+    e.severity AS event_severity,
+    ia.dispatched_at
+FROM intervention_assignments ia
+JOIN interventions i ON i.id = ia.intervention_id
+JOIN events e ON e.id = i.event_id
+JOIN units u ON u.id = ia.unit_id
+LEFT JOIN unit_routes ur ON ur.unit_id = ia.unit_id
+WHERE ia.released_at IS NULL
+  AND ia.status = 'dispatched'
+  AND ur.unit_id IS NULL
+ORDER BY e.severity DESC, ia.dispatched_at ASC
+`
+
+type ListAssignmentsMissingRouteRow struct {
+	AssignmentID       pgtype.UUID        `json:"assignment_id"`
+	InterventionID     pgtype.UUID        `json:"intervention_id"`
+	EventID            pgtype.UUID        `json:"event_id"`
+	InterventionStatus InterventionStatus `json:"intervention_status"`
+	UnitID             pgtype.UUID        `json:"unit_id"`
+	CallSign           string             `json:"call_sign"`
+	UnitTypeCode       string             `json:"unit_type_code"`
+	UnitStatus         UnitStatus         `json:"unit_status"`
+	EventSeverity      int32              `json:"event_severity"`
+	DispatchedAt       pgtype.Timestamptz `json:"dispatched_at"`
+}
+
+// Lists active dispatched assignments whose unit has no stored route,
+// i.e. route calculation failed after the unit was assigned
+func (q *Queries) ListAssignmentsMissingRoute(ctx context.Context) ([]ListAssignmentsMissingRouteRow, error) {
+	rows, err := q.db.Query(ctx, listAssignmentsMissingRoute)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAssignmentsMissingRouteRow
+	for rows.Next() {
+		var i ListAssignmentsMissingRouteRow
+		if err := rows.Scan(
+			&i.AssignmentID,
+			&i.InterventionID,
+			&i.EventID,
+			&i.InterventionStatus,
+			&i.UnitID,
+			&i.CallSign,
+			&i.UnitTypeCode,
+			&i.UnitStatus,
+			&i.EventSeverity,
+			&i.DispatchedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listDispatchCandidates = `-- name: ListDispatchCandidates :many
 SELECT 
     u.id,
@@ -113,10 +244,7 @@ SELECT
 FROM units u
 LEFT JOIN locations l ON u.location_id = l.id
 CROSS JOIN (
-    SELECT ev.location
-    FROM interventions iv
-    JOIN events ev ON iv.event_id = ev.id
-    WHERE iv.id = $1
+    SELECT ev.location FROM events ev WHERE ev.id = $1
 ) e
 LEFT JOIN intervention_assignments a 
     ON a.unit_id = u.id AND a.status = 'dispatched'
@@ -132,9 +260,9 @@ LIMIT $3::int
 `
 
 type ListDispatchCandidatesParams struct {
-	InterventionID pgtype.UUID `json:"intervention_id"`
-	UnitTypes      []string    `json:"unit_types"`
-	MaxCandidates  int32       `json:"max_candidates"`
+	EventID       pgtype.UUID `json:"event_id"`
+	UnitTypes     []string    `json:"unit_types"`
+	MaxCandidates int32       `json:"max_candidates"`
 }
 
 type ListDispatchCandidatesRow struct {
@@ -158,7 +286,7 @@ type ListDispatchCandidatesRow struct {
 // Returns units sorted by estimated travel time, includes current assignment info for preemption
 // Note: For precise routing, use the dedicated routing endpoint
 func (q *Queries) ListDispatchCandidates(ctx context.Context, arg ListDispatchCandidatesParams) ([]ListDispatchCandidatesRow, error) {
-	rows, err := q.db.Query(ctx, listDispatchCandidates, arg.InterventionID, arg.UnitTypes, arg.MaxCandidates)
+	rows, err := q.db.Query(ctx, listDispatchCandidates, arg.EventID, arg.UnitTypes, arg.MaxCandidates)
 	if err != nil {
 		return nil, err
 	}
@@ -192,6 +320,83 @@ func (q *Queries) ListDispatchCandidates(ctx context.Context, arg ListDispatchCa
 	return items, nil
 }
 
+const listNearestAvailableUnitsByType = `-- name: ListNearestAvailableUnitsByType :many
+SELECT DISTINCT ON (u.unit_type_code)
+    u.id,
+    u.call_sign,
+    u.unit_type_code,
+    l.name AS home_base_name,
+    u.status,
+    (ST_X(u.location::geometry))::double precision AS longitude,
+    (ST_Y(u.location::geometry))::double precision AS latitude,
+    (ST_Distance(u.location, e.location) / 13.89)::double precision AS travel_time_seconds,
+    ST_Distance(u.location, e.location)::double precision AS distance_meters,
+    (SELECT COUNT(*) FROM units u2 
+     WHERE u2.location_id = u.location_id 
+       AND u2.status = 'available' 
+       AND u2.id != u.id)::int AS other_units_at_base
+FROM units u
+LEFT JOIN locations l ON u.location_id = l.id
+CROSS JOIN (
+    SELECT ev.location FROM events ev WHERE ev.id = $1
+) e
+WHERE u.status IN ('available', 'available_hidden')
+  AND u.location IS NOT NULL
+  AND u.unit_type_code = ANY($2::text[])
+ORDER BY u.unit_type_code, ST_Distance(u.location, e.location) ASC
+`
+
+type ListNearestAvailableUnitsByTypeParams struct {
+	EventID   pgtype.UUID `json:"event_id"`
+	UnitTypes []string    `json:"unit_types"`
+}
+
+type ListNearestAvailableUnitsByTypeRow struct {
+	ID                pgtype.UUID `json:"id"`
+	CallSign          string      `json:"call_sign"`
+	UnitTypeCode      string      `json:"unit_type_code"`
+	HomeBaseName      *string     `json:"home_base_name"`
+	Status            UnitStatus  `json:"status"`
+	Longitude         float64     `json:"longitude"`
+	Latitude          float64     `json:"latitude"`
+	TravelTimeSeconds float64     `json:"travel_time_seconds"`
+	DistanceMeters    float64     `json:"distance_meters"`
+	OtherUnitsAtBase  int32       `json:"other_units_at_base"`
+}
+
+// Nearest available unit of each requested type to an event
+// Uses the same distance-based travel time estimate as ListDispatchCandidates
+func (q *Queries) ListNearestAvailableUnitsByType(ctx context.Context, arg ListNearestAvailableUnitsByTypeParams) ([]ListNearestAvailableUnitsByTypeRow, error) {
+	rows, err := q.db.Query(ctx, listNearestAvailableUnitsByType, arg.EventID, arg.UnitTypes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListNearestAvailableUnitsByTypeRow
+	for rows.Next() {
+		var i ListNearestAvailableUnitsByTypeRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CallSign,
+			&i.UnitTypeCode,
+			&i.HomeBaseName,
+			&i.Status,
+			&i.Longitude,
+			&i.Latitude,
+			&i.TravelTimeSeconds,
+			&i.DistanceMeters,
+			&i.OtherUnitsAtBase,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listPendingInterventions = `-- name: ListPendingInterventions :many
 SELECT 
     i.id AS intervention_id,
@@ -210,7 +415,10 @@ JOIN events e ON i.event_id = e.id
 JOIN event_types et ON e.event_type_code = et.code
 WHERE i.status = 'created'
   AND e.auto_simulated = true
-ORDER BY e.severity DESC, i.created_at ASC
+  AND (NOT $1::boolean OR NOT EXISTS (
+      SELECT 1 FROM intervention_assignments ia WHERE ia.intervention_id = i.id AND ia.status IN ('dispatched', 'arrived')
+  ))
+ORDER BY assigned_units_count ASC, event_severity DESC, i.priority DESC, i.created_at ASC
 `
 
 type ListPendingInterventionsRow struct {
@@ -227,9 +435,10 @@ type ListPendingInterventionsRow struct {
 	AssignedUnitsCount   int64              `json:"assigned_units_count"`
 }
 
-// Lists interventions awaiting dispatch, ordered by severity and age
-func (q *Queries) ListPendingInterventions(ctx context.Context) ([]ListPendingInterventionsRow, error) {
-	rows, err := q.db.Query(ctx, listPendingInterventions)
+// Lists interventions awaiting dispatch, uncovered ones first, then by severity, priority and age;
+// only_uncovered keeps the interventions without an active assignment
+func (q *Queries) ListPendingInterventions(ctx context.Context, onlyUncovered bool) ([]ListPendingInterventionsRow, error) {
+	rows, err := q.db.Query(ctx, listPendingInterventions, onlyUncovered)
 	if err != nil {
 		return nil, err
 	}
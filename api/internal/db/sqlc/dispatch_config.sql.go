@@ -52,7 +52,8 @@ SELECT
     description, 
     min_value, 
     max_value, 
-    updated_at
+    updated_at,
+    default_value
 FROM dispatch_config
 WHERE key = $1
 `
@@ -67,10 +68,33 @@ func (q *Queries) GetDispatchConfigValue(ctx context.Context, key string) (Dispa
 		&i.MinValue,
 		&i.MaxValue,
 		&i.UpdatedAt,
+		&i.DefaultValue,
 	)
 	return i, err
 }
 
+const insertDispatchConfigHistory = `-- name: InsertDispatchConfigHistory :exec
+INSERT INTO dispatch_config_history (config_key, old_value, new_value, actor)
+VALUES ($1, $2, $3, $4)
+`
+
+type InsertDispatchConfigHistoryParams struct {
+	ConfigKey string         `json:"config_key"`
+	OldValue  pgtype.Numeric `json:"old_value"`
+	NewValue  pgtype.Numeric `json:"new_value"`
+	Actor     *string        `json:"actor"`
+}
+
+func (q *Queries) InsertDispatchConfigHistory(ctx context.Context, arg InsertDispatchConfigHistoryParams) error {
+	_, err := q.db.Exec(ctx, insertDispatchConfigHistory,
+		arg.ConfigKey,
+		arg.OldValue,
+		arg.NewValue,
+		arg.Actor,
+	)
+	return err
+}
+
 const listBases = `-- name: ListBases :many
 
 SELECT DISTINCT 
@@ -128,7 +152,8 @@ SELECT
     description, 
     min_value, 
     max_value, 
-    updated_at
+    updated_at,
+    default_value
 FROM dispatch_config
 ORDER BY key
 `
@@ -152,6 +177,49 @@ func (q *Queries) ListDispatchConfig(ctx context.Context) ([]DispatchConfig, err
 			&i.MinValue,
 			&i.MaxValue,
 			&i.UpdatedAt,
+			&i.DefaultValue,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDispatchConfigHistory = `-- name: ListDispatchConfigHistory :many
+SELECT id, config_key, old_value, new_value, actor, changed_at
+FROM dispatch_config_history
+WHERE config_key = $1
+ORDER BY changed_at DESC, id DESC
+LIMIT $2 OFFSET $3
+`
+
+type ListDispatchConfigHistoryParams struct {
+	ConfigKey string `json:"config_key"`
+	Limit     int32  `json:"limit"`
+	Offset    int32  `json:"offset"`
+}
+
+// Fetch change history for a single dispatch config key, newest first
+func (q *Queries) ListDispatchConfigHistory(ctx context.Context, arg ListDispatchConfigHistoryParams) ([]DispatchConfigHistory, error) {
+	rows, err := q.db.Query(ctx, listDispatchConfigHistory, arg.ConfigKey, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DispatchConfigHistory
+	for rows.Next() {
+		var i DispatchConfigHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.ConfigKey,
+			&i.OldValue,
+			&i.NewValue,
+			&i.Actor,
+			&i.ChangedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -163,13 +231,29 @@ func (q *Queries) ListDispatchConfig(ctx context.Context) ([]DispatchConfig, err
 	return items, nil
 }
 
+const resetDispatchConfigToDefaults = `-- name: ResetDispatchConfigToDefaults :execrows
+UPDATE dispatch_config
+SET
+    value = default_value,
+    updated_at = NOW()
+WHERE value IS DISTINCT FROM default_value
+`
+
+func (q *Queries) ResetDispatchConfigToDefaults(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, resetDispatchConfigToDefaults)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updateDispatchConfigValue = `-- name: UpdateDispatchConfigValue :one
 UPDATE dispatch_config
 SET 
     value = $2,
     updated_at = NOW()
 WHERE key = $1
-RETURNING key, value, description, min_value, max_value, updated_at
+RETURNING key, value, description, min_value, max_value, updated_at, default_value
 `
 
 type UpdateDispatchConfigValueParams struct {
@@ -187,6 +271,7 @@ func (q *Queries) UpdateDispatchConfigValue(ctx context.Context, arg UpdateDispa
 		&i.MinValue,
 		&i.MaxValue,
 		&i.UpdatedAt,
+		&i.DefaultValue,
 	)
 	return i, err
 }
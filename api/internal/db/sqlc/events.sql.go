@@ -11,6 +11,260 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const acknowledgeEvent = `-- name: AcknowledgeEvent :one
+UPDATE events
+SET acknowledged_at = NOW(),
+    acknowledged_by = $1,
+    updated_at = NOW()
+WHERE id = $2
+  AND closed_at IS NULL
+  AND deleted_at IS NULL
+  AND acknowledged_at IS NULL
+RETURNING
+    id,
+    acknowledged_at
+`
+
+type AcknowledgeEventParams struct {
+	AcknowledgedBy *string     `json:"acknowledged_by"`
+	ID             pgtype.UUID `json:"id"`
+}
+
+type AcknowledgeEventRow struct {
+	ID             pgtype.UUID        `json:"id"`
+	AcknowledgedAt pgtype.Timestamptz `json:"acknowledged_at"`
+}
+
+// Only open, not deleted and not yet acknowledged events are updated; no row means the caller must check why
+func (q *Queries) AcknowledgeEvent(ctx context.Context, arg AcknowledgeEventParams) (AcknowledgeEventRow, error) {
+	row := q.db.QueryRow(ctx, acknowledgeEvent, arg.AcknowledgedBy, arg.ID)
+	var i AcknowledgeEventRow
+	err := row.Scan(
+		&i.ID,
+		&i.AcknowledgedAt,
+	)
+	return i, err
+}
+
+const closeEvent = `-- name: CloseEvent :one
+UPDATE events
+SET closed_at = COALESCE(closed_at, NOW()),
+    updated_at = NOW()
+WHERE id = $1
+RETURNING
+    id,
+    closed_at
+`
+
+type CloseEventRow struct {
+	ID       pgtype.UUID        `json:"id"`
+	ClosedAt pgtype.Timestamptz `json:"closed_at"`
+}
+
+func (q *Queries) CloseEvent(ctx context.Context, id pgtype.UUID) (CloseEventRow, error) {
+	row := q.db.QueryRow(ctx, closeEvent, id)
+	var i CloseEventRow
+	err := row.Scan(
+		&i.ID,
+		&i.ClosedAt,
+	)
+	return i, err
+}
+
+const countEventsByTimeBucket = `-- name: CountEventsByTimeBucket :many
+SELECT
+    b.bucket_start::timestamptz AS bucket_start,
+    COUNT(e.id)::bigint AS event_count
+FROM generate_series(
+    date_bin($1::interval, $2::timestamptz, TIMESTAMPTZ '2000-01-01'),
+    $3::timestamptz - INTERVAL '1 microsecond',
+    $1::interval
+) AS b(bucket_start)
+LEFT JOIN events e
+    ON e.reported_at >= GREATEST(b.bucket_start, $2::timestamptz)
+    AND e.reported_at < LEAST(b.bucket_start + $1::interval, $3::timestamptz)
+    AND ($4::boolean OR e.deleted_at IS NULL)
+GROUP BY b.bucket_start
+ORDER BY b.bucket_start
+`
+
+type CountEventsByTimeBucketParams struct {
+	Bucket         pgtype.Interval    `json:"bucket"`
+	FromTime       pgtype.Timestamptz `json:"from_time"`
+	ToTime         pgtype.Timestamptz `json:"to_time"`
+	IncludeDeleted bool               `json:"include_deleted"`
+}
+
+type CountEventsByTimeBucketRow struct {
+	BucketStart pgtype.Timestamptz `json:"bucket_start"`
+	EventCount  int64              `json:"event_count"`
+}
+
+// Counts reported events per time bucket; empty buckets are returned with a zero count
+func (q *Queries) CountEventsByTimeBucket(ctx context.Context, arg CountEventsByTimeBucketParams) ([]CountEventsByTimeBucketRow, error) {
+	rows, err := q.db.Query(ctx, countEventsByTimeBucket,
+		arg.Bucket,
+		arg.FromTime,
+		arg.ToTime,
+		arg.IncludeDeleted,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountEventsByTimeBucketRow
+	for rows.Next() {
+		var i CountEventsByTimeBucketRow
+		if err := rows.Scan(
+			&i.BucketStart,
+			&i.EventCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countEventsByTimeBucketAndCell = `-- name: CountEventsByTimeBucketAndCell :many
+SELECT
+    date_bin($1::interval, e.reported_at, TIMESTAMPTZ '2000-01-01')::timestamptz AS bucket_start,
+    (trunc(ST_Y(e.location::geometry) * 1000)::int / 1000.0)::double precision AS lat_cell,
+    (trunc(ST_X(e.location::geometry) * 1000)::int / 1000.0)::double precision AS lon_cell,
+    COUNT(*)::bigint AS event_count
+FROM events e
+WHERE e.reported_at >= $2::timestamptz
+  AND e.reported_at < $3::timestamptz
+  AND ($4::double precision IS NULL OR ST_Intersects(
+      e.location::geometry,
+      ST_MakeEnvelope($4::double precision, $5::double precision, $6::double precision, $7::double precision, 4326)
+  ))
+  AND ($8::boolean OR e.deleted_at IS NULL)
+GROUP BY bucket_start, lat_cell, lon_cell
+ORDER BY bucket_start, event_count DESC, lat_cell, lon_cell
+`
+
+type CountEventsByTimeBucketAndCellParams struct {
+	Bucket         pgtype.Interval    `json:"bucket"`
+	FromTime       pgtype.Timestamptz `json:"from_time"`
+	ToTime         pgtype.Timestamptz `json:"to_time"`
+	MinLon         *float64           `json:"min_lon"`
+	MinLat         *float64           `json:"min_lat"`
+	MaxLon         *float64           `json:"max_lon"`
+	MaxLat         *float64           `json:"max_lat"`
+	IncludeDeleted bool               `json:"include_deleted"`
+}
+
+type CountEventsByTimeBucketAndCellRow struct {
+	BucketStart pgtype.Timestamptz `json:"bucket_start"`
+	LatCell     float64            `json:"lat_cell"`
+	LonCell     float64            `json:"lon_cell"`
+	EventCount  int64              `json:"event_count"`
+}
+
+// Counts events in [from_time, to_time) per time bucket, aligned like CountEventsByTimeBucket,
+// and per 0.001° grid cell, truncated like the Prometheus heatmap (through int so that no -0 cell
+// appears); only non-empty cells are returned
+func (q *Queries) CountEventsByTimeBucketAndCell(ctx context.Context, arg CountEventsByTimeBucketAndCellParams) ([]CountEventsByTimeBucketAndCellRow, error) {
+	rows, err := q.db.Query(ctx, countEventsByTimeBucketAndCell,
+		arg.Bucket,
+		arg.FromTime,
+		arg.ToTime,
+		arg.MinLon,
+		arg.MinLat,
+		arg.MaxLon,
+		arg.MaxLat,
+		arg.IncludeDeleted,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountEventsByTimeBucketAndCellRow
+	for rows.Next() {
+		var i CountEventsByTimeBucketAndCellRow
+		if err := rows.Scan(
+			&i.BucketStart,
+			&i.LatCell,
+			&i.LonCell,
+			&i.EventCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countEventsByTimeBucketAndType = `-- name: CountEventsByTimeBucketAndType :many
+SELECT
+    b.bucket_start::timestamptz AS bucket_start,
+    et.code AS event_type_code,
+    COUNT(e.id)::bigint AS event_count
+FROM generate_series(
+    date_bin($1::interval, $2::timestamptz, TIMESTAMPTZ '2000-01-01'),
+    $3::timestamptz - INTERVAL '1 microsecond',
+    $1::interval
+) AS b(bucket_start)
+CROSS JOIN event_types et
+LEFT JOIN events e
+    ON e.event_type_code = et.code
+    AND e.reported_at >= GREATEST(b.bucket_start, $2::timestamptz)
+    AND e.reported_at < LEAST(b.bucket_start + $1::interval, $3::timestamptz)
+    AND ($4::boolean OR e.deleted_at IS NULL)
+GROUP BY b.bucket_start, et.code
+ORDER BY b.bucket_start, et.code
+`
+
+type CountEventsByTimeBucketAndTypeParams struct {
+	Bucket         pgtype.Interval    `json:"bucket"`
+	FromTime       pgtype.Timestamptz `json:"from_time"`
+	ToTime         pgtype.Timestamptz `json:"to_time"`
+	IncludeDeleted bool               `json:"include_deleted"`
+}
+
+type CountEventsByTimeBucketAndTypeRow struct {
+	BucketStart   pgtype.Timestamptz `json:"bucket_start"`
+	EventTypeCode string             `json:"event_type_code"`
+	EventCount    int64              `json:"event_count"`
+}
+
+// Same as CountEventsByTimeBucket, split by event type (every type appears in every bucket)
+func (q *Queries) CountEventsByTimeBucketAndType(ctx context.Context, arg CountEventsByTimeBucketAndTypeParams) ([]CountEventsByTimeBucketAndTypeRow, error) {
+	rows, err := q.db.Query(ctx, countEventsByTimeBucketAndType,
+		arg.Bucket,
+		arg.FromTime,
+		arg.ToTime,
+		arg.IncludeDeleted,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountEventsByTimeBucketAndTypeRow
+	for rows.Next() {
+		var i CountEventsByTimeBucketAndTypeRow
+		if err := rows.Scan(
+			&i.BucketStart,
+			&i.EventTypeCode,
+			&i.EventCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const createEvent = `-- name: CreateEvent :one
 INSERT INTO events (
     title,
@@ -19,7 +273,8 @@ INSERT INTO events (
     address,
     location,
     severity,
-    event_type_code
+    event_type_code,
+    reported_at
 ) VALUES (
     $1,
     $2,
@@ -33,7 +288,8 @@ INSERT INTO events (
         4326
     )::geography,
     $7,
-    $8
+    $8,
+    COALESCE($9::timestamptz, NOW())
 ) RETURNING
     id,
     title,
@@ -51,14 +307,15 @@ INSERT INTO events (
 `
 
 type CreateEventParams struct {
-	Title         string  `json:"title"`
-	Description   *string `json:"description"`
-	ReportSource  *string `json:"report_source"`
-	Address       *string `json:"address"`
-	Longitude     float64 `json:"longitude"`
-	Latitude      float64 `json:"latitude"`
-	Severity      int32   `json:"severity"`
-	EventTypeCode string  `json:"event_type_code"`
+	Title         string             `json:"title"`
+	Description   *string            `json:"description"`
+	ReportSource  *string            `json:"report_source"`
+	Address       *string            `json:"address"`
+	Longitude     float64            `json:"longitude"`
+	Latitude      float64            `json:"latitude"`
+	Severity      int32              `json:"severity"`
+	EventTypeCode string             `json:"event_type_code"`
+	ReportedAt    pgtype.Timestamptz `json:"reported_at"`
 }
 
 type CreateEventRow struct {
@@ -87,6 +344,7 @@ func (q *Queries) CreateEvent(ctx context.Context, arg CreateEventParams) (Creat
 		arg.Latitude,
 		arg.Severity,
 		arg.EventTypeCode,
+		arg.ReportedAt,
 	)
 	var i CreateEventRow
 	err := row.Scan(
@@ -116,6 +374,7 @@ SELECT
     e.event_type_code,
     e.reported_at
 FROM events e
+WHERE e.deleted_at IS NULL
 ORDER BY e.reported_at DESC
 `
 
@@ -173,6 +432,10 @@ SELECT
     e.reported_at,
     e.updated_at,
     e.closed_at,
+    e.acknowledged_at,
+    e.acknowledged_by,
+    e.version,
+    e.deleted_at,
     i.id AS intervention_id,
     i.status AS intervention_status
 FROM events e
@@ -198,6 +461,10 @@ type GetEventRow struct {
 	ReportedAt           pgtype.Timestamptz     `json:"reported_at"`
 	UpdatedAt            pgtype.Timestamptz     `json:"updated_at"`
 	ClosedAt             pgtype.Timestamptz     `json:"closed_at"`
+	AcknowledgedAt       pgtype.Timestamptz     `json:"acknowledged_at"`
+	AcknowledgedBy       *string                `json:"acknowledged_by"`
+	Version              int32                  `json:"version"`
+	DeletedAt            pgtype.Timestamptz     `json:"deleted_at"`
 	InterventionID       pgtype.UUID            `json:"intervention_id"`
 	InterventionStatus   NullInterventionStatus `json:"intervention_status"`
 }
@@ -222,12 +489,87 @@ func (q *Queries) GetEvent(ctx context.Context, id pgtype.UUID) (GetEventRow, er
 		&i.ReportedAt,
 		&i.UpdatedAt,
 		&i.ClosedAt,
+		&i.AcknowledgedAt,
+		&i.AcknowledgedBy,
+		&i.Version,
+		&i.DeletedAt,
 		&i.InterventionID,
 		&i.InterventionStatus,
 	)
 	return i, err
 }
 
+const getEventsExtent = `-- name: GetEventsExtent :one
+SELECT
+    COUNT(*)::bigint AS event_count,
+    COALESCE(ST_AsGeoJSON(ST_ConvexHull(ST_Collect(e.location::geometry)))::text, '')::text AS hull_geojson,
+    COALESCE(ST_XMin(ST_Extent(e.location::geometry)), 0)::double precision AS min_lon,
+    COALESCE(ST_YMin(ST_Extent(e.location::geometry)), 0)::double precision AS min_lat,
+    COALESCE(ST_XMax(ST_Extent(e.location::geometry)), 0)::double precision AS max_lon,
+    COALESCE(ST_YMax(ST_Extent(e.location::geometry)), 0)::double precision AS max_lat
+FROM events e
+WHERE ($1::boolean IS NULL OR (e.closed_at IS NULL) = $1::boolean)
+  AND ($2::boolean OR e.deleted_at IS NULL)
+`
+
+type GetEventsExtentParams struct {
+	Open           *bool `json:"open"`
+	IncludeDeleted bool  `json:"include_deleted"`
+}
+
+type GetEventsExtentRow struct {
+	EventCount  int64   `json:"event_count"`
+	HullGeojson string  `json:"hull_geojson"`
+	MinLon      float64 `json:"min_lon"`
+	MinLat      float64 `json:"min_lat"`
+	MaxLon      float64 `json:"max_lon"`
+	MaxLat      float64 `json:"max_lat"`
+}
+
+// Convex hull and bounding box of event locations; open filters on closed_at IS NULL when set
+func (q *Queries) GetEventsExtent(ctx context.Context, arg GetEventsExtentParams) (GetEventsExtentRow, error) {
+	row := q.db.QueryRow(ctx, getEventsExtent, arg.Open, arg.IncludeDeleted)
+	var i GetEventsExtentRow
+	err := row.Scan(
+		&i.EventCount,
+		&i.HullGeojson,
+		&i.MinLon,
+		&i.MinLat,
+		&i.MaxLon,
+		&i.MaxLat,
+	)
+	return i, err
+}
+
+const isStaleOpenEvent = `-- name: IsStaleOpenEvent :one
+SELECT EXISTS (
+    SELECT 1 FROM events e
+    WHERE e.id = $1
+      AND e.closed_at IS NULL
+      AND e.deleted_at IS NULL
+      AND e.updated_at < $2::timestamptz
+      AND NOT EXISTS (
+          SELECT 1 FROM interventions i
+          WHERE i.event_id = e.id
+            AND (i.status IN ('created', 'on_site')
+                 OR GREATEST(i.created_at, i.completed_at) >= $2::timestamptz)
+      )
+)::boolean AS stale
+`
+
+type IsStaleOpenEventParams struct {
+	EventID pgtype.UUID        `json:"event_id"`
+	Cutoff  pgtype.Timestamptz `json:"cutoff"`
+}
+
+// Re-checks the ListStaleOpenEvents conditions for one event once it is locked
+func (q *Queries) IsStaleOpenEvent(ctx context.Context, arg IsStaleOpenEventParams) (bool, error) {
+	row := q.db.QueryRow(ctx, isStaleOpenEvent, arg.EventID, arg.Cutoff)
+	var stale bool
+	err := row.Scan(&stale)
+	return stale, err
+}
+
 const listEvents = `-- name: ListEvents :many
 SELECT
     e.id,
@@ -248,17 +590,20 @@ SELECT
     i.id AS intervention_id,
     i.status AS intervention_status,
     i.started_at AS intervention_started_at,
-    i.completed_at AS intervention_completed_at
+    i.completed_at AS intervention_completed_at,
+    e.version
 FROM events e
 JOIN event_types et ON et.code = e.event_type_code
 LEFT JOIN interventions i ON i.event_id = e.id
+WHERE ($1::boolean OR e.deleted_at IS NULL)
 ORDER BY e.reported_at DESC
-LIMIT $1 OFFSET $2
+LIMIT $2 OFFSET $3
 `
 
 type ListEventsParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+	IncludeDeleted bool  `json:"include_deleted"`
+	Limit          int32 `json:"limit"`
+	Offset         int32 `json:"offset"`
 }
 
 type ListEventsRow struct {
@@ -281,10 +626,11 @@ type ListEventsRow struct {
 	InterventionStatus      NullInterventionStatus `json:"intervention_status"`
 	InterventionStartedAt   pgtype.Timestamptz     `json:"intervention_started_at"`
 	InterventionCompletedAt pgtype.Timestamptz     `json:"intervention_completed_at"`
+	Version                 int32                  `json:"version"`
 }
 
 func (q *Queries) ListEvents(ctx context.Context, arg ListEventsParams) ([]ListEventsRow, error) {
-	rows, err := q.db.Query(ctx, listEvents, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, listEvents, arg.IncludeDeleted, arg.Limit, arg.Offset)
 	if err != nil {
 		return nil, err
 	}
@@ -312,6 +658,7 @@ func (q *Queries) ListEvents(ctx context.Context, arg ListEventsParams) ([]ListE
 			&i.InterventionStatus,
 			&i.InterventionStartedAt,
 			&i.InterventionCompletedAt,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -323,31 +670,660 @@ func (q *Queries) ListEvents(ctx context.Context, arg ListEventsParams) ([]ListE
 	return items, nil
 }
 
-const updateEventAutoSimulated = `-- name: UpdateEventAutoSimulated :one
-UPDATE events
-SET auto_simulated = $2,
-    updated_at = NOW()
-WHERE id = $1
-RETURNING
-    id,
-    auto_simulated,
-    updated_at
+const listEventsAfter = `-- name: ListEventsAfter :many
+SELECT
+    e.id,
+    e.title,
+    e.description,
+    e.report_source,
+    e.address,
+    ST_X(e.location::geometry)::double precision AS longitude,
+    ST_Y(e.location::geometry)::double precision AS latitude,
+    e.severity,
+    e.event_type_code,
+    et.name AS event_type_name,
+    et.default_severity,
+    e.auto_simulated,
+    e.reported_at,
+    e.updated_at,
+    e.closed_at,
+    i.id AS intervention_id,
+    i.status AS intervention_status,
+    i.started_at AS intervention_started_at,
+    i.completed_at AS intervention_completed_at,
+    e.version
+FROM events e
+JOIN event_types et ON et.code = e.event_type_code
+LEFT JOIN interventions i ON i.event_id = e.id
+WHERE (e.reported_at, e.id) < ($1::timestamptz, $2::uuid)
+  AND ($3::boolean OR e.deleted_at IS NULL)
+ORDER BY e.reported_at DESC, e.id DESC
+LIMIT $4
 `
 
-type UpdateEventAutoSimulatedParams struct {
-	ID            pgtype.UUID `json:"id"`
-	AutoSimulated bool        `json:"auto_simulated"`
+type ListEventsAfterParams struct {
+	CursorReportedAt pgtype.Timestamptz `json:"cursor_reported_at"`
+	CursorID         pgtype.UUID        `json:"cursor_id"`
+	IncludeDeleted   bool               `json:"include_deleted"`
+	Limit            int32              `json:"limit"`
 }
 
-type UpdateEventAutoSimulatedRow struct {
-	ID            pgtype.UUID        `json:"id"`
-	AutoSimulated bool               `json:"auto_simulated"`
-	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+type ListEventsAfterRow struct {
+	ID                      pgtype.UUID            `json:"id"`
+	Title                   string                 `json:"title"`
+	Description             *string                `json:"description"`
+	ReportSource            *string                `json:"report_source"`
+	Address                 *string                `json:"address"`
+	Longitude               float64                `json:"longitude"`
+	Latitude                float64                `json:"latitude"`
+	Severity                int32                  `json:"severity"`
+	EventTypeCode           string                 `json:"event_type_code"`
+	EventTypeName           string                 `json:"event_type_name"`
+	DefaultSeverity         int32                  `json:"default_severity"`
+	AutoSimulated           bool                   `json:"auto_simulated"`
+	ReportedAt              pgtype.Timestamptz     `json:"reported_at"`
+	UpdatedAt               pgtype.Timestamptz     `json:"updated_at"`
+	ClosedAt                pgtype.Timestamptz     `json:"closed_at"`
+	InterventionID          pgtype.UUID            `json:"intervention_id"`
+	InterventionStatus      NullInterventionStatus `json:"intervention_status"`
+	InterventionStartedAt   pgtype.Timestamptz     `json:"intervention_started_at"`
+	InterventionCompletedAt pgtype.Timestamptz     `json:"intervention_completed_at"`
+	Version                 int32                  `json:"version"`
 }
 
-func (q *Queries) UpdateEventAutoSimulated(ctx context.Context, arg UpdateEventAutoSimulatedParams) (UpdateEventAutoSimulatedRow, error) {
-	row := q.db.QueryRow(ctx, updateEventAutoSimulated, arg.ID, arg.AutoSimulated)
-	var i UpdateEventAutoSimulatedRow
-	err := row.Scan(&i.ID, &i.AutoSimulated, &i.UpdatedAt)
+// Keyset page of ListEvents: the events strictly older than the (reported_at, id) cursor
+func (q *Queries) ListEventsAfter(ctx context.Context, arg ListEventsAfterParams) ([]ListEventsAfterRow, error) {
+	rows, err := q.db.Query(ctx, listEventsAfter,
+		arg.CursorReportedAt,
+		arg.CursorID,
+		arg.IncludeDeleted,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListEventsAfterRow
+	for rows.Next() {
+		var i ListEventsAfterRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.ReportSource,
+			&i.Address,
+			&i.Longitude,
+			&i.Latitude,
+			&i.Severity,
+			&i.EventTypeCode,
+			&i.EventTypeName,
+			&i.DefaultSeverity,
+			&i.AutoSimulated,
+			&i.ReportedAt,
+			&i.UpdatedAt,
+			&i.ClosedAt,
+			&i.InterventionID,
+			&i.InterventionStatus,
+			&i.InterventionStartedAt,
+			&i.InterventionCompletedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEventsFiltered = `-- name: ListEventsFiltered :many
+SELECT
+    e.id,
+    e.title,
+    e.description,
+    e.report_source,
+    e.address,
+    ST_X(e.location::geometry)::double precision AS longitude,
+    ST_Y(e.location::geometry)::double precision AS latitude,
+    e.severity,
+    e.event_type_code,
+    et.name AS event_type_name,
+    et.default_severity,
+    e.auto_simulated,
+    e.reported_at,
+    e.updated_at,
+    e.closed_at,
+    i.id AS intervention_id,
+    i.status AS intervention_status,
+    i.started_at AS intervention_started_at,
+    i.completed_at AS intervention_completed_at,
+    e.version
+FROM events e
+JOIN event_types et ON et.code = e.event_type_code
+LEFT JOIN interventions i ON i.event_id = e.id
+WHERE ($1::text IS NULL
+       OR ($1::text = 'open' AND e.closed_at IS NULL)
+       OR ($1::text = 'acknowledged' AND e.closed_at IS NULL AND e.acknowledged_at IS NOT NULL)
+       OR ($1::text = 'closed' AND e.closed_at IS NOT NULL))
+  AND ($2::int IS NULL OR e.severity >= $2::int)
+  AND ($3::int IS NULL OR e.severity <= $3::int)
+  AND (COALESCE(cardinality($4::text[]), 0) = 0 OR e.event_type_code = ANY($4::text[]))
+  AND ($5::text IS NULL OR EXISTS (
+        SELECT 1
+        FROM interventions ri
+        JOIN intervention_assignments ia ON ia.intervention_id = ri.id
+        JOIN units u ON u.id = ia.unit_id
+        WHERE ri.event_id = e.id AND u.unit_type_code = $5::text))
+  AND ($6::boolean OR e.deleted_at IS NULL)
+ORDER BY e.reported_at DESC
+LIMIT $7 OFFSET $8
+`
+
+type ListEventsFilteredParams struct {
+	Status          *string  `json:"status"`
+	MinSeverity     *int32   `json:"min_severity"`
+	MaxSeverity     *int32   `json:"max_severity"`
+	EventTypeCodes  []string `json:"event_type_codes"`
+	RespondedByType *string  `json:"responded_by_type"`
+	IncludeDeleted  bool     `json:"include_deleted"`
+	Limit           int32    `json:"limit"`
+	Offset          int32    `json:"offset"`
+}
+
+type ListEventsFilteredRow struct {
+	ID                      pgtype.UUID            `json:"id"`
+	Title                   string                 `json:"title"`
+	Description             *string                `json:"description"`
+	ReportSource            *string                `json:"report_source"`
+	Address                 *string                `json:"address"`
+	Longitude               float64                `json:"longitude"`
+	Latitude                float64                `json:"latitude"`
+	Severity                int32                  `json:"severity"`
+	EventTypeCode           string                 `json:"event_type_code"`
+	EventTypeName           string                 `json:"event_type_name"`
+	DefaultSeverity         int32                  `json:"default_severity"`
+	AutoSimulated           bool                   `json:"auto_simulated"`
+	ReportedAt              pgtype.Timestamptz     `json:"reported_at"`
+	UpdatedAt               pgtype.Timestamptz     `json:"updated_at"`
+	ClosedAt                pgtype.Timestamptz     `json:"closed_at"`
+	InterventionID          pgtype.UUID            `json:"intervention_id"`
+	InterventionStatus      NullInterventionStatus `json:"intervention_status"`
+	InterventionStartedAt   pgtype.Timestamptz     `json:"intervention_started_at"`
+	InterventionCompletedAt pgtype.Timestamptz     `json:"intervention_completed_at"`
+	Version                 int32                  `json:"version"`
+}
+
+// Same columns as ListEvents; every filter is optional and they are ANDed. status is derived:
+// open (not closed), acknowledged (open and acknowledged) or closed
+// responded_by_type keeps events that had a unit of that type assigned
+func (q *Queries) ListEventsFiltered(ctx context.Context, arg ListEventsFilteredParams) ([]ListEventsFilteredRow, error) {
+	rows, err := q.db.Query(ctx, listEventsFiltered,
+		arg.Status,
+		arg.MinSeverity,
+		arg.MaxSeverity,
+		arg.EventTypeCodes,
+		arg.RespondedByType,
+		arg.IncludeDeleted,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListEventsFilteredRow
+	for rows.Next() {
+		var i ListEventsFilteredRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.ReportSource,
+			&i.Address,
+			&i.Longitude,
+			&i.Latitude,
+			&i.Severity,
+			&i.EventTypeCode,
+			&i.EventTypeName,
+			&i.DefaultSeverity,
+			&i.AutoSimulated,
+			&i.ReportedAt,
+			&i.UpdatedAt,
+			&i.ClosedAt,
+			&i.InterventionID,
+			&i.InterventionStatus,
+			&i.InterventionStartedAt,
+			&i.InterventionCompletedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEventsInBounds = `-- name: ListEventsInBounds :many
+SELECT
+    e.id,
+    e.title,
+    e.description,
+    e.report_source,
+    e.address,
+    ST_X(e.location::geometry)::double precision AS longitude,
+    ST_Y(e.location::geometry)::double precision AS latitude,
+    e.severity,
+    e.event_type_code,
+    et.name AS event_type_name,
+    et.default_severity,
+    e.auto_simulated,
+    e.reported_at,
+    e.updated_at,
+    e.closed_at,
+    i.id AS intervention_id,
+    i.status AS intervention_status,
+    i.started_at AS intervention_started_at,
+    i.completed_at AS intervention_completed_at,
+    e.version
+FROM events e
+JOIN event_types et ON et.code = e.event_type_code
+LEFT JOIN interventions i ON i.event_id = e.id
+WHERE e.location::geometry && ST_MakeEnvelope($1::double precision, $2::double precision, $3::double precision, $4::double precision, 4326)
+  AND ($5::boolean OR e.deleted_at IS NULL)
+ORDER BY e.reported_at DESC
+LIMIT $6 OFFSET $7
+`
+
+type ListEventsInBoundsParams struct {
+	MinLon         float64 `json:"min_lon"`
+	MinLat         float64 `json:"min_lat"`
+	MaxLon         float64 `json:"max_lon"`
+	MaxLat         float64 `json:"max_lat"`
+	IncludeDeleted bool    `json:"include_deleted"`
+	Limit          int32   `json:"limit"`
+	Offset         int32   `json:"offset"`
+}
+
+type ListEventsInBoundsRow struct {
+	ID                      pgtype.UUID            `json:"id"`
+	Title                   string                 `json:"title"`
+	Description             *string                `json:"description"`
+	ReportSource            *string                `json:"report_source"`
+	Address                 *string                `json:"address"`
+	Longitude               float64                `json:"longitude"`
+	Latitude                float64                `json:"latitude"`
+	Severity                int32                  `json:"severity"`
+	EventTypeCode           string                 `json:"event_type_code"`
+	EventTypeName           string                 `json:"event_type_name"`
+	DefaultSeverity         int32                  `json:"default_severity"`
+	AutoSimulated           bool                   `json:"auto_simulated"`
+	ReportedAt              pgtype.Timestamptz     `json:"reported_at"`
+	UpdatedAt               pgtype.Timestamptz     `json:"updated_at"`
+	ClosedAt                pgtype.Timestamptz     `json:"closed_at"`
+	InterventionID          pgtype.UUID            `json:"intervention_id"`
+	InterventionStatus      NullInterventionStatus `json:"intervention_status"`
+	InterventionStartedAt   pgtype.Timestamptz     `json:"intervention_started_at"`
+	InterventionCompletedAt pgtype.Timestamptz     `json:"intervention_completed_at"`
+	Version                 int32                  `json:"version"`
+}
+
+// Same columns as ListEvents, restricted to a lon/lat envelope; the && on location::geometry uses events_location_geom_idx
+func (q *Queries) ListEventsInBounds(ctx context.Context, arg ListEventsInBoundsParams) ([]ListEventsInBoundsRow, error) {
+	rows, err := q.db.Query(ctx, listEventsInBounds,
+		arg.MinLon,
+		arg.MinLat,
+		arg.MaxLon,
+		arg.MaxLat,
+		arg.IncludeDeleted,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListEventsInBoundsRow
+	for rows.Next() {
+		var i ListEventsInBoundsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.ReportSource,
+			&i.Address,
+			&i.Longitude,
+			&i.Latitude,
+			&i.Severity,
+			&i.EventTypeCode,
+			&i.EventTypeName,
+			&i.DefaultSeverity,
+			&i.AutoSimulated,
+			&i.ReportedAt,
+			&i.UpdatedAt,
+			&i.ClosedAt,
+			&i.InterventionID,
+			&i.InterventionStatus,
+			&i.InterventionStartedAt,
+			&i.InterventionCompletedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStaleOpenEvents = `-- name: ListStaleOpenEvents :many
+SELECT
+    e.id,
+    e.title,
+    e.reported_at,
+    e.updated_at,
+    e.acknowledged_at
+FROM events e
+WHERE e.closed_at IS NULL
+  AND e.deleted_at IS NULL
+  AND e.updated_at < $1::timestamptz
+  AND NOT EXISTS (
+      SELECT 1 FROM interventions i
+      WHERE i.event_id = e.id
+        AND (i.status IN ('created', 'on_site')
+             OR GREATEST(i.created_at, i.completed_at) >= $1::timestamptz)
+  )
+  AND NOT EXISTS (
+      SELECT 1 FROM activity_logs al
+      WHERE al.activity_type = 'stale_event'
+        AND al.entity_type = 'event'
+        AND al.entity_id = e.id
+        AND al.created_at >= e.updated_at
+  )
+ORDER BY e.updated_at
+LIMIT $2
+`
+
+type ListStaleOpenEventsParams struct {
+	Cutoff pgtype.Timestamptz `json:"cutoff"`
+	Limit  int32              `json:"limit"`
+}
+
+type ListStaleOpenEventsRow struct {
+	ID             pgtype.UUID        `json:"id"`
+	Title          string             `json:"title"`
+	ReportedAt     pgtype.Timestamptz `json:"reported_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+	AcknowledgedAt pgtype.Timestamptz `json:"acknowledged_at"`
+}
+
+// Open events untouched since cutoff: no open intervention, no intervention created or completed
+// since cutoff, and not already flagged stale since their last update
+func (q *Queries) ListStaleOpenEvents(ctx context.Context, arg ListStaleOpenEventsParams) ([]ListStaleOpenEventsRow, error) {
+	rows, err := q.db.Query(ctx, listStaleOpenEvents, arg.Cutoff, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListStaleOpenEventsRow
+	for rows.Next() {
+		var i ListStaleOpenEventsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.ReportedAt,
+			&i.UpdatedAt,
+			&i.AcknowledgedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockEvent = `-- name: LockEvent :one
+SELECT id FROM events WHERE id = $1 FOR UPDATE
+`
+
+// Row-locks an event so concurrent intervention creation checks are serialised
+func (q *Queries) LockEvent(ctx context.Context, eventID pgtype.UUID) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, lockEvent, eventID)
+	var id pgtype.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const searchEvents = `-- name: SearchEvents :many
+SELECT
+    e.id,
+    e.title,
+    e.description,
+    e.report_source,
+    e.address,
+    ST_X(e.location::geometry)::double precision AS longitude,
+    ST_Y(e.location::geometry)::double precision AS latitude,
+    e.severity,
+    e.event_type_code,
+    et.name AS event_type_name,
+    et.default_severity,
+    e.auto_simulated,
+    e.reported_at,
+    e.updated_at,
+    e.closed_at,
+    i.id AS intervention_id,
+    i.status AS intervention_status,
+    i.started_at AS intervention_started_at,
+    i.completed_at AS intervention_completed_at,
+    e.version
+FROM events e
+JOIN event_types et ON et.code = e.event_type_code
+LEFT JOIN interventions i ON i.event_id = e.id
+WHERE to_tsvector('simple'::regconfig, e.title || ' ' || COALESCE(e.description, '') || ' ' || COALESCE(e.address, '')) @@ plainto_tsquery('simple'::regconfig, $1::text)
+  AND ($2::boolean OR e.deleted_at IS NULL)
+ORDER BY ts_rank(to_tsvector('simple'::regconfig, e.title || ' ' || COALESCE(e.description, '') || ' ' || COALESCE(e.address, '')), plainto_tsquery('simple'::regconfig, $1::text)) DESC, e.reported_at DESC
+LIMIT $3 OFFSET $4
+`
+
+type SearchEventsParams struct {
+	Query          string `json:"query"`
+	IncludeDeleted bool   `json:"include_deleted"`
+	Limit          int32  `json:"limit"`
+	Offset         int32  `json:"offset"`
+}
+
+type SearchEventsRow struct {
+	ID                      pgtype.UUID            `json:"id"`
+	Title                   string                 `json:"title"`
+	Description             *string                `json:"description"`
+	ReportSource            *string                `json:"report_source"`
+	Address                 *string                `json:"address"`
+	Longitude               float64                `json:"longitude"`
+	Latitude                float64                `json:"latitude"`
+	Severity                int32                  `json:"severity"`
+	EventTypeCode           string                 `json:"event_type_code"`
+	EventTypeName           string                 `json:"event_type_name"`
+	DefaultSeverity         int32                  `json:"default_severity"`
+	AutoSimulated           bool                   `json:"auto_simulated"`
+	ReportedAt              pgtype.Timestamptz     `json:"reported_at"`
+	UpdatedAt               pgtype.Timestamptz     `json:"updated_at"`
+	ClosedAt                pgtype.Timestamptz     `json:"closed_at"`
+	InterventionID          pgtype.UUID            `json:"intervention_id"`
+	InterventionStatus      NullInterventionStatus `json:"intervention_status"`
+	InterventionStartedAt   pgtype.Timestamptz     `json:"intervention_started_at"`
+	InterventionCompletedAt pgtype.Timestamptz     `json:"intervention_completed_at"`
+	Version                 int32                  `json:"version"`
+}
+
+// Same columns as ListEvents, matching title, description and address; the document expression
+// must stay in sync with events_search_idx
+func (q *Queries) SearchEvents(ctx context.Context, arg SearchEventsParams) ([]SearchEventsRow, error) {
+	rows, err := q.db.Query(ctx, searchEvents,
+		arg.Query,
+		arg.IncludeDeleted,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchEventsRow
+	for rows.Next() {
+		var i SearchEventsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.ReportSource,
+			&i.Address,
+			&i.Longitude,
+			&i.Latitude,
+			&i.Severity,
+			&i.EventTypeCode,
+			&i.EventTypeName,
+			&i.DefaultSeverity,
+			&i.AutoSimulated,
+			&i.ReportedAt,
+			&i.UpdatedAt,
+			&i.ClosedAt,
+			&i.InterventionID,
+			&i.InterventionStatus,
+			&i.InterventionStartedAt,
+			&i.InterventionCompletedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const softDeleteEvent = `-- name: SoftDeleteEvent :one
+UPDATE events
+SET deleted_at = COALESCE(deleted_at, NOW()),
+    updated_at = NOW()
+WHERE id = $1
+RETURNING
+    id,
+    deleted_at
+`
+
+type SoftDeleteEventRow struct {
+	ID        pgtype.UUID        `json:"id"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+}
+
+// Hides an event from the lists; deleting twice keeps the first deleted_at
+func (q *Queries) SoftDeleteEvent(ctx context.Context, id pgtype.UUID) (SoftDeleteEventRow, error) {
+	row := q.db.QueryRow(ctx, softDeleteEvent, id)
+	var i SoftDeleteEventRow
+	err := row.Scan(
+		&i.ID,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const updateEvent = `-- name: UpdateEvent :one
+UPDATE events
+SET severity = COALESCE($1::int, severity),
+    location = CASE
+        WHEN $2::double precision IS NULL OR $3::double precision IS NULL THEN location
+        ELSE ST_SetSRID(ST_MakePoint($2::double precision, $3::double precision), 4326)::geography
+    END,
+    address = COALESCE($4::text, address),
+    description = COALESCE($5::text, description),
+    updated_at = NOW()
+WHERE id = $6 AND ($7::int IS NULL OR version = $7::int)
+RETURNING
+    id,
+    updated_at,
+    version
+`
+
+type UpdateEventParams struct {
+	Severity        *int32      `json:"severity"`
+	Longitude       *float64    `json:"longitude"`
+	Latitude        *float64    `json:"latitude"`
+	Address         *string     `json:"address"`
+	Description     *string     `json:"description"`
+	ID              pgtype.UUID `json:"id"`
+	ExpectedVersion *int32      `json:"expected_version"`
+}
+
+type UpdateEventRow struct {
+	ID        pgtype.UUID        `json:"id"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+	Version   int32              `json:"version"`
+}
+
+// Applies only the non-null fields; the location changes only when both coordinates are given
+func (q *Queries) UpdateEvent(ctx context.Context, arg UpdateEventParams) (UpdateEventRow, error) {
+	row := q.db.QueryRow(ctx, updateEvent,
+		arg.Severity,
+		arg.Longitude,
+		arg.Latitude,
+		arg.Address,
+		arg.Description,
+		arg.ID,
+		arg.ExpectedVersion,
+	)
+	var i UpdateEventRow
+	err := row.Scan(
+		&i.ID,
+		&i.UpdatedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const updateEventAutoSimulated = `-- name: UpdateEventAutoSimulated :one
+UPDATE events
+SET auto_simulated = $2,
+    updated_at = NOW()
+WHERE id = $1 AND ($3::int IS NULL OR version = $3::int)
+RETURNING
+    id,
+    auto_simulated,
+    updated_at,
+    version
+`
+
+type UpdateEventAutoSimulatedParams struct {
+	ID              pgtype.UUID `json:"id"`
+	AutoSimulated   bool        `json:"auto_simulated"`
+	ExpectedVersion *int32      `json:"expected_version"`
+}
+
+type UpdateEventAutoSimulatedRow struct {
+	ID            pgtype.UUID        `json:"id"`
+	AutoSimulated bool               `json:"auto_simulated"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+	Version       int32              `json:"version"`
+}
+
+func (q *Queries) UpdateEventAutoSimulated(ctx context.Context, arg UpdateEventAutoSimulatedParams) (UpdateEventAutoSimulatedRow, error) {
+	row := q.db.QueryRow(ctx, updateEventAutoSimulated, arg.ID, arg.AutoSimulated, arg.ExpectedVersion)
+	var i UpdateEventAutoSimulatedRow
+	err := row.Scan(
+		&i.ID,
+		&i.AutoSimulated,
+		&i.UpdatedAt,
+		&i.Version,
+	)
 	return i, err
 }
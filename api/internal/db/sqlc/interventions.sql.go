@@ -11,6 +11,71 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countActiveAssignments = `-- name: CountActiveAssignments :one
+SELECT COUNT(*)::bigint AS active_count
+FROM intervention_assignments
+WHERE intervention_id = $1
+  AND released_at IS NULL
+  AND status IN ('dispatched', 'arrived')
+`
+
+func (q *Queries) CountActiveAssignments(ctx context.Context, interventionID pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countActiveAssignments, interventionID)
+	var active_count int64
+	err := row.Scan(&active_count)
+	return active_count, err
+}
+
+const countActiveAssignmentsForUnit = `-- name: CountActiveAssignmentsForUnit :one
+SELECT COUNT(*)::bigint
+FROM intervention_assignments
+WHERE unit_id = $1
+  AND released_at IS NULL
+  AND status NOT IN ('released', 'cancelled')
+`
+
+func (q *Queries) CountActiveAssignmentsForUnit(ctx context.Context, unitID pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countActiveAssignmentsForUnit, unitID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countActiveAssignmentsWithRole = `-- name: CountActiveAssignmentsWithRole :one
+SELECT COUNT(*)::bigint
+FROM intervention_assignments
+WHERE intervention_id = $1
+  AND lower(role) = lower($2::text)
+  AND released_at IS NULL
+  AND status NOT IN ('released', 'cancelled')
+`
+
+type CountActiveAssignmentsWithRoleParams struct {
+	InterventionID pgtype.UUID `json:"intervention_id"`
+	Role           string      `json:"role"`
+}
+
+func (q *Queries) CountActiveAssignmentsWithRole(ctx context.Context, arg CountActiveAssignmentsWithRoleParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countActiveAssignmentsWithRole, arg.InterventionID, arg.Role)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countOpenInterventionsForEvent = `-- name: CountOpenInterventionsForEvent :one
+SELECT COUNT(*)::bigint
+FROM interventions
+WHERE event_id = $1
+  AND status <> 'cancelled'
+`
+
+func (q *Queries) CountOpenInterventionsForEvent(ctx context.Context, eventID pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countOpenInterventionsForEvent, eventID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createAssignment = `-- name: CreateAssignment :one
 INSERT INTO intervention_assignments (
     intervention_id,
@@ -243,6 +308,91 @@ func (q *Queries) GetInterventionEventContext(ctx context.Context, id pgtype.UUI
 	return i, err
 }
 
+const listActiveAssignments = `-- name: ListActiveAssignments :many
+SELECT
+    ia.id,
+    ia.intervention_id,
+    ia.unit_id,
+    ia.role,
+    ia.status,
+    ia.dispatched_at,
+    ia.arrived_at,
+    u.call_sign,
+    u.unit_type_code,
+    u.status AS unit_status,
+    i.status AS intervention_status,
+    i.event_id,
+    e.title AS event_title,
+    e.severity AS event_severity
+FROM intervention_assignments ia
+JOIN units u ON u.id = ia.unit_id
+JOIN interventions i ON i.id = ia.intervention_id
+JOIN events e ON e.id = i.event_id
+WHERE ia.released_at IS NULL
+  AND ia.status IN ('dispatched', 'arrived')
+  AND ($1::text IS NULL OR u.unit_type_code = $1)
+ORDER BY e.severity DESC, ia.dispatched_at ASC
+LIMIT $2 OFFSET $3
+`
+
+type ListActiveAssignmentsParams struct {
+	UnitType *string `json:"unit_type"`
+	Limit    int32   `json:"limit"`
+	Offset   int32   `json:"offset"`
+}
+
+type ListActiveAssignmentsRow struct {
+	ID                 pgtype.UUID        `json:"id"`
+	InterventionID     pgtype.UUID        `json:"intervention_id"`
+	UnitID             pgtype.UUID        `json:"unit_id"`
+	Role               *string            `json:"role"`
+	Status             AssignmentStatus   `json:"status"`
+	DispatchedAt       pgtype.Timestamptz `json:"dispatched_at"`
+	ArrivedAt          pgtype.Timestamptz `json:"arrived_at"`
+	CallSign           string             `json:"call_sign"`
+	UnitTypeCode       string             `json:"unit_type_code"`
+	UnitStatus         UnitStatus         `json:"unit_status"`
+	InterventionStatus InterventionStatus `json:"intervention_status"`
+	EventID            pgtype.UUID        `json:"event_id"`
+	EventTitle         string             `json:"event_title"`
+	EventSeverity      int32              `json:"event_severity"`
+}
+
+func (q *Queries) ListActiveAssignments(ctx context.Context, arg ListActiveAssignmentsParams) ([]ListActiveAssignmentsRow, error) {
+	rows, err := q.db.Query(ctx, listActiveAssignments, arg.UnitType, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListActiveAssignmentsRow
+	for rows.Next() {
+		var i ListActiveAssignmentsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.InterventionID,
+			&i.UnitID,
+			&i.Role,
+			&i.Status,
+			&i.DispatchedAt,
+			&i.ArrivedAt,
+			&i.CallSign,
+			&i.UnitTypeCode,
+			&i.UnitStatus,
+			&i.InterventionStatus,
+			&i.EventID,
+			&i.EventTitle,
+			&i.EventSeverity,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listAssignmentsByIntervention = `-- name: ListAssignmentsByIntervention :many
 SELECT
     ia.id,
@@ -378,6 +528,198 @@ func (q *Queries) ListInterventionsByEvent(ctx context.Context, eventID pgtype.U
 	return items, nil
 }
 
+const listInterventionsCreatedBetween = `-- name: ListInterventionsCreatedBetween :many
+SELECT
+    i.id,
+    i.event_id,
+    i.status,
+    i.priority,
+    i.decision_mode,
+    i.created_by,
+    i.notes,
+    i.created_at,
+    i.started_at,
+    i.completed_at,
+    e.title AS event_title,
+    e.event_type_code,
+    e.severity AS event_severity,
+    e.address AS event_address
+FROM interventions i
+JOIN events e ON e.id = i.event_id
+WHERE ($1::timestamptz IS NULL OR i.created_at >= $1::timestamptz)
+  AND ($2::timestamptz IS NULL OR i.created_at < $2::timestamptz)
+  AND ($3::intervention_status IS NULL OR i.status = $3::intervention_status)
+  AND ($4::integer IS NULL OR i.priority = $4::integer)
+ORDER BY i.created_at ASC, i.id ASC
+LIMIT $5 OFFSET $6
+`
+
+type ListInterventionsCreatedBetweenParams struct {
+	CreatedFrom pgtype.Timestamptz     `json:"created_from"`
+	CreatedTo   pgtype.Timestamptz     `json:"created_to"`
+	Status      NullInterventionStatus `json:"status"`
+	Priority    *int32                 `json:"priority"`
+	Limit       int32                  `json:"limit"`
+	Offset      int32                  `json:"offset"`
+}
+
+type ListInterventionsCreatedBetweenRow struct {
+	ID            pgtype.UUID        `json:"id"`
+	EventID       pgtype.UUID        `json:"event_id"`
+	Status        InterventionStatus `json:"status"`
+	Priority      int32              `json:"priority"`
+	DecisionMode  DecisionMode       `json:"decision_mode"`
+	CreatedBy     *string            `json:"created_by"`
+	Notes         *string            `json:"notes"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	StartedAt     pgtype.Timestamptz `json:"started_at"`
+	CompletedAt   pgtype.Timestamptz `json:"completed_at"`
+	EventTitle    string             `json:"event_title"`
+	EventTypeCode string             `json:"event_type_code"`
+	EventSeverity int32              `json:"event_severity"`
+	EventAddress  *string            `json:"event_address"`
+}
+
+// Interventions created in [created_from, created_to), with event context, for shift reports
+func (q *Queries) ListInterventionsCreatedBetween(ctx context.Context, arg ListInterventionsCreatedBetweenParams) ([]ListInterventionsCreatedBetweenRow, error) {
+	rows, err := q.db.Query(ctx, listInterventionsCreatedBetween,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.Status,
+		arg.Priority,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListInterventionsCreatedBetweenRow
+	for rows.Next() {
+		var i ListInterventionsCreatedBetweenRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventID,
+			&i.Status,
+			&i.Priority,
+			&i.DecisionMode,
+			&i.CreatedBy,
+			&i.Notes,
+			&i.CreatedAt,
+			&i.StartedAt,
+			&i.CompletedAt,
+			&i.EventTitle,
+			&i.EventTypeCode,
+			&i.EventSeverity,
+			&i.EventAddress,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOverlappingInterventions = `-- name: ListOverlappingInterventions :many
+SELECT
+    a.id AS intervention_id,
+    a.status AS intervention_status,
+    ea.id AS event_id,
+    ea.title AS event_title,
+    ea.event_type_code,
+    ea.severity AS event_severity,
+    ea.reported_at AS event_reported_at,
+    b.id AS other_intervention_id,
+    b.status AS other_intervention_status,
+    eb.id AS other_event_id,
+    eb.title AS other_event_title,
+    eb.event_type_code AS other_event_type_code,
+    eb.severity AS other_event_severity,
+    eb.reported_at AS other_event_reported_at,
+    ST_Distance(ea.location, eb.location)::double precision AS distance_meters,
+    ABS(EXTRACT(EPOCH FROM (ea.reported_at - eb.reported_at)))::double precision AS time_gap_seconds
+FROM interventions a
+JOIN events ea ON ea.id = a.event_id
+JOIN interventions b ON b.id > a.id
+JOIN events eb ON eb.id = b.event_id
+WHERE a.status IN ('created', 'on_site')
+  AND b.status IN ('created', 'on_site')
+  AND ea.id <> eb.id
+  AND ea.deleted_at IS NULL
+  AND eb.deleted_at IS NULL
+  AND ST_DWithin(ea.location, eb.location, $1::double precision)
+  AND eb.reported_at BETWEEN ea.reported_at - $2::interval AND ea.reported_at + $2::interval
+ORDER BY distance_meters ASC, time_gap_seconds ASC
+LIMIT $3
+`
+
+type ListOverlappingInterventionsParams struct {
+	RadiusMeters float64         `json:"radius_meters"`
+	Window       pgtype.Interval `json:"window"`
+	Limit        int32           `json:"limit"`
+}
+
+type ListOverlappingInterventionsRow struct {
+	InterventionID          pgtype.UUID        `json:"intervention_id"`
+	InterventionStatus      InterventionStatus `json:"intervention_status"`
+	EventID                 pgtype.UUID        `json:"event_id"`
+	EventTitle              string             `json:"event_title"`
+	EventTypeCode           string             `json:"event_type_code"`
+	EventSeverity           int32              `json:"event_severity"`
+	EventReportedAt         pgtype.Timestamptz `json:"event_reported_at"`
+	OtherInterventionID     pgtype.UUID        `json:"other_intervention_id"`
+	OtherInterventionStatus InterventionStatus `json:"other_intervention_status"`
+	OtherEventID            pgtype.UUID        `json:"other_event_id"`
+	OtherEventTitle         string             `json:"other_event_title"`
+	OtherEventTypeCode      string             `json:"other_event_type_code"`
+	OtherEventSeverity      int32              `json:"other_event_severity"`
+	OtherEventReportedAt    pgtype.Timestamptz `json:"other_event_reported_at"`
+	DistanceMeters          float64            `json:"distance_meters"`
+	TimeGapSeconds          float64            `json:"time_gap_seconds"`
+}
+
+// Pairs of active interventions on different events reported within radius_meters and window of each other.
+// Each pair is returned once, closest first; they are candidates for merging.
+func (q *Queries) ListOverlappingInterventions(ctx context.Context, arg ListOverlappingInterventionsParams) ([]ListOverlappingInterventionsRow, error) {
+	rows, err := q.db.Query(ctx, listOverlappingInterventions, arg.RadiusMeters, arg.Window, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListOverlappingInterventionsRow
+	for rows.Next() {
+		var i ListOverlappingInterventionsRow
+		if err := rows.Scan(
+			&i.InterventionID,
+			&i.InterventionStatus,
+			&i.EventID,
+			&i.EventTitle,
+			&i.EventTypeCode,
+			&i.EventSeverity,
+			&i.EventReportedAt,
+			&i.OtherInterventionID,
+			&i.OtherInterventionStatus,
+			&i.OtherEventID,
+			&i.OtherEventTitle,
+			&i.OtherEventTypeCode,
+			&i.OtherEventSeverity,
+			&i.OtherEventReportedAt,
+			&i.DistanceMeters,
+			&i.TimeGapSeconds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listUnitsAssignedToEvent = `-- name: ListUnitsAssignedToEvent :many
 SELECT
     u.id,
@@ -448,6 +790,37 @@ func (q *Queries) ListUnitsAssignedToEvent(ctx context.Context, eventID pgtype.U
 	return items, nil
 }
 
+const lockAssignment = `-- name: LockAssignment :one
+SELECT status, arrived_at, released_at FROM intervention_assignments WHERE id = $1 FOR UPDATE
+`
+
+type LockAssignmentRow struct {
+	Status     AssignmentStatus   `json:"status"`
+	ArrivedAt  pgtype.Timestamptz `json:"arrived_at"`
+	ReleasedAt pgtype.Timestamptz `json:"released_at"`
+}
+
+// Row-locks an assignment and returns its timestamps before the update, so arrival
+// and release are only observed once
+func (q *Queries) LockAssignment(ctx context.Context, id pgtype.UUID) (LockAssignmentRow, error) {
+	row := q.db.QueryRow(ctx, lockAssignment, id)
+	var i LockAssignmentRow
+	err := row.Scan(&i.Status, &i.ArrivedAt, &i.ReleasedAt)
+	return i, err
+}
+
+const lockIntervention = `-- name: LockIntervention :one
+SELECT status FROM interventions WHERE id = $1 FOR UPDATE
+`
+
+// Row-locks an intervention so concurrent assignment checks are serialised
+func (q *Queries) LockIntervention(ctx context.Context, id pgtype.UUID) (InterventionStatus, error) {
+	row := q.db.QueryRow(ctx, lockIntervention, id)
+	var status InterventionStatus
+	err := row.Scan(&status)
+	return status, err
+}
+
 const releaseUnitFromIntervention = `-- name: ReleaseUnitFromIntervention :one
 UPDATE intervention_assignments
 SET
@@ -473,8 +846,8 @@ const updateAssignmentStatus = `-- name: UpdateAssignmentStatus :one
 UPDATE intervention_assignments
 SET
     status = $2::assignment_status,
-    arrived_at = CASE WHEN $2::assignment_status = 'arrived' THEN NOW() ELSE arrived_at END,
-    released_at = CASE WHEN $2::assignment_status = 'released' THEN NOW() ELSE released_at END
+    arrived_at = CASE WHEN $2::assignment_status = 'arrived' THEN COALESCE(arrived_at, NOW()) ELSE arrived_at END,
+    released_at = CASE WHEN $2::assignment_status = 'released' THEN COALESCE(released_at, NOW()) ELSE released_at END
 WHERE id = $1
 RETURNING
     id,
@@ -492,6 +865,7 @@ type UpdateAssignmentStatusParams struct {
 	Column2 AssignmentStatus `json:"column_2"`
 }
 
+// arrived_at and released_at are set on the first transition only
 func (q *Queries) UpdateAssignmentStatus(ctx context.Context, arg UpdateAssignmentStatusParams) (InterventionAssignment, error) {
 	row := q.db.QueryRow(ctx, updateAssignmentStatus, arg.ID, arg.Column2)
 	var i InterventionAssignment
@@ -97,6 +97,65 @@ func (q *Queries) GetStation(ctx context.Context, id pgtype.UUID) (GetStationRow
 	return i, err
 }
 
+const listBaseResponseTimes = `-- name: ListBaseResponseTimes :many
+SELECT
+    l.id,
+    l.name,
+    COUNT(*)::bigint AS arrived_assignments,
+    AVG(EXTRACT(EPOCH FROM (ia.arrived_at - ia.dispatched_at)))::double precision AS avg_travel_seconds,
+    (percentile_cont(0.9) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM (ia.arrived_at - ia.dispatched_at))))::double precision AS p90_travel_seconds
+FROM intervention_assignments ia
+JOIN units u ON u.id = ia.unit_id
+JOIN locations l ON l.id = u.location_id
+WHERE l.type = 'station'
+  AND ia.arrived_at IS NOT NULL
+  AND ia.dispatched_at >= $1
+  AND ia.dispatched_at < $2
+GROUP BY l.id, l.name
+ORDER BY p90_travel_seconds DESC, l.name
+`
+
+type ListBaseResponseTimesParams struct {
+	FromTime pgtype.Timestamptz `json:"from_time"`
+	ToTime   pgtype.Timestamptz `json:"to_time"`
+}
+
+type ListBaseResponseTimesRow struct {
+	ID                 pgtype.UUID `json:"id"`
+	Name               string      `json:"name"`
+	ArrivedAssignments int64       `json:"arrived_assignments"`
+	AvgTravelSeconds   float64     `json:"avg_travel_seconds"`
+	P90TravelSeconds   float64     `json:"p90_travel_seconds"`
+}
+
+// Dispatch-to-arrival travel times per home base for assignments dispatched in [from_time, to_time).
+// Units are grouped by their current home base.
+func (q *Queries) ListBaseResponseTimes(ctx context.Context, arg ListBaseResponseTimesParams) ([]ListBaseResponseTimesRow, error) {
+	rows, err := q.db.Query(ctx, listBaseResponseTimes, arg.FromTime, arg.ToTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBaseResponseTimesRow
+	for rows.Next() {
+		var i ListBaseResponseTimesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.ArrivedAssignments,
+			&i.AvgTravelSeconds,
+			&i.P90TravelSeconds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listLocations = `-- name: ListLocations :many
 SELECT
     id,
@@ -148,6 +207,58 @@ func (q *Queries) ListLocations(ctx context.Context) ([]ListLocationsRow, error)
 	return items, nil
 }
 
+const listStationCoverage = `-- name: ListStationCoverage :many
+SELECT
+    l.id,
+    l.name,
+    (COALESCE(ST_X(l.location::geometry)::double precision, 0::double precision))::double precision AS longitude,
+    (COALESCE(ST_Y(l.location::geometry)::double precision, 0::double precision))::double precision AS latitude,
+    COUNT(u.id) FILTER (WHERE u.status = 'available')::bigint AS available_units,
+    COUNT(u.id)::bigint AS total_units
+FROM locations l
+LEFT JOIN units u ON u.location_id = l.id
+WHERE l.type = 'station'
+GROUP BY l.id, l.name, l.location
+ORDER BY l.name
+`
+
+type ListStationCoverageRow struct {
+	ID             pgtype.UUID `json:"id"`
+	Name           string      `json:"name"`
+	Longitude      float64     `json:"longitude"`
+	Latitude       float64     `json:"latitude"`
+	AvailableUnits int64       `json:"available_units"`
+	TotalUnits     int64       `json:"total_units"`
+}
+
+// Lists every station with its unit counts, including stations without units (for rebalancing)
+func (q *Queries) ListStationCoverage(ctx context.Context) ([]ListStationCoverageRow, error) {
+	rows, err := q.db.Query(ctx, listStationCoverage)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListStationCoverageRow
+	for rows.Next() {
+		var i ListStationCoverageRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Longitude,
+			&i.Latitude,
+			&i.AvailableUnits,
+			&i.TotalUnits,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listStations = `-- name: ListStations :many
 SELECT
     id,
@@ -150,6 +150,8 @@ const (
 	UnitStatusOnSite          UnitStatus = "on_site"
 	UnitStatusUnavailable     UnitStatus = "unavailable"
 	UnitStatusOffline         UnitStatus = "offline"
+	UnitStatusMaintenance     UnitStatus = "maintenance"
+	UnitStatusReturning       UnitStatus = "returning"
 )
 
 func (e *UnitStatus) Scan(src interface{}) error {
@@ -200,37 +202,52 @@ type ActivityLog struct {
 }
 
 type DispatchConfig struct {
-	Key         string             `json:"key"`
-	Value       pgtype.Numeric     `json:"value"`
-	Description string             `json:"description"`
-	MinValue    pgtype.Numeric     `json:"min_value"`
-	MaxValue    pgtype.Numeric     `json:"max_value"`
-	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+	Key          string             `json:"key"`
+	Value        pgtype.Numeric     `json:"value"`
+	Description  string             `json:"description"`
+	MinValue     pgtype.Numeric     `json:"min_value"`
+	MaxValue     pgtype.Numeric     `json:"max_value"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+	DefaultValue pgtype.Numeric     `json:"default_value"`
+}
+
+type DispatchConfigHistory struct {
+	ID        int64              `json:"id"`
+	ConfigKey string             `json:"config_key"`
+	OldValue  pgtype.Numeric     `json:"old_value"`
+	NewValue  pgtype.Numeric     `json:"new_value"`
+	Actor     *string            `json:"actor"`
+	ChangedAt pgtype.Timestamptz `json:"changed_at"`
 }
 
 type Event struct {
-	ID            pgtype.UUID        `json:"id"`
-	Title         string             `json:"title"`
-	Description   *string            `json:"description"`
-	ReportSource  *string            `json:"report_source"`
-	Address       *string            `json:"address"`
-	Location      interface{}        `json:"location"`
-	Severity      int32              `json:"severity"`
-	EventTypeCode string             `json:"event_type_code"`
-	ReportedAt    pgtype.Timestamptz `json:"reported_at"`
-	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
-	ClosedAt      pgtype.Timestamptz `json:"closed_at"`
-	AutoSimulated bool               `json:"auto_simulated"`
+	ID             pgtype.UUID        `json:"id"`
+	Title          string             `json:"title"`
+	Description    *string            `json:"description"`
+	ReportSource   *string            `json:"report_source"`
+	Address        *string            `json:"address"`
+	Location       interface{}        `json:"location"`
+	Severity       int32              `json:"severity"`
+	EventTypeCode  string             `json:"event_type_code"`
+	ReportedAt     pgtype.Timestamptz `json:"reported_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+	ClosedAt       pgtype.Timestamptz `json:"closed_at"`
+	AutoSimulated  bool               `json:"auto_simulated"`
+	AcknowledgedAt pgtype.Timestamptz `json:"acknowledged_at"`
+	AcknowledgedBy *string            `json:"acknowledged_by"`
+	DeletedAt      pgtype.Timestamptz `json:"deleted_at"`
+	Version        int32              `json:"version"`
 }
 
 type EventType struct {
-	Code                 string             `json:"code"`
-	Name                 string             `json:"name"`
-	Description          string             `json:"description"`
-	DefaultSeverity      int32              `json:"default_severity"`
-	RecommendedUnitTypes []string           `json:"recommended_unit_types"`
-	CreatedAt            pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
+	Code                  string             `json:"code"`
+	Name                  string             `json:"name"`
+	Description           string             `json:"description"`
+	DefaultSeverity       int32              `json:"default_severity"`
+	RecommendedUnitTypes  []string           `json:"recommended_unit_types"`
+	CreatedAt             pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt             pgtype.Timestamptz `json:"updated_at"`
+	RecommendedUnitCounts []byte             `json:"recommended_unit_counts"`
 }
 
 type Intervention struct {
@@ -287,6 +304,19 @@ type Personnel struct {
 	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
 }
 
+type RouteCache struct {
+	FromLatKey               int32              `json:"from_lat_key"`
+	FromLonKey               int32              `json:"from_lon_key"`
+	ToLatKey                 int32              `json:"to_lat_key"`
+	ToLonKey                 int32              `json:"to_lon_key"`
+	RouteGeojson             string             `json:"route_geojson"`
+	RouteLengthMeters        float64            `json:"route_length_meters"`
+	EstimatedDurationSeconds float64            `json:"estimated_duration_seconds"`
+	AlreadyAtDestination     bool               `json:"already_at_destination"`
+	CalculatedAt             pgtype.Timestamptz `json:"calculated_at"`
+	ExpiresAt                pgtype.Timestamptz `json:"expires_at"`
+}
+
 type RoutingWay struct {
 	Gid          int32       `json:"gid"`
 	Class        *string     `json:"class"`
@@ -326,6 +356,7 @@ type Unit struct {
 	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
 	MicrobitID    *string            `json:"microbit_id"`
 	LocationID    pgtype.UUID        `json:"location_id"`
+	Version       int32              `json:"version"`
 }
 
 type UnitRoute struct {
@@ -337,6 +368,7 @@ type UnitRoute struct {
 	ProgressPercent          float64            `json:"progress_percent"`
 	CreatedAt                pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt                pgtype.Timestamptz `json:"updated_at"`
+	CalculatedAt             pgtype.Timestamptz `json:"calculated_at"`
 }
 
 type UnitTelemetry struct {
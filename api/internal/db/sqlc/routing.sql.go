@@ -11,6 +11,46 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const archiveUnitRoute = `-- name: ArchiveUnitRoute :execrows
+INSERT INTO unit_route_history (
+    assignment_id,
+    unit_id,
+    intervention_id,
+    route_geometry,
+    route_length_meters,
+    estimated_duration_seconds,
+    progress_percent,
+    calculated_at
+)
+SELECT
+    (
+        SELECT ia.id
+        FROM intervention_assignments ia
+        WHERE ia.unit_id = ur.unit_id AND ia.intervention_id = ur.intervention_id
+        ORDER BY ia.dispatched_at DESC
+        LIMIT 1
+    ),
+    ur.unit_id,
+    ur.intervention_id,
+    ur.route_geometry,
+    ur.route_length_meters,
+    ur.estimated_duration_seconds,
+    ur.progress_percent,
+    ur.calculated_at
+FROM unit_routes ur
+WHERE ur.unit_id = $1
+  AND ur.intervention_id IS NOT NULL
+`
+
+// Copies a unit's intervention route into unit_route_history before it is removed or replaced
+func (q *Queries) ArchiveUnitRoute(ctx context.Context, unitID pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, archiveUnitRoute, unitID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const deleteUnitRoute = `-- name: DeleteUnitRoute :exec
 DELETE FROM unit_routes WHERE unit_id = $1
 `
@@ -68,6 +108,52 @@ func (q *Queries) GetActiveRouteRepairData(ctx context.Context, unitID pgtype.UU
 	return i, err
 }
 
+const getCachedRoute = `-- name: GetCachedRoute :one
+SELECT
+    route_geojson,
+    route_length_meters,
+    estimated_duration_seconds,
+    already_at_destination
+FROM route_cache
+WHERE from_lat_key = $1
+  AND from_lon_key = $2
+  AND to_lat_key = $3
+  AND to_lon_key = $4
+  AND expires_at > NOW()
+`
+
+type GetCachedRouteParams struct {
+	FromLatKey int32 `json:"from_lat_key"`
+	FromLonKey int32 `json:"from_lon_key"`
+	ToLatKey   int32 `json:"to_lat_key"`
+	ToLonKey   int32 `json:"to_lon_key"`
+}
+
+type GetCachedRouteRow struct {
+	RouteGeojson             string  `json:"route_geojson"`
+	RouteLengthMeters        float64 `json:"route_length_meters"`
+	EstimatedDurationSeconds float64 `json:"estimated_duration_seconds"`
+	AlreadyAtDestination     bool    `json:"already_at_destination"`
+}
+
+// Unexpired cached route between two rounded point pairs
+func (q *Queries) GetCachedRoute(ctx context.Context, arg GetCachedRouteParams) (GetCachedRouteRow, error) {
+	row := q.db.QueryRow(ctx, getCachedRoute,
+		arg.FromLatKey,
+		arg.FromLonKey,
+		arg.ToLatKey,
+		arg.ToLonKey,
+	)
+	var i GetCachedRouteRow
+	err := row.Scan(
+		&i.RouteGeojson,
+		&i.RouteLengthMeters,
+		&i.EstimatedDurationSeconds,
+		&i.AlreadyAtDestination,
+	)
+	return i, err
+}
+
 const getRouteCalculationData = `-- name: GetRouteCalculationData :one
 SELECT
     u.id AS unit_id,
@@ -202,6 +288,41 @@ func (q *Queries) GetUnitRoute(ctx context.Context, unitID pgtype.UUID) (GetUnit
 	return i, err
 }
 
+const getUnitRouteDeviation = `-- name: GetUnitRouteDeviation :one
+SELECT
+    ur.intervention_id,
+    u.call_sign,
+    ST_Distance(ur.route_geometry::geography, ST_SetSRID(ST_MakePoint($1::float8, $2::float8), 4326)::geography)::float8 AS deviation_meters
+FROM unit_routes ur
+JOIN units u ON u.id = ur.unit_id
+WHERE ur.unit_id = $3
+    AND ur.intervention_id IS NOT NULL
+`
+
+type GetUnitRouteDeviationParams struct {
+	Longitude float64     `json:"longitude"`
+	Latitude  float64     `json:"latitude"`
+	UnitID    pgtype.UUID `json:"unit_id"`
+}
+
+type GetUnitRouteDeviationRow struct {
+	InterventionID  pgtype.UUID `json:"intervention_id"`
+	CallSign        string      `json:"call_sign"`
+	DeviationMeters float64     `json:"deviation_meters"`
+}
+
+// Distance from a position to the unit's active intervention route; no row when the unit has none
+func (q *Queries) GetUnitRouteDeviation(ctx context.Context, arg GetUnitRouteDeviationParams) (GetUnitRouteDeviationRow, error) {
+	row := q.db.QueryRow(ctx, getUnitRouteDeviation, arg.Longitude, arg.Latitude, arg.UnitID)
+	var i GetUnitRouteDeviationRow
+	err := row.Scan(
+		&i.InterventionID,
+		&i.CallSign,
+		&i.DeviationMeters,
+	)
+	return i, err
+}
+
 const getUnitStationRouteData = `-- name: GetUnitStationRouteData :one
 
 SELECT
@@ -242,6 +363,175 @@ func (q *Queries) GetUnitStationRouteData(ctx context.Context, unitID pgtype.UUI
 	return i, err
 }
 
+const listAssignmentRouteHistory = `-- name: ListAssignmentRouteHistory :many
+SELECT
+    id,
+    assignment_id,
+    unit_id,
+    intervention_id,
+    ST_AsGeoJSON(route_geometry)::text AS route_geojson,
+    route_length_meters,
+    estimated_duration_seconds,
+    progress_percent,
+    calculated_at,
+    archived_at
+FROM unit_route_history
+WHERE assignment_id = $1
+ORDER BY archived_at
+`
+
+type ListAssignmentRouteHistoryRow struct {
+	ID                       int64              `json:"id"`
+	AssignmentID             pgtype.UUID        `json:"assignment_id"`
+	UnitID                   pgtype.UUID        `json:"unit_id"`
+	InterventionID           pgtype.UUID        `json:"intervention_id"`
+	RouteGeojson             string             `json:"route_geojson"`
+	RouteLengthMeters        float64            `json:"route_length_meters"`
+	EstimatedDurationSeconds float64            `json:"estimated_duration_seconds"`
+	ProgressPercent          float64            `json:"progress_percent"`
+	CalculatedAt             pgtype.Timestamptz `json:"calculated_at"`
+	ArchivedAt               pgtype.Timestamptz `json:"archived_at"`
+}
+
+// Archived routes of an assignment, oldest first
+func (q *Queries) ListAssignmentRouteHistory(ctx context.Context, assignmentID pgtype.UUID) ([]ListAssignmentRouteHistoryRow, error) {
+	rows, err := q.db.Query(ctx, listAssignmentRouteHistory, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAssignmentRouteHistoryRow
+	for rows.Next() {
+		var i ListAssignmentRouteHistoryRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AssignmentID,
+			&i.UnitID,
+			&i.InterventionID,
+			&i.RouteGeojson,
+			&i.RouteLengthMeters,
+			&i.EstimatedDurationSeconds,
+			&i.ProgressPercent,
+			&i.CalculatedAt,
+			&i.ArchivedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStaleAssignmentRoutes = `-- name: ListStaleAssignmentRoutes :many
+SELECT
+    ur.unit_id,
+    ur.intervention_id,
+    ur.calculated_at
+FROM unit_routes ur
+JOIN intervention_assignments ia
+    ON ia.unit_id = ur.unit_id
+    AND ia.intervention_id = ur.intervention_id
+    AND ia.released_at IS NULL
+    AND ia.status = 'dispatched'
+WHERE ur.calculated_at < NOW() - $1::interval
+  AND NOT (ur.unit_id = ANY($2::uuid[]))
+ORDER BY ur.calculated_at ASC
+LIMIT $3
+`
+
+type ListStaleAssignmentRoutesParams struct {
+	MaxAge      pgtype.Interval `json:"max_age"`
+	SkipUnitIds []pgtype.UUID   `json:"skip_unit_ids"`
+	MaxRoutes   int32           `json:"max_routes"`
+}
+
+type ListStaleAssignmentRoutesRow struct {
+	UnitID         pgtype.UUID        `json:"unit_id"`
+	InterventionID pgtype.UUID        `json:"intervention_id"`
+	CalculatedAt   pgtype.Timestamptz `json:"calculated_at"`
+}
+
+// Finds routes of units still heading to an intervention whose geometry is older than max_age,
+// leaving out skip_unit_ids
+func (q *Queries) ListStaleAssignmentRoutes(ctx context.Context, arg ListStaleAssignmentRoutesParams) ([]ListStaleAssignmentRoutesRow, error) {
+	rows, err := q.db.Query(ctx, listStaleAssignmentRoutes, arg.MaxAge, arg.SkipUnitIds, arg.MaxRoutes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListStaleAssignmentRoutesRow
+	for rows.Next() {
+		var i ListStaleAssignmentRoutesRow
+		if err := rows.Scan(
+			&i.UnitID,
+			&i.InterventionID,
+			&i.CalculatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const pruneRouteCache = `-- name: PruneRouteCache :execrows
+DELETE FROM route_cache
+WHERE expires_at <= NOW()
+`
+
+// Deletes expired cached routes
+func (q *Queries) PruneRouteCache(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, pruneRouteCache)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const pruneUnitRouteHistory = `-- name: PruneUnitRouteHistory :execrows
+DELETE FROM unit_route_history
+WHERE archived_at < $1
+`
+
+// Deletes archived routes older than the retention cutoff
+func (q *Queries) PruneUnitRouteHistory(ctx context.Context, archivedBefore pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, pruneUnitRouteHistory, archivedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const resetUnitRoute = `-- name: ResetUnitRoute :one
+DELETE FROM unit_routes
+WHERE unit_id = $1
+RETURNING unit_id, intervention_id, progress_percent
+`
+
+type ResetUnitRouteRow struct {
+	UnitID          pgtype.UUID `json:"unit_id"`
+	InterventionID  pgtype.UUID `json:"intervention_id"`
+	ProgressPercent float64     `json:"progress_percent"`
+}
+
+// Deletes a unit's route and returns what was cleared (intervention link and progress)
+func (q *Queries) ResetUnitRoute(ctx context.Context, unitID pgtype.UUID) (ResetUnitRouteRow, error) {
+	row := q.db.QueryRow(ctx, resetUnitRoute, unitID)
+	var i ResetUnitRouteRow
+	err := row.Scan(
+		&i.UnitID,
+		&i.InterventionID,
+		&i.ProgressPercent,
+	)
+	return i, err
+}
+
 const saveUnitRoute = `-- name: SaveUnitRoute :one
 
 INSERT INTO unit_routes (unit_id, intervention_id, route_geometry, route_length_meters, estimated_duration_seconds, progress_percent)
@@ -259,6 +549,7 @@ ON CONFLICT (unit_id) DO UPDATE SET
     route_length_meters = EXCLUDED.route_length_meters,
     estimated_duration_seconds = EXCLUDED.estimated_duration_seconds,
     progress_percent = 0,
+    calculated_at = NOW(),
     updated_at = NOW()
 RETURNING 
     unit_id,
@@ -316,6 +607,101 @@ func (q *Queries) SaveUnitRoute(ctx context.Context, arg SaveUnitRouteParams) (S
 	return i, err
 }
 
+const snapPointToUnitRoute = `-- name: SnapPointToUnitRoute :one
+SELECT
+    ST_X(ST_ClosestPoint(route_geometry, ST_SetSRID(ST_MakePoint($1::float8, $2::float8), 4326)))::float8 AS snapped_lon,
+    ST_Y(ST_ClosestPoint(route_geometry, ST_SetSRID(ST_MakePoint($1::float8, $2::float8), 4326)))::float8 AS snapped_lat,
+    LEAST(100, ST_LineLocatePoint(route_geometry, ST_SetSRID(ST_MakePoint($1::float8, $2::float8), 4326)) * 100)::float8 AS progress_percent,
+    ST_Distance(route_geometry::geography, ST_SetSRID(ST_MakePoint($1::float8, $2::float8), 4326)::geography)::float8 AS distance_meters,
+    progress_percent AS current_progress_percent
+FROM unit_routes
+WHERE unit_id = $3
+`
+
+type SnapPointToUnitRouteParams struct {
+	Longitude float64     `json:"longitude"`
+	Latitude  float64     `json:"latitude"`
+	UnitID    pgtype.UUID `json:"unit_id"`
+}
+
+type SnapPointToUnitRouteRow struct {
+	SnappedLon             float64 `json:"snapped_lon"`
+	SnappedLat             float64 `json:"snapped_lat"`
+	ProgressPercent        float64 `json:"progress_percent"`
+	DistanceMeters         float64 `json:"distance_meters"`
+	CurrentProgressPercent float64 `json:"current_progress_percent"`
+}
+
+// Nearest point of the unit's route to a position and the progress it implies, without updating the route
+func (q *Queries) SnapPointToUnitRoute(ctx context.Context, arg SnapPointToUnitRouteParams) (SnapPointToUnitRouteRow, error) {
+	row := q.db.QueryRow(ctx, snapPointToUnitRoute, arg.Longitude, arg.Latitude, arg.UnitID)
+	var i SnapPointToUnitRouteRow
+	err := row.Scan(
+		&i.SnappedLon,
+		&i.SnappedLat,
+		&i.ProgressPercent,
+		&i.DistanceMeters,
+		&i.CurrentProgressPercent,
+	)
+	return i, err
+}
+
+const snapPositionToUnitRoute = `-- name: SnapPositionToUnitRoute :one
+UPDATE unit_routes ur
+SET
+    progress_percent = GREATEST(ur.progress_percent, LEAST(100, snap.fraction * 100)),
+    updated_at = NOW()
+FROM (
+    SELECT
+        unit_id,
+        ST_LineLocatePoint(route_geometry, ST_SetSRID(ST_MakePoint($1::float8, $2::float8), 4326)) AS fraction,
+        ST_Distance(route_geometry::geography, ST_SetSRID(ST_MakePoint($1::float8, $2::float8), 4326)::geography) AS distance_meters
+    FROM unit_routes
+    WHERE unit_id = $3
+) AS snap
+WHERE ur.unit_id = snap.unit_id
+    AND snap.distance_meters <= $4::float8
+RETURNING
+    ur.progress_percent,
+    ST_X(ST_LineInterpolatePoint(ur.route_geometry, ur.progress_percent / 100.0))::float8 AS snapped_lon,
+    ST_Y(ST_LineInterpolatePoint(ur.route_geometry, ur.progress_percent / 100.0))::float8 AS snapped_lat,
+    snap.distance_meters::float8 AS distance_meters
+`
+
+type SnapPositionToUnitRouteParams struct {
+	Longitude         float64     `json:"longitude"`
+	Latitude          float64     `json:"latitude"`
+	UnitID            pgtype.UUID `json:"unit_id"`
+	MaxDistanceMeters float64     `json:"max_distance_meters"`
+}
+
+type SnapPositionToUnitRouteRow struct {
+	ProgressPercent float64 `json:"progress_percent"`
+	SnappedLon      float64 `json:"snapped_lon"`
+	SnappedLat      float64 `json:"snapped_lat"`
+	DistanceMeters  float64 `json:"distance_meters"`
+}
+
+// Projects a raw position onto the unit's route and advances progress when the position
+// is within max_distance_meters of the route. Progress never moves backwards so GPS noise
+// cannot pull the unit back. Returns no row when the unit has no route or is too far from it.
+func (q *Queries) SnapPositionToUnitRoute(ctx context.Context, arg SnapPositionToUnitRouteParams) (SnapPositionToUnitRouteRow, error) {
+	row := q.db.QueryRow(ctx, snapPositionToUnitRoute,
+		arg.Longitude,
+		arg.Latitude,
+		arg.UnitID,
+		arg.MaxDistanceMeters,
+	)
+	var i SnapPositionToUnitRouteRow
+	err := row.Scan(
+		&i.ProgressPercent,
+		&i.SnappedLon,
+		&i.SnappedLat,
+		&i.DistanceMeters,
+	)
+	return i, err
+}
+
 const updateRouteProgress = `-- name: UpdateRouteProgress :one
 UPDATE unit_routes
 SET 
@@ -359,3 +745,64 @@ func (q *Queries) UpdateRouteProgress(ctx context.Context, arg UpdateRouteProgre
 	)
 	return i, err
 }
+
+const upsertCachedRoute = `-- name: UpsertCachedRoute :exec
+INSERT INTO route_cache (
+    from_lat_key,
+    from_lon_key,
+    to_lat_key,
+    to_lon_key,
+    route_geojson,
+    route_length_meters,
+    estimated_duration_seconds,
+    already_at_destination,
+    calculated_at,
+    expires_at
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    NOW(),
+    NOW() + $9::interval
+)
+ON CONFLICT (from_lat_key, from_lon_key, to_lat_key, to_lon_key) DO UPDATE
+SET route_geojson = EXCLUDED.route_geojson,
+    route_length_meters = EXCLUDED.route_length_meters,
+    estimated_duration_seconds = EXCLUDED.estimated_duration_seconds,
+    already_at_destination = EXCLUDED.already_at_destination,
+    calculated_at = EXCLUDED.calculated_at,
+    expires_at = EXCLUDED.expires_at
+`
+
+type UpsertCachedRouteParams struct {
+	FromLatKey               int32           `json:"from_lat_key"`
+	FromLonKey               int32           `json:"from_lon_key"`
+	ToLatKey                 int32           `json:"to_lat_key"`
+	ToLonKey                 int32           `json:"to_lon_key"`
+	RouteGeojson             string          `json:"route_geojson"`
+	RouteLengthMeters        float64         `json:"route_length_meters"`
+	EstimatedDurationSeconds float64         `json:"estimated_duration_seconds"`
+	AlreadyAtDestination     bool            `json:"already_at_destination"`
+	Ttl                      pgtype.Interval `json:"ttl"`
+}
+
+// Stores a calculated route, replacing any previous entry for the same rounded point pair
+func (q *Queries) UpsertCachedRoute(ctx context.Context, arg UpsertCachedRouteParams) error {
+	_, err := q.db.Exec(ctx, upsertCachedRoute,
+		arg.FromLatKey,
+		arg.FromLonKey,
+		arg.ToLatKey,
+		arg.ToLonKey,
+		arg.RouteGeojson,
+		arg.RouteLengthMeters,
+		arg.EstimatedDurationSeconds,
+		arg.AlreadyAtDestination,
+		arg.Ttl,
+	)
+	return err
+}
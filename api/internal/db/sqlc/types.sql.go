@@ -9,6 +9,19 @@ import (
 	"context"
 )
 
+const getEventTypeDefaultSeverity = `-- name: GetEventTypeDefaultSeverity :one
+SELECT default_severity
+FROM event_types
+WHERE code = $1
+`
+
+func (q *Queries) GetEventTypeDefaultSeverity(ctx context.Context, code string) (int32, error) {
+	row := q.db.QueryRow(ctx, getEventTypeDefaultSeverity, code)
+	var default_severity int32
+	err := row.Scan(&default_severity)
+	return default_severity, err
+}
+
 const listEventTypes = `-- name: ListEventTypes :many
 SELECT
     code,
@@ -17,7 +30,8 @@ SELECT
     default_severity,
     recommended_unit_types,
     created_at,
-    updated_at
+    updated_at,
+    recommended_unit_counts
 FROM event_types
 ORDER BY default_severity DESC, name
 `
@@ -39,6 +53,7 @@ func (q *Queries) ListEventTypes(ctx context.Context) ([]EventType, error) {
 			&i.RecommendedUnitTypes,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.RecommendedUnitCounts,
 		); err != nil {
 			return nil, err
 		}
@@ -72,6 +72,46 @@ func (q *Queries) AssignMicrobit(ctx context.Context, arg AssignMicrobitParams)
 	return i, err
 }
 
+const countUnitsByStatus = `-- name: CountUnitsByStatus :many
+SELECT
+    u.status,
+    u.unit_type_code,
+    COUNT(*)::bigint AS unit_count
+FROM units u
+GROUP BY u.status, u.unit_type_code
+ORDER BY u.status, u.unit_type_code
+`
+
+type CountUnitsByStatusRow struct {
+	Status       UnitStatus `json:"status"`
+	UnitTypeCode string     `json:"unit_type_code"`
+	UnitCount    int64      `json:"unit_count"`
+}
+
+func (q *Queries) CountUnitsByStatus(ctx context.Context) ([]CountUnitsByStatusRow, error) {
+	rows, err := q.db.Query(ctx, countUnitsByStatus)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountUnitsByStatusRow
+	for rows.Next() {
+		var i CountUnitsByStatusRow
+		if err := rows.Scan(
+			&i.Status,
+			&i.UnitTypeCode,
+			&i.UnitCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const createUnit = `-- name: CreateUnit :one
 INSERT INTO units (
     call_sign,
@@ -208,7 +248,8 @@ SELECT
     (COALESCE(ST_Y(u.location::geometry)::double precision, 0::double precision))::double precision AS latitude,
     u.last_contact_at,
     u.created_at,
-    u.updated_at
+    u.updated_at,
+    u.version
 FROM units u
 LEFT JOIN locations l ON u.location_id = l.id
 WHERE u.id = $1
@@ -227,6 +268,7 @@ type GetUnitRow struct {
 	LastContactAt pgtype.Timestamptz `json:"last_contact_at"`
 	CreatedAt     pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+	Version       int32              `json:"version"`
 }
 
 func (q *Queries) GetUnit(ctx context.Context, id pgtype.UUID) (GetUnitRow, error) {
@@ -245,6 +287,7 @@ func (q *Queries) GetUnit(ctx context.Context, id pgtype.UUID) (GetUnitRow, erro
 		&i.LastContactAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
 	)
 	return i, err
 }
@@ -303,6 +346,41 @@ func (q *Queries) GetUnitByMicrobitID(ctx context.Context, microbitID *string) (
 	return i, err
 }
 
+const getUnitTrackLine = `-- name: GetUnitTrackLine :one
+SELECT
+    COUNT(*)::bigint AS point_count,
+    (CASE WHEN COUNT(*) >= 2
+        THEN ST_AsGeoJSON(ST_MakeLine(location::geometry ORDER BY recorded_at, id))
+    END)::text AS line_geojson
+FROM unit_telemetry
+WHERE unit_id = $1
+  AND recorded_at >= $2
+  AND recorded_at < $3
+`
+
+type GetUnitTrackLineParams struct {
+	UnitID pgtype.UUID        `json:"unit_id"`
+	Since  pgtype.Timestamptz `json:"since"`
+	Until  pgtype.Timestamptz `json:"until"`
+}
+
+type GetUnitTrackLineRow struct {
+	PointCount  int64   `json:"point_count"`
+	LineGeojson *string `json:"line_geojson"`
+}
+
+// Telemetry positions of a unit in [since, until) joined in time order; line_geojson is null
+// when there are fewer than two points
+func (q *Queries) GetUnitTrackLine(ctx context.Context, arg GetUnitTrackLineParams) (GetUnitTrackLineRow, error) {
+	row := q.db.QueryRow(ctx, getUnitTrackLine, arg.UnitID, arg.Since, arg.Until)
+	var i GetUnitTrackLineRow
+	err := row.Scan(
+		&i.PointCount,
+		&i.LineGeojson,
+	)
+	return i, err
+}
+
 const insertUnitTelemetry = `-- name: InsertUnitTelemetry :one
 INSERT INTO unit_telemetry (
     unit_id,
@@ -393,15 +471,19 @@ SELECT
     ST_Distance(u.location, ST_SetSRID(ST_MakePoint($1::double precision, $2::double precision), 4326)::geography)::double precision AS distance
 FROM units u
 LEFT JOIN locations l ON u.location_id = l.id
-WHERE (u.status = 'available' OR u.status = 'available_hidden')
+WHERE ($4::text[] IS NULL OR u.status::text = ANY($4::text[]))
+AND ($4::text[] IS NOT NULL OR u.status = 'available' OR u.status = 'available_hidden')
 AND ($3::text[] IS NULL OR u.unit_type_code = ANY($3::text[]))
+AND ($5::double precision IS NULL OR ST_DWithin(u.location, ST_SetSRID(ST_MakePoint($1::double precision, $2::double precision), 4326)::geography, $5::double precision))
 ORDER BY distance ASC
 `
 
 type ListAvailableUnitsNearbyParams struct {
-	Longitude float64  `json:"longitude"`
-	Latitude  float64  `json:"latitude"`
-	UnitTypes []string `json:"unit_types"`
+	Longitude    float64  `json:"longitude"`
+	Latitude     float64  `json:"latitude"`
+	UnitTypes    []string `json:"unit_types"`
+	Statuses     []string `json:"statuses"`
+	RadiusMeters *float64 `json:"radius_meters"`
 }
 
 type ListAvailableUnitsNearbyRow struct {
@@ -420,8 +502,15 @@ type ListAvailableUnitsNearbyRow struct {
 	Distance      float64            `json:"distance"`
 }
 
+// Units closest first; statuses defaults to available and available_hidden, radius_meters to no limit
 func (q *Queries) ListAvailableUnitsNearby(ctx context.Context, arg ListAvailableUnitsNearbyParams) ([]ListAvailableUnitsNearbyRow, error) {
-	rows, err := q.db.Query(ctx, listAvailableUnitsNearby, arg.Longitude, arg.Latitude, arg.UnitTypes)
+	rows, err := q.db.Query(ctx, listAvailableUnitsNearby,
+		arg.Longitude,
+		arg.Latitude,
+		arg.UnitTypes,
+		arg.Statuses,
+		arg.RadiusMeters,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -454,6 +543,304 @@ func (q *Queries) ListAvailableUnitsNearby(ctx context.Context, arg ListAvailabl
 	return items, nil
 }
 
+const listUnitStatusValues = `-- name: ListUnitStatusValues :many
+SELECT unnest(enum_range(NULL::unit_status))::text AS status
+`
+
+// Values of the unit_status enum, used to check UNIT_STATUSES at startup
+func (q *Queries) ListUnitStatusValues(ctx context.Context) ([]string, error) {
+	rows, err := q.db.Query(ctx, listUnitStatusValues)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var status string
+		if err := rows.Scan(&status); err != nil {
+			return nil, err
+		}
+		items = append(items, status)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnitTelemetry = `-- name: ListUnitTelemetry :many
+SELECT
+    id,
+    unit_id,
+    recorded_at,
+    (COALESCE(ST_X(location::geometry)::double precision, 0::double precision))::double precision AS longitude,
+    (COALESCE(ST_Y(location::geometry)::double precision, 0::double precision))::double precision AS latitude,
+    heading,
+    speed_kmh,
+    status_snapshot
+FROM unit_telemetry
+WHERE unit_id = $1
+  AND ($2::timestamptz IS NULL OR recorded_at >= $2::timestamptz)
+  AND ($3::timestamptz IS NULL OR recorded_at < $3::timestamptz)
+ORDER BY recorded_at DESC, id DESC
+LIMIT $4 OFFSET $5
+`
+
+type ListUnitTelemetryParams struct {
+	UnitID pgtype.UUID        `json:"unit_id"`
+	Since  pgtype.Timestamptz `json:"since"`
+	Until  pgtype.Timestamptz `json:"until"`
+	Limit  int32              `json:"limit"`
+	Offset int32              `json:"offset"`
+}
+
+type ListUnitTelemetryRow struct {
+	ID             int64              `json:"id"`
+	UnitID         pgtype.UUID        `json:"unit_id"`
+	RecordedAt     pgtype.Timestamptz `json:"recorded_at"`
+	Longitude      float64            `json:"longitude"`
+	Latitude       float64            `json:"latitude"`
+	Heading        *int32             `json:"heading"`
+	SpeedKmh       *float64           `json:"speed_kmh"`
+	StatusSnapshot []byte             `json:"status_snapshot"`
+}
+
+// Telemetry snapshots of a unit, newest first; since and until are optional bounds on recorded_at
+func (q *Queries) ListUnitTelemetry(ctx context.Context, arg ListUnitTelemetryParams) ([]ListUnitTelemetryRow, error) {
+	rows, err := q.db.Query(ctx, listUnitTelemetry,
+		arg.UnitID,
+		arg.Since,
+		arg.Until,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUnitTelemetryRow
+	for rows.Next() {
+		var i ListUnitTelemetryRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UnitID,
+			&i.RecordedAt,
+			&i.Longitude,
+			&i.Latitude,
+			&i.Heading,
+			&i.SpeedKmh,
+			&i.StatusSnapshot,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnitTelemetryGaps = `-- name: ListUnitTelemetryGaps :many
+WITH samples AS (
+    SELECT ut.recorded_at
+    FROM unit_telemetry ut
+    WHERE ut.unit_id = $1
+      AND ut.recorded_at >= $2::timestamptz
+      AND ut.recorded_at < $3::timestamptz
+    UNION ALL SELECT $2::timestamptz
+    UNION ALL SELECT $3::timestamptz
+),
+ordered AS (
+    SELECT
+        LAG(recorded_at) OVER (ORDER BY recorded_at) AS gap_start,
+        recorded_at AS gap_end
+    FROM samples
+)
+SELECT
+    gap_start::timestamptz AS gap_start,
+    gap_end::timestamptz AS gap_end,
+    EXTRACT(EPOCH FROM gap_end - gap_start)::double precision AS gap_seconds
+FROM ordered
+WHERE gap_start IS NOT NULL
+  AND gap_end - gap_start > $4::interval
+ORDER BY gap_start
+LIMIT $5
+`
+
+type ListUnitTelemetryGapsParams struct {
+	UnitID    pgtype.UUID        `json:"unit_id"`
+	FromTime  pgtype.Timestamptz `json:"from_time"`
+	ToTime    pgtype.Timestamptz `json:"to_time"`
+	Threshold pgtype.Interval    `json:"threshold"`
+	MaxGaps   int32              `json:"max_gaps"`
+}
+
+type ListUnitTelemetryGapsRow struct {
+	GapStart   pgtype.Timestamptz `json:"gap_start"`
+	GapEnd     pgtype.Timestamptz `json:"gap_end"`
+	GapSeconds float64            `json:"gap_seconds"`
+}
+
+// Intervals longer than threshold without telemetry in [from_time, to_time); the range bounds count as samples so leading and trailing silences are reported too
+func (q *Queries) ListUnitTelemetryGaps(ctx context.Context, arg ListUnitTelemetryGapsParams) ([]ListUnitTelemetryGapsRow, error) {
+	rows, err := q.db.Query(ctx, listUnitTelemetryGaps,
+		arg.UnitID,
+		arg.FromTime,
+		arg.ToTime,
+		arg.Threshold,
+		arg.MaxGaps,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUnitTelemetryGapsRow
+	for rows.Next() {
+		var i ListUnitTelemetryGapsRow
+		if err := rows.Scan(
+			&i.GapStart,
+			&i.GapEnd,
+			&i.GapSeconds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnitTrackPoints = `-- name: ListUnitTrackPoints :many
+SELECT
+    recorded_at,
+    (COALESCE(ST_X(location::geometry)::double precision, 0::double precision))::double precision AS longitude,
+    (COALESCE(ST_Y(location::geometry)::double precision, 0::double precision))::double precision AS latitude,
+    heading,
+    speed_kmh
+FROM unit_telemetry
+WHERE unit_id = $1
+  AND recorded_at >= $2
+  AND recorded_at < $3
+ORDER BY recorded_at, id
+LIMIT $4
+`
+
+type ListUnitTrackPointsParams struct {
+	UnitID pgtype.UUID        `json:"unit_id"`
+	Since  pgtype.Timestamptz `json:"since"`
+	Until  pgtype.Timestamptz `json:"until"`
+	Limit  int32              `json:"limit"`
+}
+
+type ListUnitTrackPointsRow struct {
+	RecordedAt pgtype.Timestamptz `json:"recorded_at"`
+	Longitude  float64            `json:"longitude"`
+	Latitude   float64            `json:"latitude"`
+	Heading    *int32             `json:"heading"`
+	SpeedKmh   *float64           `json:"speed_kmh"`
+}
+
+// Telemetry points of a unit in [since, until), oldest first
+func (q *Queries) ListUnitTrackPoints(ctx context.Context, arg ListUnitTrackPointsParams) ([]ListUnitTrackPointsRow, error) {
+	rows, err := q.db.Query(ctx, listUnitTrackPoints,
+		arg.UnitID,
+		arg.Since,
+		arg.Until,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUnitTrackPointsRow
+	for rows.Next() {
+		var i ListUnitTrackPointsRow
+		if err := rows.Scan(
+			&i.RecordedAt,
+			&i.Longitude,
+			&i.Latitude,
+			&i.Heading,
+			&i.SpeedKmh,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnitTypeObservedSpeeds = `-- name: ListUnitTypeObservedSpeeds :many
+SELECT
+    ut.code AS unit_type_code,
+    ut.speed_kmh AS configured_speed_kmh,
+    COUNT(DISTINCT t.unit_id)::bigint AS unit_count,
+    COUNT(*)::bigint AS moving_readings,
+    AVG(t.speed_kmh)::double precision AS avg_speed_kmh
+FROM unit_telemetry t
+JOIN units u ON u.id = t.unit_id
+JOIN unit_types ut ON ut.code = u.unit_type_code
+WHERE t.recorded_at >= $1
+  AND t.recorded_at < $2
+  AND t.speed_kmh > $3::double precision
+  AND ($4::text IS NULL OR ut.code = $4::text)
+GROUP BY ut.code, ut.speed_kmh
+ORDER BY ut.code
+`
+
+type ListUnitTypeObservedSpeedsParams struct {
+	FromTime    pgtype.Timestamptz `json:"from_time"`
+	ToTime      pgtype.Timestamptz `json:"to_time"`
+	MinSpeedKmh float64            `json:"min_speed_kmh"`
+	UnitType    *string            `json:"unit_type"`
+}
+
+type ListUnitTypeObservedSpeedsRow struct {
+	UnitTypeCode       string  `json:"unit_type_code"`
+	ConfiguredSpeedKmh *int32  `json:"configured_speed_kmh"`
+	UnitCount          int64   `json:"unit_count"`
+	MovingReadings     int64   `json:"moving_readings"`
+	AvgSpeedKmh        float64 `json:"avg_speed_kmh"`
+}
+
+// Average telemetry speed per unit type for readings in [from_time, to_time) faster than min_speed_kmh.
+// Units are grouped by their current unit type.
+func (q *Queries) ListUnitTypeObservedSpeeds(ctx context.Context, arg ListUnitTypeObservedSpeedsParams) ([]ListUnitTypeObservedSpeedsRow, error) {
+	rows, err := q.db.Query(ctx, listUnitTypeObservedSpeeds,
+		arg.FromTime,
+		arg.ToTime,
+		arg.MinSpeedKmh,
+		arg.UnitType,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUnitTypeObservedSpeedsRow
+	for rows.Next() {
+		var i ListUnitTypeObservedSpeedsRow
+		if err := rows.Scan(
+			&i.UnitTypeCode,
+			&i.ConfiguredSpeedKmh,
+			&i.UnitCount,
+			&i.MovingReadings,
+			&i.AvgSpeedKmh,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listUnits = `-- name: ListUnits :many
 SELECT
     u.id,
@@ -467,7 +854,8 @@ SELECT
     (COALESCE(ST_Y(u.location::geometry)::double precision, 0::double precision))::double precision AS latitude,
     u.last_contact_at,
     u.created_at,
-    u.updated_at
+    u.updated_at,
+    u.version
 FROM units u
 LEFT JOIN locations l ON u.location_id = l.id
 ORDER BY u.call_sign
@@ -486,6 +874,7 @@ type ListUnitsRow struct {
 	LastContactAt pgtype.Timestamptz `json:"last_contact_at"`
 	CreatedAt     pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+	Version       int32              `json:"version"`
 }
 
 func (q *Queries) ListUnits(ctx context.Context) ([]ListUnitsRow, error) {
@@ -510,6 +899,87 @@ func (q *Queries) ListUnits(ctx context.Context) ([]ListUnitsRow, error) {
 			&i.LastContactAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnitsAwayFromBase = `-- name: ListUnitsAwayFromBase :many
+SELECT
+    u.id,
+    u.call_sign,
+    u.unit_type_code,
+    u.status,
+    u.microbit_id,
+    u.location_id,
+    l.name AS home_base_name,
+    (COALESCE(ST_X(u.location::geometry)::double precision, 0::double precision))::double precision AS longitude,
+    (COALESCE(ST_Y(u.location::geometry)::double precision, 0::double precision))::double precision AS latitude,
+    (ST_X(l.location::geometry))::double precision AS home_base_longitude,
+    (ST_Y(l.location::geometry))::double precision AS home_base_latitude,
+    u.last_contact_at,
+    u.created_at,
+    u.updated_at,
+    ST_Distance(u.location, l.location)::double precision AS distance_from_base
+FROM units u
+JOIN locations l ON u.location_id = l.id
+WHERE u.status IN ('available', 'available_hidden')
+  AND u.location IS NOT NULL
+  AND NOT ST_DWithin(u.location, l.location, $1::double precision)
+ORDER BY distance_from_base DESC, u.call_sign
+`
+
+type ListUnitsAwayFromBaseRow struct {
+	ID                pgtype.UUID        `json:"id"`
+	CallSign          string             `json:"call_sign"`
+	UnitTypeCode      string             `json:"unit_type_code"`
+	Status            UnitStatus         `json:"status"`
+	MicrobitID        *string            `json:"microbit_id"`
+	LocationID        pgtype.UUID        `json:"location_id"`
+	HomeBaseName      string             `json:"home_base_name"`
+	Longitude         float64            `json:"longitude"`
+	Latitude          float64            `json:"latitude"`
+	HomeBaseLongitude float64            `json:"home_base_longitude"`
+	HomeBaseLatitude  float64            `json:"home_base_latitude"`
+	LastContactAt     pgtype.Timestamptz `json:"last_contact_at"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
+	DistanceFromBase  float64            `json:"distance_from_base"`
+}
+
+// Available units farther than min_distance meters from their home base, farthest first
+func (q *Queries) ListUnitsAwayFromBase(ctx context.Context, minDistance float64) ([]ListUnitsAwayFromBaseRow, error) {
+	rows, err := q.db.Query(ctx, listUnitsAwayFromBase, minDistance)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUnitsAwayFromBaseRow
+	for rows.Next() {
+		var i ListUnitsAwayFromBaseRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CallSign,
+			&i.UnitTypeCode,
+			&i.Status,
+			&i.MicrobitID,
+			&i.LocationID,
+			&i.HomeBaseName,
+			&i.Longitude,
+			&i.Latitude,
+			&i.HomeBaseLongitude,
+			&i.HomeBaseLatitude,
+			&i.LastContactAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DistanceFromBase,
 		); err != nil {
 			return nil, err
 		}
@@ -589,6 +1059,75 @@ func (q *Queries) ListUnitsByLocation(ctx context.Context, locationID pgtype.UUI
 	return items, nil
 }
 
+const listUnitsNearPoint = `-- name: ListUnitsNearPoint :many
+SELECT
+    u.id,
+    u.call_sign,
+    u.unit_type_code,
+    u.status,
+    ST_X(u.location::geometry)::double precision AS longitude,
+    ST_Y(u.location::geometry)::double precision AS latitude,
+    ST_Distance(u.location, ST_SetSRID(ST_MakePoint($1::double precision, $2::double precision), 4326)::geography)::double precision AS distance_meters
+FROM units u
+WHERE u.location IS NOT NULL
+  AND ($3::text[] IS NULL OR u.unit_type_code = ANY($3::text[]))
+  AND u.status::text = ANY($4::text[])
+ORDER BY distance_meters ASC
+LIMIT $5
+`
+
+type ListUnitsNearPointParams struct {
+	Longitude float64  `json:"longitude"`
+	Latitude  float64  `json:"latitude"`
+	UnitTypes []string `json:"unit_types"`
+	Statuses  []string `json:"statuses"`
+	MaxUnits  int32    `json:"max_units"`
+}
+
+type ListUnitsNearPointRow struct {
+	ID             pgtype.UUID `json:"id"`
+	CallSign       string      `json:"call_sign"`
+	UnitTypeCode   string      `json:"unit_type_code"`
+	Status         UnitStatus  `json:"status"`
+	Longitude      float64     `json:"longitude"`
+	Latitude       float64     `json:"latitude"`
+	DistanceMeters float64     `json:"distance_meters"`
+}
+
+func (q *Queries) ListUnitsNearPoint(ctx context.Context, arg ListUnitsNearPointParams) ([]ListUnitsNearPointRow, error) {
+	rows, err := q.db.Query(ctx, listUnitsNearPoint,
+		arg.Longitude,
+		arg.Latitude,
+		arg.UnitTypes,
+		arg.Statuses,
+		arg.MaxUnits,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUnitsNearPointRow
+	for rows.Next() {
+		var i ListUnitsNearPointRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CallSign,
+			&i.UnitTypeCode,
+			&i.Status,
+			&i.Longitude,
+			&i.Latitude,
+			&i.DistanceMeters,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listVisibleUnits = `-- name: ListVisibleUnits :many
 SELECT
     u.id,
@@ -657,6 +1196,18 @@ func (q *Queries) ListVisibleUnits(ctx context.Context) ([]ListVisibleUnitsRow,
 	return items, nil
 }
 
+const lockUnit = `-- name: LockUnit :one
+SELECT status FROM units WHERE id = $1 FOR UPDATE
+`
+
+// Row-locks a unit so concurrent status transitions are serialised
+func (q *Queries) LockUnit(ctx context.Context, id pgtype.UUID) (UnitStatus, error) {
+	row := q.db.QueryRow(ctx, lockUnit, id)
+	var status UnitStatus
+	err := row.Scan(&status)
+	return status, err
+}
+
 const unassignMicrobit = `-- name: UnassignMicrobit :one
 UPDATE units
 SET
@@ -725,7 +1276,7 @@ SET
     )::geography,
     last_contact_at = COALESCE($3, NOW()),
     updated_at = NOW()
-WHERE units.id = $4
+WHERE units.id = $4 AND ($5::int IS NULL OR units.version = $5::int)
 RETURNING
     id,
     call_sign,
@@ -738,14 +1289,16 @@ RETURNING
     (COALESCE(ST_Y(location::geometry)::double precision, 0::double precision))::double precision AS latitude,
     last_contact_at,
     created_at,
-    updated_at
+    updated_at,
+    version
 `
 
 type UpdateUnitLocationParams struct {
-	Longitude   float64            `json:"longitude"`
-	Latitude    float64            `json:"latitude"`
-	ContactTime pgtype.Timestamptz `json:"contact_time"`
-	ID          pgtype.UUID        `json:"id"`
+	Longitude       float64            `json:"longitude"`
+	Latitude        float64            `json:"latitude"`
+	ContactTime     pgtype.Timestamptz `json:"contact_time"`
+	ID              pgtype.UUID        `json:"id"`
+	ExpectedVersion *int32             `json:"expected_version"`
 }
 
 type UpdateUnitLocationRow struct {
@@ -761,6 +1314,7 @@ type UpdateUnitLocationRow struct {
 	LastContactAt pgtype.Timestamptz `json:"last_contact_at"`
 	CreatedAt     pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+	Version       int32              `json:"version"`
 }
 
 func (q *Queries) UpdateUnitLocation(ctx context.Context, arg UpdateUnitLocationParams) (UpdateUnitLocationRow, error) {
@@ -769,6 +1323,7 @@ func (q *Queries) UpdateUnitLocation(ctx context.Context, arg UpdateUnitLocation
 		arg.Latitude,
 		arg.ContactTime,
 		arg.ID,
+		arg.ExpectedVersion,
 	)
 	var i UpdateUnitLocationRow
 	err := row.Scan(
@@ -784,6 +1339,7 @@ func (q *Queries) UpdateUnitLocation(ctx context.Context, arg UpdateUnitLocation
 		&i.LastContactAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
 	)
 	return i, err
 }
@@ -862,7 +1418,7 @@ UPDATE units
 SET
     location_id = $1,
     updated_at = NOW()
-WHERE units.id = $2
+WHERE units.id = $2 AND ($3::int IS NULL OR units.version = $3::int)
 RETURNING
     id,
     call_sign,
@@ -875,12 +1431,14 @@ RETURNING
     (COALESCE(ST_Y(location::geometry)::double precision, 0::double precision))::double precision AS latitude,
     last_contact_at,
     created_at,
-    updated_at
+    updated_at,
+    version
 `
 
 type UpdateUnitStationParams struct {
-	LocationID pgtype.UUID `json:"location_id"`
-	ID         pgtype.UUID `json:"id"`
+	LocationID      pgtype.UUID `json:"location_id"`
+	ID              pgtype.UUID `json:"id"`
+	ExpectedVersion *int32      `json:"expected_version"`
 }
 
 type UpdateUnitStationRow struct {
@@ -896,10 +1454,11 @@ type UpdateUnitStationRow struct {
 	LastContactAt pgtype.Timestamptz `json:"last_contact_at"`
 	CreatedAt     pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+	Version       int32              `json:"version"`
 }
 
 func (q *Queries) UpdateUnitStation(ctx context.Context, arg UpdateUnitStationParams) (UpdateUnitStationRow, error) {
-	row := q.db.QueryRow(ctx, updateUnitStation, arg.LocationID, arg.ID)
+	row := q.db.QueryRow(ctx, updateUnitStation, arg.LocationID, arg.ID, arg.ExpectedVersion)
 	var i UpdateUnitStationRow
 	err := row.Scan(
 		&i.ID,
@@ -914,6 +1473,7 @@ func (q *Queries) UpdateUnitStation(ctx context.Context, arg UpdateUnitStationPa
 		&i.LastContactAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
 	)
 	return i, err
 }
@@ -923,7 +1483,7 @@ UPDATE units
 SET
     status = $2,
     updated_at = NOW()
-WHERE units.id = $1
+WHERE units.id = $1 AND ($3::int IS NULL OR units.version = $3::int)
 RETURNING
     id,
     call_sign,
@@ -936,12 +1496,14 @@ RETURNING
     (COALESCE(ST_Y(location::geometry)::double precision, 0::double precision))::double precision AS latitude,
     last_contact_at,
     created_at,
-    updated_at
+    updated_at,
+    version
 `
 
 type UpdateUnitStatusParams struct {
-	ID     pgtype.UUID `json:"id"`
-	Status UnitStatus  `json:"status"`
+	ID              pgtype.UUID `json:"id"`
+	Status          UnitStatus  `json:"status"`
+	ExpectedVersion *int32      `json:"expected_version"`
 }
 
 type UpdateUnitStatusRow struct {
@@ -957,10 +1519,11 @@ type UpdateUnitStatusRow struct {
 	LastContactAt pgtype.Timestamptz `json:"last_contact_at"`
 	CreatedAt     pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+	Version       int32              `json:"version"`
 }
 
 func (q *Queries) UpdateUnitStatus(ctx context.Context, arg UpdateUnitStatusParams) (UpdateUnitStatusRow, error) {
-	row := q.db.QueryRow(ctx, updateUnitStatus, arg.ID, arg.Status)
+	row := q.db.QueryRow(ctx, updateUnitStatus, arg.ID, arg.Status, arg.ExpectedVersion)
 	var i UpdateUnitStatusRow
 	err := row.Scan(
 		&i.ID,
@@ -975,6 +1538,7 @@ func (q *Queries) UpdateUnitStatus(ctx context.Context, arg UpdateUnitStatusPara
 		&i.LastContactAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
 	)
 	return i, err
 }
@@ -36,33 +36,36 @@ type UserClaims struct {
 	jwt.RegisteredClaims
 	PreferredUsername string   `json:"preferred_username"`
 	Email             string   `json:"email"`
+	AuthorizedParty   string   `json:"azp"`
 	RealmAccess       struct {
 		Roles []string `json:"roles"`
 	} `json:"realm_access"`
 }
 
+// jwksHTTPTimeout bounds a single JWKS fetch so retries stay responsive.
+const jwksHTTPTimeout = 10 * time.Second
+
 // AuthMiddleware handles JWT validation using Keycloak's JWKS.
 type AuthMiddleware struct {
+	// jwks is set once, before ready is closed; read it only after checking Ready.
 	jwks         keyfunc.Keyfunc
+	jwksCancel   context.CancelFunc
+	jwksURL      string
+	ready        chan struct{}
 	cancelFn     context.CancelFunc
 	validIssuers []string
 	log          zerolog.Logger
 }
 
 // NewAuthMiddleware creates a new authentication middleware with JWKS from Keycloak.
+// If Keycloak is unreachable, the JWKS keeps being fetched in the background and
+// the middleware rejects requests with 503 until keys are available.
 func NewAuthMiddleware(ctx context.Context, cfg config.KeycloakConfig, log zerolog.Logger) (*AuthMiddleware, error) {
 	jwksURL := fmt.Sprintf("%s/realms/%s/protocol/openid-connect/certs", cfg.URL, cfg.Realm)
 
 	// Create a cancellable context for JWKS refresh goroutine
 	jwksCtx, cancelFn := context.WithCancel(ctx)
 
-	// Create JWKS with automatic refresh
-	jwks, err := keyfunc.NewDefaultCtx(jwksCtx, []string{jwksURL})
-	if err != nil {
-		cancelFn()
-		return nil, fmt.Errorf("failed to create JWKS from %s: %w", jwksURL, err)
-	}
-
 	// Accept tokens from both internal and public Keycloak URLs
 	internalIssuer := fmt.Sprintf("%s/realms/%s", cfg.URL, cfg.Realm)
 	publicIssuer := fmt.Sprintf("%s/realms/%s", cfg.PublicURL, cfg.Realm)
@@ -76,16 +79,95 @@ func NewAuthMiddleware(ctx context.Context, cfg config.KeycloakConfig, log zerol
 		Strs("valid_issuers", validIssuers).
 		Msg("JWT authentication middleware initialized")
 
-	return &AuthMiddleware{
-		jwks:         jwks,
+	a := &AuthMiddleware{
+		jwksURL:      jwksURL,
+		ready:        make(chan struct{}),
 		cancelFn:     cancelFn,
 		validIssuers: validIssuers,
 		log:          log,
-	}, nil
+	}
+
+	retryInterval := cfg.JWKSRetryInterval
+	if retryInterval <= 0 {
+		retryInterval = 5 * time.Second
+	}
+	go a.loadJWKSUntilReady(jwksCtx, retryInterval)
+
+	select {
+	case <-a.ready:
+	case <-time.After(cfg.JWKSStartupGrace):
+		log.Warn().
+			Str("jwks_url", jwksURL).
+			Dur("retry_interval", retryInterval).
+			Msg("JWKS not available yet, starting anyway; /v1 returns 503 until keys are loaded")
+	case <-ctx.Done():
+		cancelFn()
+		return nil, ctx.Err()
+	}
+
+	return a, nil
+}
+
+// loadJWKSUntilReady retries fetching the JWKS until at least one key is loaded.
+func (a *AuthMiddleware) loadJWKSUntilReady(ctx context.Context, retryInterval time.Duration) {
+	for attempt := 1; ; attempt++ {
+		if a.loadJWKS(ctx) {
+			a.log.Info().Int("attempt", attempt).Msg("JWKS loaded")
+			close(a.ready)
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+// loadJWKS makes one attempt to fetch the JWKS. On success the keyfunc keeps
+// refreshing itself in the background until ctx is cancelled.
+func (a *AuthMiddleware) loadJWKS(ctx context.Context) bool {
+	attemptCtx, cancel := context.WithCancel(ctx)
+	jwks, err := keyfunc.NewDefaultOverrideCtx(attemptCtx, []string{a.jwksURL}, keyfunc.Override{
+		HTTPTimeout: jwksHTTPTimeout,
+		RefreshErrorHandlerFunc: func(u string) func(context.Context, error) {
+			return func(_ context.Context, err error) {
+				a.log.Warn().Err(err).Str("jwks_url", u).Msg("failed to fetch JWKS")
+			}
+		},
+	})
+	if err != nil {
+		cancel()
+		a.log.Error().Err(err).Str("jwks_url", a.jwksURL).Msg("failed to create JWKS client")
+		return false
+	}
+
+	keys, err := jwks.Storage().KeyReadAll(attemptCtx)
+	if err != nil || len(keys) == 0 {
+		cancel()
+		return false
+	}
+
+	a.jwks = jwks
+	a.jwksCancel = cancel
+	return true
+}
+
+// Ready reports whether the JWKS has been loaded and tokens can be validated.
+func (a *AuthMiddleware) Ready() bool {
+	select {
+	case <-a.ready:
+		return true
+	default:
+		return false
+	}
 }
 
 // Close releases resources used by the auth middleware.
 func (a *AuthMiddleware) Close() {
+	if a.Ready() && a.jwksCancel != nil {
+		a.jwksCancel()
+	}
 	if a.cancelFn != nil {
 		a.cancelFn()
 	}
@@ -94,6 +176,12 @@ func (a *AuthMiddleware) Close() {
 // Middleware returns an HTTP middleware that validates JWT tokens.
 func (a *AuthMiddleware) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !a.Ready() {
+			w.Header().Set("Retry-After", "5")
+			http.Error(w, "Service Unavailable: authentication keys not loaded", http.StatusServiceUnavailable)
+			return
+		}
+
 		token, err := a.extractAndValidateToken(r)
 		if err != nil {
 			a.log.Debug().Err(err).Str("path", r.URL.Path).Msg("authentication failed")
@@ -217,3 +305,13 @@ func GetUserFromContext(ctx context.Context) (*UserClaims, bool) {
 	claims, ok := ctx.Value(UserContextKey).(*UserClaims)
 	return claims, ok
 }
+
+// actorFromContext returns the authenticated username for activity logging, or nil when unknown.
+func actorFromContext(ctx context.Context) *string {
+	claims, ok := GetUserFromContext(ctx)
+	if !ok || claims.PreferredUsername == "" {
+		return nil
+	}
+	username := claims.PreferredUsername
+	return &username
+}
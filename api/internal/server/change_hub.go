@@ -0,0 +1,80 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// changeHub tells waiting clients that API data changed. Waiters block on the
+// current channel; notify closes it and installs a fresh one for the next change.
+type changeHub struct {
+	mu      sync.Mutex
+	last    time.Time
+	changed chan struct{}
+}
+
+// notify records a change and wakes every waiter.
+func (h *changeHub) notify() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.last = time.Now().UTC()
+	if h.changed != nil {
+		close(h.changed)
+		h.changed = nil
+	}
+}
+
+// lastChange returns the time of the latest change, zero if none happened yet.
+func (h *changeHub) lastChange() time.Time {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.last
+}
+
+// wait blocks until a change newer than since happens, the timeout elapses or
+// ctx is done. It returns the latest change time and whether it is after since.
+func (h *changeHub) wait(ctx context.Context, since time.Time, timeout time.Duration) (time.Time, bool) {
+	h.mu.Lock()
+	if h.last.After(since) {
+		last := h.last
+		h.mu.Unlock()
+		return last, true
+	}
+	if h.changed == nil {
+		h.changed = make(chan struct{})
+	}
+	changed := h.changed
+	h.mu.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-changed:
+		return h.lastChange(), true
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+	return h.lastChange(), false
+}
+
+// changeNotifyMiddleware signals the change hub after every successful write request.
+func (s *Server) changeNotifyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+		if status := ww.Status(); status >= 200 && status < 400 {
+			s.changes.notify()
+		}
+	})
+}
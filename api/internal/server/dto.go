@@ -19,9 +19,11 @@ func (r *RawJSON) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// GeoPoint is a WGS84 coordinate. When a projected SRID is requested via
+// ?srid=, Longitude carries the X (easting) and Latitude the Y (northing).
 type GeoPoint struct {
-	Latitude  float64 `json:"latitude"`
-	Longitude float64 `json:"longitude"`
+	Latitude  float64 `json:"latitude" validate:"required,latitude"`
+	Longitude float64 `json:"longitude" validate:"required,longitude"`
 }
 
 type EventSummaryResponse struct {
@@ -43,11 +45,14 @@ type EventSummaryResponse struct {
 	StartedAt          *time.Time     `json:"started_at,omitempty"`
 	CompletedAt        *time.Time     `json:"completed_at,omitempty"`
 	AssignedUnits      []UnitResponse `json:"assigned_units,omitempty"`
+	Version            int32          `json:"version,omitempty"`
 }
 
 type EventDetailResponse struct {
 	EventSummaryResponse
 	RecommendedUnitTypes []string              `json:"recommended_unit_types"`
+	AcknowledgedAt       *time.Time            `json:"acknowledged_at,omitempty"`
+	AcknowledgedBy       string                `json:"acknowledged_by,omitempty"`
 	Intervention         *InterventionResponse `json:"intervention,omitempty"`
 	Logs                 []EventLogResponse    `json:"logs,omitempty"`
 }
@@ -77,11 +82,12 @@ type EventLogWithEventResponse struct {
 }
 
 type EventTypeResponse struct {
-	Code                 string   `json:"code"`
-	Name                 string   `json:"name"`
-	Description          string   `json:"description"`
-	DefaultSeverity      int32    `json:"default_severity"`
-	RecommendedUnitTypes []string `json:"recommended_unit_types"`
+	Code                  string           `json:"code"`
+	Name                  string           `json:"name"`
+	Description           string           `json:"description"`
+	DefaultSeverity       int32            `json:"default_severity"`
+	RecommendedUnitTypes  []string         `json:"recommended_unit_types"`
+	RecommendedUnitCounts map[string]int32 `json:"recommended_unit_counts"`
 }
 
 type UnitTypeResponse struct {
@@ -107,6 +113,34 @@ type InterventionResponse struct {
 	Assignments  []AssignmentResponse `json:"assignments,omitempty"`
 }
 
+// InterventionListItemResponse is an intervention with the context of its event
+type InterventionListItemResponse struct {
+	InterventionResponse
+	EventTitle    string  `json:"event_title"`
+	EventTypeCode string  `json:"event_type_code"`
+	EventSeverity int32   `json:"event_severity"`
+	EventAddress  *string `json:"event_address,omitempty"`
+}
+
+// OverlapSide is one intervention of an overlapping pair, with its event
+type OverlapSide struct {
+	InterventionID     string    `json:"intervention_id"`
+	InterventionStatus string    `json:"intervention_status"`
+	EventID            string    `json:"event_id"`
+	EventTitle         string    `json:"event_title"`
+	EventTypeCode      string    `json:"event_type_code"`
+	EventSeverity      int32     `json:"event_severity"`
+	EventReportedAt    time.Time `json:"event_reported_at"`
+}
+
+// InterventionOverlapResponse is a pair of interventions whose events are close in space and time
+type InterventionOverlapResponse struct {
+	First          OverlapSide `json:"first"`
+	Second         OverlapSide `json:"second"`
+	DistanceMeters float64     `json:"distance_meters"`
+	TimeGapSeconds float64     `json:"time_gap_seconds"`
+}
+
 type AssignmentResponse struct {
 	ID             string     `json:"id"`
 	InterventionID string     `json:"intervention_id"`
@@ -120,6 +154,15 @@ type AssignmentResponse struct {
 	ReleasedAt     *time.Time `json:"released_at,omitempty"`
 }
 
+type ActiveAssignmentResponse struct {
+	AssignmentResponse
+	UnitStatus         string `json:"unit_status"`
+	InterventionStatus string `json:"intervention_status"`
+	EventID            string `json:"event_id"`
+	EventTitle         string `json:"event_title"`
+	EventSeverity      int32  `json:"event_severity"`
+}
+
 type UnitResponse struct {
 	ID             string     `json:"id"`
 	CallSign       string     `json:"call_sign"`
@@ -133,6 +176,7 @@ type UnitResponse struct {
 	LastContact    *time.Time `json:"last_contact_at,omitempty"`
 	CreatedAt      time.Time  `json:"created_at"`
 	UpdatedAt      time.Time  `json:"updated_at"`
+	Version        int32      `json:"version,omitempty"`
 }
 
 type TelemetryResponse struct {
@@ -143,6 +187,28 @@ type TelemetryResponse struct {
 	Heading    *int32    `json:"heading,omitempty"`
 	SpeedKMH   *float64  `json:"speed_kmh,omitempty"`
 	Status     RawJSON   `json:"status_snapshot"`
+	// SpeedClamped is set when the reported speed exceeded the configured ceiling and was clamped.
+	SpeedClamped bool `json:"speed_clamped,omitempty"`
+	// SnappedLocation and RouteProgressPercent are set when the position was snapped to the unit's route.
+	SnappedLocation      *GeoPoint `json:"snapped_location,omitempty"`
+	RouteProgressPercent *float64  `json:"route_progress_percent,omitempty"`
+}
+
+type TelemetryGap struct {
+	Start           time.Time `json:"start"`
+	End             time.Time `json:"end"`
+	DurationSeconds float64   `json:"duration_seconds"`
+}
+
+type TelemetryGapsResponse struct {
+	UnitID          string         `json:"unit_id"`
+	From            time.Time      `json:"from"`
+	To              time.Time      `json:"to"`
+	Threshold       string         `json:"threshold"`
+	Gaps            []TelemetryGap `json:"gaps"`
+	TotalGapSeconds float64        `json:"total_gap_seconds"`
+	// Truncated is set when more gaps exist than were returned.
+	Truncated bool `json:"truncated,omitempty"`
 }
 
 type ActivityLogResponse struct {
@@ -170,6 +236,72 @@ type SyncResponse struct {
 	Events     []EventSummaryResponse `json:"events"`
 	Units      []UnitResponse         `json:"units"`
 	RecentLogs []ActivityLogResponse  `json:"recent_logs"`
+	// Partial is true when at least one section failed; failed sections are
+	// returned empty and listed in Errors keyed by section name.
+	Partial bool              `json:"partial"`
+	Errors  map[string]string `json:"errors,omitempty"`
+}
+
+type SyncPollResponse struct {
+	SyncResponse
+	// Cursor is the time of the latest change, to pass as since on the next poll.
+	Cursor time.Time `json:"cursor"`
+	// Changed is false when the wait elapsed without changes; sections are then empty.
+	Changed bool `json:"changed"`
+}
+
+type EventTimelineBucket struct {
+	Start  time.Time        `json:"start"`
+	Count  int64            `json:"count"`
+	ByType map[string]int64 `json:"by_type,omitempty"`
+}
+
+type EventTimelineResponse struct {
+	From     time.Time             `json:"from"`
+	To       time.Time             `json:"to"`
+	Interval string                `json:"interval"`
+	Buckets  []EventTimelineBucket `json:"buckets"`
+}
+
+type EventHeatmapCell struct {
+	LatBucket string `json:"lat_bucket"`
+	LonBucket string `json:"lon_bucket"`
+	Count     int64  `json:"count"`
+}
+
+type EventHeatmapBucket struct {
+	Start time.Time          `json:"start"`
+	Count int64              `json:"count"`
+	Cells []EventHeatmapCell `json:"cells"`
+}
+
+type EventHeatmapTimeseriesResponse struct {
+	From     time.Time            `json:"from"`
+	To       time.Time            `json:"to"`
+	Interval string               `json:"interval"`
+	Buckets  []EventHeatmapBucket `json:"buckets"`
+}
+
+// EventPageResponse is the cursor-paginated response of GET /v1/events.
+// NextCursor is omitted once the last page has been reached.
+type EventPageResponse struct {
+	Events     []EventSummaryResponse `json:"events"`
+	NextCursor *string                `json:"next_cursor,omitempty"`
+}
+
+// EventExtentResponse is a GeoJSON Feature whose geometry is the convex hull of
+// the matching events: a Polygon, or a Point/LineString for one or two distinct
+// locations, and null when no event matches. BBox is [min_lon, min_lat, max_lon, max_lat].
+type EventExtentResponse struct {
+	Type       string                `json:"type"`
+	Geometry   RawJSON               `json:"geometry"`
+	BBox       []float64             `json:"bbox,omitempty"`
+	Properties EventExtentProperties `json:"properties"`
+}
+
+type EventExtentProperties struct {
+	Status     string `json:"status"`
+	EventCount int64  `json:"event_count"`
 }
 
 type LocationResponse struct {
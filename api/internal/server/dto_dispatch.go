@@ -8,12 +8,13 @@ import "time"
 
 // DispatchConfigItem represents a single configuration parameter.
 type DispatchConfigItem struct {
-	Key         string    `json:"key"`
-	Value       float64   `json:"value"`
-	Description string    `json:"description"`
-	MinValue    *float64  `json:"min_value,omitempty"`
-	MaxValue    *float64  `json:"max_value,omitempty"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	Key          string    `json:"key"`
+	Value        float64   `json:"value"`
+	DefaultValue float64   `json:"default_value"`
+	Description  string    `json:"description"`
+	MinValue     *float64  `json:"min_value,omitempty"`
+	MaxValue     *float64  `json:"max_value,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at"`
 }
 
 // DispatchConfigResponse is the response for GET /v1/dispatch/config.
@@ -21,6 +22,24 @@ type DispatchConfigResponse struct {
 	Items []DispatchConfigItem `json:"items"`
 }
 
+// DispatchConfigDrift is a config key whose engine value differs from the DB.
+// A nil value means the key is missing on that side.
+type DispatchConfigDrift struct {
+	Key         string   `json:"key"`
+	DBValue     *float64 `json:"db_value"`
+	EngineValue *float64 `json:"engine_value"`
+}
+
+// EffectiveDispatchConfigResponse is the response for GET /v1/dispatch/config/effective.
+type EffectiveDispatchConfigResponse struct {
+	DB              map[string]float64    `json:"db"`
+	Engine          map[string]float64    `json:"engine,omitempty"`
+	EngineReachable bool                  `json:"engine_reachable"`
+	EngineError     string                `json:"engine_error,omitempty"`
+	InSync          bool                  `json:"in_sync"`
+	Drift           []DispatchConfigDrift `json:"drift"`
+}
+
 // UpdateDispatchConfigRequest is the request for PUT /v1/dispatch/config.
 type UpdateDispatchConfigRequest struct {
 	Key   string  `json:"key" validate:"required"`
@@ -32,6 +51,22 @@ type BatchUpdateDispatchConfigRequest struct {
 	Items []UpdateDispatchConfigRequest `json:"items" validate:"required,min=1,dive"`
 }
 
+// DispatchConfigChange represents a single audited change of a config value.
+type DispatchConfigChange struct {
+	ID        int64     `json:"id"`
+	Key       string    `json:"key"`
+	Actor     string    `json:"actor,omitempty"`
+	OldValue  *float64  `json:"old_value,omitempty"`
+	NewValue  *float64  `json:"new_value,omitempty"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// DispatchConfigHistoryResponse is the response for GET /v1/dispatch/config/{key}/history.
+type DispatchConfigHistoryResponse struct {
+	Key     string                 `json:"key"`
+	Changes []DispatchConfigChange `json:"changes"`
+}
+
 // =============================================================================
 // Static Data DTOs (for engine startup)
 // =============================================================================
@@ -75,10 +110,42 @@ type DispatchCandidate struct {
 
 // DispatchCandidatesResponse is the response for GET /v1/interventions/{id}/candidates.
 type DispatchCandidatesResponse struct {
-	InterventionID       string              `json:"intervention_id"`
-	EventSeverity        int32               `json:"event_severity"`
+	InterventionID        string              `json:"intervention_id"`
+	EventSeverity         int32               `json:"event_severity"`
+	RecommendedUnitTypes  []string            `json:"recommended_unit_types"`
+	RecommendedUnitCounts map[string]int32    `json:"recommended_unit_counts"`
+	Candidates            []DispatchCandidate `json:"candidates"`
+	// SuggestedUnitIDs is the quick-dispatch selection: the closest available
+	// candidates, taking as many of each type as the event type recommends.
+	SuggestedUnitIDs []string `json:"suggested_unit_ids"`
+}
+
+// NearestUnitsResponse is the response for GET /v1/events/{id}/nearest-units.
+type NearestUnitsResponse struct {
+	EventID              string              `json:"event_id"`
 	RecommendedUnitTypes []string            `json:"recommended_unit_types"`
-	Candidates           []DispatchCandidate `json:"candidates"`
+	Units                []DispatchCandidate `json:"units"`
+	// MissingTypes lists recommended types with no available unit.
+	MissingTypes []string `json:"missing_types"`
+}
+
+// StaffingTypeRecommendation is the dispatch plan for one recommended unit type.
+type StaffingTypeRecommendation struct {
+	UnitTypeCode string `json:"unit_type_code"`
+	Recommended  int32  `json:"recommended"`
+	// Units are the closest available units to send, at most Recommended.
+	Units []DispatchCandidate `json:"units"`
+	// Shortfall is how many more units of the type are recommended than are available.
+	Shortfall int32 `json:"shortfall"`
+}
+
+// StaffingRecommendationResponse is the response for GET /v1/events/{id}/staffing-recommendation.
+type StaffingRecommendationResponse struct {
+	EventID       string                       `json:"event_id"`
+	EventTypeCode string                       `json:"event_type_code"`
+	UnitTypes     []StaffingTypeRecommendation `json:"unit_types"`
+	// Complete is false when at least one unit type has a shortfall.
+	Complete bool `json:"complete"`
 }
 
 // =============================================================================
@@ -104,6 +171,29 @@ type PendingInterventionsResponse struct {
 	Interventions []PendingIntervention `json:"interventions"`
 }
 
+// =============================================================================
+// Missing Routes DTOs
+// =============================================================================
+
+// MissingRouteAssignment is an active assignment whose unit has no stored route.
+type MissingRouteAssignment struct {
+	AssignmentID       string    `json:"assignment_id"`
+	InterventionID     string    `json:"intervention_id"`
+	EventID            string    `json:"event_id"`
+	InterventionStatus string    `json:"intervention_status"`
+	UnitID             string    `json:"unit_id"`
+	CallSign           string    `json:"call_sign"`
+	UnitTypeCode       string    `json:"unit_type_code"`
+	UnitStatus         string    `json:"unit_status"`
+	EventSeverity      int32     `json:"event_severity"`
+	DispatchedAt       time.Time `json:"dispatched_at"`
+}
+
+// MissingRoutesResponse is the response for GET /v1/dispatch/missing-routes.
+type MissingRoutesResponse struct {
+	Assignments []MissingRouteAssignment `json:"assignments"`
+}
+
 // =============================================================================
 // Intervention Dispatch Info DTO
 // =============================================================================
@@ -121,3 +211,38 @@ type InterventionDispatchInfo struct {
 	RecommendedUnitTypes []string `json:"recommended_unit_types"`
 	Location             GeoPoint `json:"location"`
 }
+
+// ReadinessUnitType compares recommended and assigned units for one unit type.
+type ReadinessUnitType struct {
+	UnitTypeCode string `json:"unit_type_code"`
+	Recommended  int32  `json:"recommended"`
+	Assigned     int32  `json:"assigned"`
+	Missing      int32  `json:"missing"`
+	Satisfied    bool   `json:"satisfied"`
+}
+
+// InterventionReadiness reports whether the active assignments cover the event type recommendations.
+type InterventionReadiness struct {
+	InterventionID string              `json:"intervention_id"`
+	EventTypeCode  string              `json:"event_type_code"`
+	Ready          bool                `json:"ready"`
+	UnitTypes      []ReadinessUnitType `json:"unit_types"`
+	Satisfied      []string            `json:"satisfied"`
+	Missing        []string            `json:"missing"`
+	// Extra lists assigned unit types that are not recommended for the event type.
+	Extra []string `json:"extra"`
+}
+
+// =============================================================================
+// Dispatch Health DTO
+// =============================================================================
+
+// DispatchHealth is the summary returned by GET /v1/dispatch/health and pushed by its stream.
+type DispatchHealth struct {
+	PendingInterventions    int64      `json:"pending_interventions"`
+	OldestPendingAt         *time.Time `json:"oldest_pending_at,omitempty"`
+	OldestPendingAgeSeconds float64    `json:"oldest_pending_age_seconds"`
+	AvailableUnits          int64      `json:"available_units"`
+	ActiveAssignments       int64      `json:"active_assignments"`
+	ComputedAt              time.Time  `json:"computed_at"`
+}
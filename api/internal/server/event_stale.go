@@ -0,0 +1,148 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	db "fast/pin/internal/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// staleEventBatch bounds the events handled per stale event run.
+const staleEventBatch = 100
+
+// Reasons recorded on stale_event activity logs.
+const (
+	staleEventFlagged    = "flagged stale"
+	staleEventAutoClosed = "auto-closed stale"
+)
+
+// staleEventActor is the actor recorded for changes made by the stale event worker.
+var staleEventActor = "system:stale-events"
+
+// startStaleEventWorker periodically flags, or with EVENT_AUTO_CLOSE_STALE closes, open events
+// that have gone EVENT_STALE_AFTER without an open or recent intervention.
+func (s *Server) startStaleEventWorker(ctx context.Context) {
+	staleAfter := s.cfg.Event.StaleAfter
+	if staleAfter <= 0 {
+		return
+	}
+	interval := s.cfg.Event.StaleCheckInterval
+	if interval <= 0 {
+		interval = 10 * time.Minute
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.handleStaleEvents(ctx, time.Now().Add(-staleAfter))
+			}
+		}
+	}()
+}
+
+// handleStaleEvents flags or closes the events left open without activity since cutoff.
+func (s *Server) handleStaleEvents(ctx context.Context, cutoff time.Time) {
+	events, err := s.queries.ListStaleOpenEvents(ctx, db.ListStaleOpenEventsParams{
+		Cutoff: pgtype.Timestamptz{Time: cutoff, Valid: true},
+		Limit:  staleEventBatch,
+	})
+	if err != nil {
+		s.log.Warn().Err(err).Msg("failed to list stale events")
+		return
+	}
+
+	handled := 0
+	for _, e := range events {
+		reason, err := s.handleStaleEvent(ctx, e, cutoff)
+		if err != nil {
+			s.log.Warn().Err(err).Str("event_id", uuidString(e.ID)).Msg("failed to handle stale event")
+			continue
+		}
+		if reason == "" {
+			continue
+		}
+		handled++
+
+		s.log.Info().
+			Str("event_id", uuidString(e.ID)).
+			Str("reason", reason).
+			Time("updated_at", e.UpdatedAt.Time).
+			Msg("stale event handled")
+	}
+	if handled > 0 {
+		s.changes.notify()
+	}
+}
+
+// handleStaleEvent flags or closes one event in its own transaction. The event is locked and
+// re-checked first, so an intervention created since it was listed keeps it untouched; the
+// returned reason is then empty.
+func (s *Server) handleStaleEvent(ctx context.Context, e db.ListStaleOpenEventsRow, cutoff time.Time) (string, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return "", fmt.Errorf("start transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	qtx := s.queries.WithTx(tx)
+
+	// Intervention creation locks the event too, so the re-check cannot race it
+	if _, err := qtx.LockEvent(ctx, e.ID); err != nil {
+		if isNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("lock event: %w", err)
+	}
+	stale, err := qtx.IsStaleOpenEvent(ctx, db.IsStaleOpenEventParams{
+		EventID: e.ID,
+		Cutoff:  pgtype.Timestamptz{Time: cutoff, Valid: true},
+	})
+	if err != nil {
+		return "", fmt.Errorf("re-check event: %w", err)
+	}
+	if !stale {
+		return "", nil
+	}
+
+	reason := staleEventFlagged
+	if s.cfg.Event.AutoCloseStale {
+		if _, err := qtx.CloseEvent(ctx, e.ID); err != nil {
+			return "", fmt.Errorf("close event: %w", err)
+		}
+		reason = staleEventAutoClosed
+		oldStatus := eventStatus(pgtype.Timestamptz{}, e.AcknowledgedAt)
+		if err := s.logEventStatusChange(ctx, qtx, e.ID, oldStatus, "closed", &staleEventActor); err != nil {
+			return "", fmt.Errorf("log event status change: %w", err)
+		}
+	}
+
+	metadata, _ := json.Marshal(map[string]string{
+		"title":      e.Title,
+		"updated_at": e.UpdatedAt.Time.Format(time.RFC3339),
+	})
+	entityType := "event"
+	if _, err := qtx.CreateActivityLog(ctx, db.CreateActivityLogParams{
+		ActivityType: "stale_event",
+		EntityType:   &entityType,
+		EntityID:     e.ID,
+		Actor:        &staleEventActor,
+		NewValue:     &reason,
+		Metadata:     metadata,
+	}); err != nil {
+		return "", fmt.Errorf("log stale event: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", fmt.Errorf("commit: %w", err)
+	}
+	return reason, nil
+}
@@ -0,0 +1,293 @@
+package server
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"fast/pin/internal/config"
+)
+
+// AdminConfigResponse is the effective non-secret configuration returned by GET /v1/admin/config.
+// Fields are listed explicitly so that new settings are not exposed until they are reviewed.
+type AdminConfigResponse struct {
+	AppName         string                  `json:"app_name"`
+	Env             string                  `json:"env"`
+	LogLevel        string                  `json:"log_level"`
+	LogFile         string                  `json:"log_file"`
+	LogFileRequired bool                    `json:"log_file_required"`
+	EngineURLs      []string                `json:"engine_urls"`
+	HTTP            AdminHTTPConfig         `json:"http"`
+	Database        AdminDatabaseConfig     `json:"database"`
+	Keycloak        AdminKeycloakConfig     `json:"keycloak"`
+	Rebalance       AdminRebalanceConfig    `json:"rebalance"`
+	Routing         AdminRoutingConfig      `json:"routing"`
+	Sync            AdminSyncConfig         `json:"sync"`
+	Intervention    AdminInterventionConfig `json:"intervention"`
+	Telemetry       AdminTelemetryConfig    `json:"telemetry"`
+	Dispatch        AdminDispatchConfig     `json:"dispatch"`
+	Event           AdminEventConfig        `json:"event"`
+	Stream          AdminStreamConfig       `json:"stream"`
+	Unit            AdminUnitConfig         `json:"unit"`
+	SLO             AdminSLOConfig          `json:"slo"`
+}
+
+type AdminHTTPConfig struct {
+	Address      string `json:"address"`
+	ReadTimeout  string `json:"read_timeout"`
+	WriteTimeout string `json:"write_timeout"`
+	IdleTimeout  string `json:"idle_timeout"`
+	// LogRedactParams are query parameters masked in request logs.
+	LogRedactParams []string `json:"log_redact_params"`
+	RequireIfMatch  bool     `json:"require_if_match"`
+}
+
+type AdminDatabaseConfig struct {
+	// URL has its password redacted.
+	URL             string `json:"url"`
+	RunMigrations   bool   `json:"run_migrations"`
+	MigrationsDir   string `json:"migrations_dir"`
+	MaxConns        int32  `json:"max_conns"`
+	MaxConnIdleTime string `json:"max_conn_idle_time"`
+	MaxConnLifetime string `json:"max_conn_lifetime"`
+}
+
+type AdminKeycloakConfig struct {
+	URL               string `json:"url"`
+	PublicURL         string `json:"public_url"`
+	Realm             string `json:"realm"`
+	ClientID          string `json:"client_id"`
+	JWKSRetryInterval string `json:"jwks_retry_interval"`
+	JWKSStartupGrace  string `json:"jwks_startup_grace"`
+}
+
+type AdminRebalanceConfig struct {
+	MinImbalance int `json:"min_imbalance"`
+	MaxMoves     int `json:"max_moves"`
+}
+
+type AdminRoutingConfig struct {
+	NetworkStatsTTL           string  `json:"network_stats_ttl"`
+	MaxRouteAge               string  `json:"max_route_age"`
+	RefreshInterval           string  `json:"refresh_interval"`
+	RefreshConcurrency        int     `json:"refresh_concurrency"`
+	RefreshBatchSize          int32   `json:"refresh_batch_size"`
+	AllowZeroLengthRoutes     bool    `json:"allow_zero_length_routes"`
+	NearestMaxUnits           int32   `json:"nearest_max_units"`
+	NearestConcurrency        int     `json:"nearest_concurrency"`
+	ArchiveRoutes             bool    `json:"archive_routes"`
+	RouteHistoryRetention     string  `json:"route_history_retention"`
+	RouteHistoryPruneInterval string  `json:"route_history_prune_interval"`
+	MaxConcurrentRepairs      int     `json:"max_concurrent_repairs"`
+	AutoRouteOnAssign         bool    `json:"auto_route_on_assign"`
+	AssignRouteConcurrency    int     `json:"assign_route_concurrency"`
+	RouteDeviationMeters      float64 `json:"route_deviation_meters"`
+	RouteCacheTTL             string  `json:"route_cache_ttl"`
+}
+
+type AdminSyncConfig struct {
+	DefaultDenyStatuses  []string `json:"default_deny_statuses"`
+	DefaultLimitLogs     int      `json:"default_limit_logs"`
+	MaxLimitLogs         int      `json:"max_limit_logs"`
+	PollDefaultWait      string   `json:"poll_default_wait"`
+	PollMaxWait          string   `json:"poll_max_wait"`
+	UnitFetchConcurrency int      `json:"unit_fetch_concurrency"`
+}
+
+type AdminInterventionConfig struct {
+	AutoCompleteOnRelease bool     `json:"auto_complete_on_release"`
+	EngineClientIDs       []string `json:"engine_client_ids"`
+	TrustSourceHeader     bool     `json:"trust_source_header"`
+	SingletonRoles        []string `json:"singleton_roles"`
+	LogAssignmentChanges  bool     `json:"log_assignment_changes"`
+	MaxPerEvent           int64    `json:"max_per_event"`
+	// SeverityPriorities maps event severity to the default intervention priority.
+	SeverityPriorities map[string]int32 `json:"severity_priorities"`
+	// StatusTransitions are the allowed "from:to" intervention status changes.
+	StatusTransitions []string `json:"status_transitions"`
+}
+
+type AdminTelemetryConfig struct {
+	MaxSpeedKMH           float64 `json:"max_speed_kmh"`
+	SpeedCeilingMode      string  `json:"speed_ceiling_mode"`
+	SnapToRoute           bool    `json:"snap_to_route"`
+	SnapMaxDistanceMeters float64 `json:"snap_max_distance_meters"`
+}
+
+type AdminDispatchConfig struct {
+	StaticCacheTTL        string `json:"static_cache_ttl"`
+	EngineRefreshDebounce string `json:"engine_refresh_debounce"`
+	HealthInterval        string `json:"health_interval"`
+	EngineRetries         int    `json:"engine_retries"`
+	EngineRetryBackoff    string `json:"engine_retry_backoff"`
+}
+
+type AdminEventConfig struct {
+	ReportedAtMaxSkew string `json:"reported_at_max_skew"`
+	ReportedAtMaxAge  string `json:"reported_at_max_age"`
+	// DisallowedCombinations are "event_type_code:severity" pairs refused by policy.
+	DisallowedCombinations []string `json:"disallowed_combinations"`
+	StaleAfter             string   `json:"stale_after"`
+	StaleCheckInterval     string   `json:"stale_check_interval"`
+	AutoCloseStale         bool     `json:"auto_close_stale"`
+}
+
+type AdminStreamConfig struct {
+	MaxConnectionsPerUser int    `json:"max_connections_per_user"`
+	KeepAlive             string `json:"keep_alive"`
+	SubscriberBuffer      int    `json:"subscriber_buffer"`
+}
+
+type AdminUnitConfig struct {
+	Statuses           []string `json:"statuses"`
+	AwayDistanceMeters float64  `json:"away_distance_meters"`
+}
+
+type AdminSLOConfig struct {
+	// ResponseTargets maps event severity to the target dispatch-to-arrival time.
+	ResponseTargets map[string]string `json:"response_targets"`
+}
+
+// handleGetAdminConfig godoc
+// @Title Get server configuration
+// @Description Returns the effective non-secret configuration the server is running with. Secrets such as the database password are redacted. Requires the it role.
+// @Resource System
+// @Produce json
+// @Success 200 {object} AdminConfigResponse
+// @Failure 403 {object} APIError
+// @Route /v1/admin/config [get]
+func (s *Server) handleGetAdminConfig(w http.ResponseWriter, r *http.Request) {
+	if !s.authMw.RequireOneOfRoles(w, r, RoleIT) {
+		return
+	}
+	s.writeJSON(w, http.StatusOK, adminConfig(s.cfg))
+}
+
+// adminConfig copies the safe fields of cfg into the admin response.
+func adminConfig(cfg config.Config) AdminConfigResponse {
+	targets := make(map[string]string, len(cfg.SLO.ResponseTargets))
+	for severity, target := range cfg.SLO.ResponseTargets {
+		targets[strconv.Itoa(int(severity))] = target.String()
+	}
+
+	priorities := make(map[string]int32, len(cfg.Intervention.SeverityPriorities))
+	for severity, priority := range cfg.Intervention.SeverityPriorities {
+		priorities[strconv.Itoa(int(severity))] = priority
+	}
+
+	return AdminConfigResponse{
+		AppName:         cfg.AppName,
+		Env:             cfg.Env,
+		LogLevel:        cfg.LogLevel,
+		LogFile:         cfg.LogFile,
+		LogFileRequired: cfg.LogFileRequired,
+		EngineURLs:      cfg.EngineURLs,
+		HTTP: AdminHTTPConfig{
+			Address:         cfg.HTTP.Address,
+			ReadTimeout:     cfg.HTTP.ReadTimeout.String(),
+			WriteTimeout:    cfg.HTTP.WriteTimeout.String(),
+			IdleTimeout:     cfg.HTTP.IdleTimeout.String(),
+			LogRedactParams: cfg.HTTP.LogRedactParams,
+			RequireIfMatch:  cfg.HTTP.RequireIfMatch,
+		},
+		Database: AdminDatabaseConfig{
+			URL:             redactURL(cfg.Database.URL),
+			RunMigrations:   cfg.Database.RunMigrations,
+			MigrationsDir:   cfg.Database.MigrationsDir,
+			MaxConns:        cfg.Database.MaxConns,
+			MaxConnIdleTime: cfg.Database.MaxConnIdleTime.String(),
+			MaxConnLifetime: cfg.Database.MaxConnLifetime.String(),
+		},
+		Keycloak: AdminKeycloakConfig{
+			URL:               cfg.Keycloak.URL,
+			PublicURL:         cfg.Keycloak.PublicURL,
+			Realm:             cfg.Keycloak.Realm,
+			ClientID:          cfg.Keycloak.ClientID,
+			JWKSRetryInterval: cfg.Keycloak.JWKSRetryInterval.String(),
+			JWKSStartupGrace:  cfg.Keycloak.JWKSStartupGrace.String(),
+		},
+		Rebalance: AdminRebalanceConfig{
+			MinImbalance: cfg.Rebalance.MinImbalance,
+			MaxMoves:     cfg.Rebalance.MaxMoves,
+		},
+		Routing: AdminRoutingConfig{
+			NetworkStatsTTL:           cfg.Routing.NetworkStatsTTL.String(),
+			MaxRouteAge:               cfg.Routing.MaxRouteAge.String(),
+			RefreshInterval:           cfg.Routing.RefreshInterval.String(),
+			RefreshConcurrency:        cfg.Routing.RefreshConcurrency,
+			RefreshBatchSize:          cfg.Routing.RefreshBatchSize,
+			AllowZeroLengthRoutes:     cfg.Routing.AllowZeroLengthRoutes,
+			NearestMaxUnits:           cfg.Routing.NearestMaxUnits,
+			NearestConcurrency:        cfg.Routing.NearestConcurrency,
+			ArchiveRoutes:             cfg.Routing.ArchiveRoutes,
+			RouteHistoryRetention:     cfg.Routing.RouteHistoryRetention.String(),
+			RouteHistoryPruneInterval: cfg.Routing.RouteHistoryPruneInterval.String(),
+			MaxConcurrentRepairs:      cfg.Routing.MaxConcurrentRepairs,
+			AutoRouteOnAssign:         cfg.Routing.AutoRouteOnAssign,
+			AssignRouteConcurrency:    cfg.Routing.AssignRouteConcurrency,
+			RouteDeviationMeters:      cfg.Routing.RouteDeviationMeters,
+			RouteCacheTTL:             cfg.Routing.RouteCacheTTL.String(),
+		},
+		Sync: AdminSyncConfig{
+			DefaultDenyStatuses:  cfg.Sync.DefaultDenyStatuses,
+			DefaultLimitLogs:     cfg.Sync.DefaultLimitLogs,
+			MaxLimitLogs:         cfg.Sync.MaxLimitLogs,
+			PollDefaultWait:      cfg.Sync.PollDefaultWait.String(),
+			PollMaxWait:          cfg.Sync.PollMaxWait.String(),
+			UnitFetchConcurrency: cfg.Sync.UnitFetchConcurrency,
+		},
+		Intervention: AdminInterventionConfig{
+			AutoCompleteOnRelease: cfg.Intervention.AutoCompleteOnRelease,
+			EngineClientIDs:       cfg.Intervention.EngineClientIDs,
+			TrustSourceHeader:     cfg.Intervention.TrustSourceHeader,
+			SingletonRoles:        cfg.Intervention.SingletonRoles,
+			LogAssignmentChanges:  cfg.Intervention.LogAssignmentChanges,
+			MaxPerEvent:           cfg.Intervention.MaxPerEvent,
+			SeverityPriorities:    priorities,
+			StatusTransitions:     cfg.Intervention.StatusTransitions,
+		},
+		Telemetry: AdminTelemetryConfig{
+			MaxSpeedKMH:           cfg.Telemetry.MaxSpeedKMH,
+			SpeedCeilingMode:      cfg.Telemetry.SpeedCeilingMode,
+			SnapToRoute:           cfg.Telemetry.SnapToRoute,
+			SnapMaxDistanceMeters: cfg.Telemetry.SnapMaxDistanceMeters,
+		},
+		Dispatch: AdminDispatchConfig{
+			StaticCacheTTL:        cfg.Dispatch.StaticCacheTTL.String(),
+			EngineRefreshDebounce: cfg.Dispatch.EngineRefreshDebounce.String(),
+			HealthInterval:        cfg.Dispatch.HealthInterval.String(),
+			EngineRetries:         cfg.Dispatch.EngineRetries,
+			EngineRetryBackoff:    cfg.Dispatch.EngineRetryBackoff.String(),
+		},
+		Event: AdminEventConfig{
+			ReportedAtMaxSkew:      cfg.Event.ReportedAtMaxSkew.String(),
+			ReportedAtMaxAge:       cfg.Event.ReportedAtMaxAge.String(),
+			DisallowedCombinations: cfg.Event.DisallowedCombinations,
+			StaleAfter:             cfg.Event.StaleAfter.String(),
+			StaleCheckInterval:     cfg.Event.StaleCheckInterval.String(),
+			AutoCloseStale:         cfg.Event.AutoCloseStale,
+		},
+		Stream: AdminStreamConfig{
+			MaxConnectionsPerUser: cfg.Stream.MaxConnectionsPerUser,
+			KeepAlive:             cfg.Stream.KeepAlive.String(),
+			SubscriberBuffer:      cfg.Stream.SubscriberBuffer,
+		},
+		Unit: AdminUnitConfig{
+			Statuses:           cfg.Unit.Statuses,
+			AwayDistanceMeters: cfg.Unit.AwayDistanceMeters,
+		},
+		SLO: AdminSLOConfig{
+			ResponseTargets: targets,
+		},
+	}
+}
+
+// redactURL masks the password of a connection URL. Unparseable values are
+// hidden entirely since they may still contain credentials.
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "[redacted]"
+	}
+	return u.Redacted()
+}
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"net/http"
+	"time"
+)
+
+// TokenIntrospectionResponse describes the state of the caller's access token.
+type TokenIntrospectionResponse struct {
+	Username         string     `json:"username"`
+	Email            string     `json:"email,omitempty"`
+	Subject          string     `json:"subject,omitempty"`
+	Roles            []string   `json:"roles"`
+	IssuedAt         *time.Time `json:"issued_at,omitempty"`
+	ExpiresAt        time.Time  `json:"expires_at"`
+	ExpiresInSeconds int64      `json:"expires_in_seconds"`
+}
+
+// handleIntrospectToken godoc
+// @Title Introspect token
+// @Description Returns expiry, roles and username of the validated bearer token so clients can schedule refreshes.
+// @Resource Auth
+// @Produce json
+// @Success 200 {object} TokenIntrospectionResponse
+// @Failure 401 {object} APIError
+// @Route /v1/auth/introspect [get]
+func (s *Server) handleIntrospectToken(w http.ResponseWriter, r *http.Request) {
+	claims, ok := GetUserFromContext(r.Context())
+	if !ok || claims.ExpiresAt == nil {
+		s.writeError(w, http.StatusUnauthorized, "no authenticated token", nil)
+		return
+	}
+
+	roles := claims.RealmAccess.Roles
+	if roles == nil {
+		roles = []string{}
+	}
+
+	expiresAt := claims.ExpiresAt.Time.UTC()
+	expiresIn := int64(time.Until(expiresAt).Seconds())
+	if expiresIn < 0 {
+		expiresIn = 0
+	}
+
+	var issuedAt *time.Time
+	if claims.IssuedAt != nil {
+		t := claims.IssuedAt.Time.UTC()
+		issuedAt = &t
+	}
+
+	s.writeJSON(w, http.StatusOK, TokenIntrospectionResponse{
+		Username:         claims.PreferredUsername,
+		Email:            claims.Email,
+		Subject:          claims.Subject,
+		Roles:            roles,
+		IssuedAt:         issuedAt,
+		ExpiresAt:        expiresAt,
+		ExpiresInSeconds: expiresIn,
+	})
+}
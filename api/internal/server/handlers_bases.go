@@ -0,0 +1,258 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	db "fast/pin/internal/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// BaseCoverage is the current and projected availability of a station.
+type BaseCoverage struct {
+	ID                 string   `json:"id"`
+	Name               string   `json:"name"`
+	Location           GeoPoint `json:"location"`
+	AvailableUnits     int64    `json:"available_units"`
+	TotalUnits         int64    `json:"total_units"`
+	ProjectedAvailable int64    `json:"projected_available_units"`
+}
+
+// BaseRebalanceMove suggests moving one available unit between two stations.
+type BaseRebalanceMove struct {
+	UnitID            string  `json:"unit_id"`
+	CallSign          string  `json:"call_sign"`
+	UnitTypeCode      string  `json:"unit_type_code"`
+	FromBaseID        string  `json:"from_base_id"`
+	FromBaseName      string  `json:"from_base_name"`
+	ToBaseID          string  `json:"to_base_id"`
+	ToBaseName        string  `json:"to_base_name"`
+	TravelTimeSeconds float64 `json:"travel_time_seconds"`
+	DistanceMeters    float64 `json:"distance_meters"`
+}
+
+// BaseRebalanceResponse is the response for GET /v1/bases/rebalance.
+type BaseRebalanceResponse struct {
+	MinImbalance int                 `json:"min_imbalance"`
+	MinReserve   int32               `json:"min_reserve_per_base"`
+	Bases        []BaseCoverage      `json:"bases"`
+	Moves        []BaseRebalanceMove `json:"moves"`
+}
+
+// handleGetBaseRebalance godoc
+// @Title Suggest base rebalancing
+// @Description Computes availability imbalance across stations and suggests unit moves to equalize coverage. Advisory only, nothing is changed.
+// @Resource Bases
+// @Produce json
+// @Success 200 {object} BaseRebalanceResponse
+// @Failure 500 {object} APIError
+// @Route /v1/bases/rebalance [get]
+func (s *Server) handleGetBaseRebalance(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	stations, err := s.queries.ListStationCoverage(ctx)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to list stations", err.Error())
+		return
+	}
+
+	// Without a min_reserve_per_base row every base keeps at least one unit
+	minReserve, err := s.queries.GetBaseMinReserve(ctx)
+	if isNotFound(err) {
+		minReserve = 1
+	} else if err != nil {
+		s.log.Error().Err(err).Msg("failed to fetch base min reserve")
+		s.writeError(w, http.StatusInternalServerError, "failed to fetch base min reserve", err.Error())
+		return
+	}
+	minImbalance := int64(s.cfg.Rebalance.MinImbalance)
+	if minImbalance < 1 {
+		minImbalance = 1
+	}
+
+	available := make([]int64, len(stations))
+	for i, st := range stations {
+		available[i] = st.AvailableUnits
+	}
+
+	// Inter-base routes are computed lazily and cached for the duration of the request.
+	type routeKey struct{ from, to int }
+	routes := make(map[routeKey]*CalculateRouteResponse)
+	routeBetween := func(from, to int) *CalculateRouteResponse {
+		key := routeKey{from, to}
+		if cached, ok := routes[key]; ok {
+			return cached
+		}
+		route, err := s.calculateRoute(ctx, stations[from].Longitude, stations[from].Latitude, stations[to].Longitude, stations[to].Latitude)
+		var result *CalculateRouteResponse
+		if err != nil {
+			s.log.Warn().Err(err).
+				Str("from_base", stations[from].Name).
+				Str("to_base", stations[to].Name).
+				Msg("failed to calculate inter-base route")
+		} else if route.RouteGeoJSON != "" && route.RouteLengthMeters > 0 {
+			result = &route
+		}
+		routes[key] = result
+		return result
+	}
+
+	moved := make(map[string]struct{})
+	exhausted := make(map[int]struct{})
+	moves := make([]BaseRebalanceMove, 0)
+
+	for len(moves) < s.cfg.Rebalance.MaxMoves {
+		// Recipient: the least covered station that still has a potential donor.
+		recipient := -1
+		for i := range stations {
+			if _, skip := exhausted[i]; skip {
+				continue
+			}
+			if recipient == -1 || available[i] < available[recipient] {
+				recipient = i
+			}
+		}
+		if recipient == -1 {
+			break
+		}
+
+		// Donor: the closest station whose surplus exceeds the imbalance threshold
+		// and which keeps its minimum reserve after giving up a unit.
+		donor := -1
+		var donorRoute *CalculateRouteResponse
+		for i := range stations {
+			if i == recipient {
+				continue
+			}
+			if available[i]-available[recipient] < minImbalance || available[i]-1 < int64(minReserve) {
+				continue
+			}
+			route := routeBetween(i, recipient)
+			if route == nil {
+				continue
+			}
+			if donorRoute == nil || route.EstimatedDurationSeconds < donorRoute.EstimatedDurationSeconds {
+				donor = i
+				donorRoute = route
+			}
+		}
+		if donor == -1 {
+			exhausted[recipient] = struct{}{}
+			continue
+		}
+
+		units, err := s.queries.ListUnitsByLocation(ctx, stations[donor].ID)
+		if err != nil {
+			s.writeError(w, http.StatusInternalServerError, "failed to list units at base", err.Error())
+			return
+		}
+		var move *BaseRebalanceMove
+		for _, u := range units {
+			id := uuidString(u.ID)
+			if _, done := moved[id]; done || u.Status != "available" {
+				continue
+			}
+			moved[id] = struct{}{}
+			move = &BaseRebalanceMove{
+				UnitID:            id,
+				CallSign:          u.CallSign,
+				UnitTypeCode:      u.UnitTypeCode,
+				FromBaseID:        uuidString(stations[donor].ID),
+				FromBaseName:      stations[donor].Name,
+				ToBaseID:          uuidString(stations[recipient].ID),
+				ToBaseName:        stations[recipient].Name,
+				TravelTimeSeconds: donorRoute.EstimatedDurationSeconds,
+				DistanceMeters:    donorRoute.RouteLengthMeters,
+			}
+			break
+		}
+		if move == nil {
+			// Counts changed since the coverage query; stop rather than suggest stale moves.
+			break
+		}
+
+		moves = append(moves, *move)
+		available[donor]--
+		available[recipient]++
+	}
+
+	bases := make([]BaseCoverage, 0, len(stations))
+	for i, st := range stations {
+		bases = append(bases, BaseCoverage{
+			ID:                 uuidString(st.ID),
+			Name:               st.Name,
+			Location:           GeoPoint{Latitude: st.Latitude, Longitude: st.Longitude},
+			AvailableUnits:     st.AvailableUnits,
+			TotalUnits:         st.TotalUnits,
+			ProjectedAvailable: available[i],
+		})
+	}
+
+	s.writeJSON(w, http.StatusOK, BaseRebalanceResponse{
+		MinImbalance: int(minImbalance),
+		MinReserve:   minReserve,
+		Bases:        bases,
+		Moves:        moves,
+	})
+}
+
+// BaseResponseTimes is the dispatch-to-arrival travel time of the units homed at a station.
+type BaseResponseTimes struct {
+	ID                 string  `json:"id"`
+	Name               string  `json:"name"`
+	ArrivedAssignments int64   `json:"arrived_assignments"`
+	AvgTravelSeconds   float64 `json:"avg_travel_seconds"`
+	P90TravelSeconds   float64 `json:"p90_travel_seconds"`
+}
+
+// BaseResponseTimesResponse is the response for GET /v1/stats/bases.
+type BaseResponseTimesResponse struct {
+	From  time.Time           `json:"from"`
+	To    time.Time           `json:"to"`
+	Bases []BaseResponseTimes `json:"bases"`
+}
+
+// handleGetBaseResponseTimes godoc
+// @Title Response times per base
+// @Description Returns the average and 90th percentile dispatch-to-arrival time of assignments dispatched between from and to, grouped by the current home station of the unit. Slowest stations come first; stations without arrivals are omitted.
+// @Resource Bases
+// @Produce json
+// @Param from query string false "Start (RFC3339), defaults to 30 days before to"
+// @Param to query string false "End (RFC3339, exclusive), defaults to now"
+// @Success 200 {object} BaseResponseTimesResponse
+// @Failure 400 {object} APIError
+// @Failure 500 {object} APIError
+// @Route /v1/stats/bases [get]
+func (s *Server) handleGetBaseResponseTimes(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := s.parseTimeRange(w, r, "from", "to", 30*24*time.Hour)
+	if !ok {
+		return
+	}
+
+	rows, err := s.queries.ListBaseResponseTimes(r.Context(), db.ListBaseResponseTimesParams{
+		FromTime: pgtype.Timestamptz{Time: from, Valid: true},
+		ToTime:   pgtype.Timestamptz{Time: to, Valid: true},
+	})
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to compute base response times", err.Error())
+		return
+	}
+
+	resp := BaseResponseTimesResponse{
+		From:  from,
+		To:    to,
+		Bases: make([]BaseResponseTimes, 0, len(rows)),
+	}
+	for _, row := range rows {
+		resp.Bases = append(resp.Bases, BaseResponseTimes{
+			ID:                 uuidString(row.ID),
+			Name:               row.Name,
+			ArrivedAssignments: row.ArrivedAssignments,
+			AvgTravelSeconds:   row.AvgTravelSeconds,
+			P90TravelSeconds:   row.P90TravelSeconds,
+		})
+	}
+
+	s.writeJSON(w, http.StatusOK, resp)
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -12,6 +13,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
+
+	db "fast/pin/internal/db/sqlc"
 )
 
 type APIError struct {
@@ -103,6 +106,85 @@ func (s *Server) paginate(r *http.Request, defaultLimit int32) (limit int32, off
 	return
 }
 
+// defaultSRID is WGS84, the coordinate system locations are stored in.
+const defaultSRID int32 = 4326
+
+// parseSRIDParam reads the optional ?srid= query parameter used to reproject
+// output coordinates. It writes a 400 and returns false when the SRID is
+// malformed or unknown to PostGIS.
+func (s *Server) parseSRIDParam(w http.ResponseWriter, r *http.Request) (int32, bool) {
+	raw := r.URL.Query().Get("srid")
+	if raw == "" {
+		return defaultSRID, true
+	}
+	srid, err := parseInt32(raw)
+	if err != nil || srid <= 0 {
+		s.writeError(w, http.StatusBadRequest, "invalid srid", "srid must be a positive integer")
+		return 0, false
+	}
+	if srid == defaultSRID {
+		return srid, true
+	}
+	supported, err := s.queries.IsSupportedSRID(r.Context(), srid)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to validate srid", err.Error())
+		return 0, false
+	}
+	if !supported {
+		s.writeError(w, http.StatusBadRequest, "unsupported srid", raw)
+		return 0, false
+	}
+	return srid, true
+}
+
+// reprojectPoints transforms WGS84 points in place to the target SRID in a
+// single round trip. Projected coordinates are written as Longitude=X and
+// Latitude=Y.
+func (s *Server) reprojectPoints(ctx context.Context, srid int32, points []*GeoPoint) error {
+	if srid == defaultSRID || len(points) == 0 {
+		return nil
+	}
+	lons := make([]float64, len(points))
+	lats := make([]float64, len(points))
+	for i, p := range points {
+		lons[i] = p.Longitude
+		lats[i] = p.Latitude
+	}
+	rows, err := s.queries.TransformPoints(ctx, db.TransformPointsParams{
+		Srid:       srid,
+		Longitudes: lons,
+		Latitudes:  lats,
+	})
+	if err != nil {
+		return err
+	}
+	if len(rows) != len(points) {
+		return errors.New("reprojection returned an unexpected number of points")
+	}
+	for i, row := range rows {
+		points[i].Longitude = row.X
+		points[i].Latitude = row.Y
+	}
+	return nil
+}
+
+// reprojectGeoJSON transforms a WGS84 GeoJSON geometry to the target SRID.
+// An empty geometry is returned unchanged.
+func (s *Server) reprojectGeoJSON(ctx context.Context, srid int32, geojson string) (string, error) {
+	if srid == defaultSRID || geojson == "" {
+		return geojson, nil
+	}
+	row, err := s.queries.TransformGeoJSON(ctx, db.TransformGeoJSONParams{
+		Geojson:  geojson,
+		FromSrid: defaultSRID,
+		ToSrid:   srid,
+	})
+	if err != nil {
+		return "", err
+	}
+	return row.Geojson, nil
+}
+
 func parseInt32(value string) (int32, error) {
 	if strings.TrimSpace(value) == "" {
 		return 0, errors.New("empty value")
@@ -171,6 +253,50 @@ func isNotFound(err error) bool {
 	return errors.Is(err, pgx.ErrNoRows)
 }
 
+// expectedVersion returns the row version a PATCH was based on, taken from the If-Match
+// header or else from the body's version field. A nil version means no check was asked
+// for: If-Match: * asks for an unconditional update explicitly. On a bad or missing (when
+// HTTP_REQUIRE_IF_MATCH is set) precondition the error response is written and ok is false.
+func (s *Server) expectedVersion(w http.ResponseWriter, r *http.Request, bodyVersion *int32) (version *int32, ok bool) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "*" {
+		return bodyVersion, true
+	}
+	if header == "" {
+		if bodyVersion == nil && s.cfg.HTTP.RequireIfMatch {
+			s.writeError(w, http.StatusPreconditionRequired, "If-Match header or version field required", nil)
+			return nil, false
+		}
+		return bodyVersion, true
+	}
+
+	parsed, err := strconv.ParseInt(strings.Trim(strings.TrimPrefix(header, "W/"), `"`), 10, 32)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid If-Match header", err.Error())
+		return nil, false
+	}
+	v := int32(parsed)
+	if bodyVersion != nil && *bodyVersion != v {
+		s.writeError(w, http.StatusBadRequest, "If-Match header and version field disagree", nil)
+		return nil, false
+	}
+	return &v, true
+}
+
+// setVersionETag exposes a row version as a strong ETag so clients can echo it in If-Match.
+func setVersionETag(w http.ResponseWriter, version int32) {
+	w.Header().Set("ETag", `"`+strconv.FormatInt(int64(version), 10)+`"`)
+}
+
+// writeVersionConflict rejects an update based on a stale row version.
+func (s *Server) writeVersionConflict(w http.ResponseWriter, current, expected int32) {
+	setVersionETag(w, current)
+	s.writeError(w, http.StatusConflict, "resource was modified since it was read", map[string]int32{
+		"current_version":  current,
+		"expected_version": expected,
+	})
+}
+
 func isUniqueViolation(err error) bool {
 	if err == nil {
 		return false
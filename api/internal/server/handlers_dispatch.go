@@ -2,13 +2,23 @@ package server
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog"
 
 	db "fast/pin/internal/db/sqlc"
 )
@@ -44,7 +54,7 @@ func (s *Server) handleGetDispatchConfig(w http.ResponseWriter, r *http.Request)
 
 // handleUpdateDispatchConfig updates a single dispatch configuration parameter.
 // @Summary Update dispatch configuration
-// @Description Updates a single weight or threshold value. Triggers engine refresh.
+// @Description Updates a single weight or threshold value. Values outside the min_value/max_value bounds of the key are rejected. Triggers engine refresh.
 // @Tags dispatch
 // @Accept json
 // @Produce json
@@ -70,12 +80,34 @@ func (s *Server) handleUpdateDispatchConfig(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	updated, err := s.queries.UpdateDispatchConfigValue(ctx, db.UpdateDispatchConfigValueParams{
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to start transaction", err.Error())
+		return
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	qtx := s.queries.WithTx(tx)
+
+	// Fetch current value for the audit log
+	current, err := qtx.GetDispatchConfigValue(ctx, req.Key)
+	if err != nil {
+		if isNotFound(err) {
+			s.writeError(w, http.StatusNotFound, "config key not found", req.Key)
+			return
+		}
+		s.writeError(w, http.StatusInternalServerError, "failed to fetch config", err.Error())
+		return
+	}
+	if !s.checkDispatchConfigRange(w, mapDispatchConfigToDTO(current), req.Value) {
+		return
+	}
+
+	updated, err := qtx.UpdateDispatchConfigValue(ctx, db.UpdateDispatchConfigValueParams{
 		Key:   req.Key,
 		Value: numericValue,
 	})
 	if err != nil {
-		if err == pgx.ErrNoRows {
+		if isNotFound(err) {
 			s.writeError(w, http.StatusNotFound, "config key not found", req.Key)
 			return
 		}
@@ -83,12 +115,334 @@ func (s *Server) handleUpdateDispatchConfig(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	oldValue, _ := numericToFloat64(current.Value)
+	newValue, _ := numericToFloat64(updated.Value)
+	if oldValue != newValue {
+		if err := s.logDispatchConfigChange(ctx, qtx, req.Key, current.Value, updated.Value, actorFromContext(ctx)); err != nil {
+			s.writeError(w, http.StatusInternalServerError, "failed to record config history", err.Error())
+			return
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to commit config update", err.Error())
+		return
+	}
+
+	s.invalidateStaticData()
+
 	// Trigger engine refresh asynchronously
-	go s.notifyEngineRefresh(context.Background())
+	s.scheduleEngineRefresh()
 
 	s.writeJSON(w, http.StatusOK, mapDispatchConfigToDTO(updated))
 }
 
+// handleBatchUpdateDispatchConfig updates several dispatch configuration parameters at once.
+// @Summary Batch update dispatch configuration
+// @Description Updates several weights or thresholds in one transaction: either every value is applied or none. Each value must lie within its min/max bounds. Triggers a single engine refresh.
+// @Tags dispatch
+// @Accept json
+// @Produce json
+// @Param body body BatchUpdateDispatchConfigRequest true "Config updates"
+// @Success 200 {object} DispatchConfigResponse
+// @Failure 400 {object} APIError
+// @Failure 500 {object} APIError
+// @Router /v1/dispatch/config/batch [put]
+func (s *Server) handleBatchUpdateDispatchConfig(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req BatchUpdateDispatchConfigRequest
+	if err := s.decodeAndValidate(r, &req); err != nil {
+		s.writeError(w, http.StatusBadRequest, errInvalidPayload, err.Error())
+		return
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to start transaction", err.Error())
+		return
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	qtx := s.queries.WithTx(tx)
+
+	before, err := qtx.ListDispatchConfig(ctx)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to fetch dispatch config", err.Error())
+		return
+	}
+	current := make(map[string]DispatchConfigItem, len(before))
+	previous := make(map[string]pgtype.Numeric, len(before))
+	for _, c := range before {
+		current[c.Key] = mapDispatchConfigToDTO(c)
+		previous[c.Key] = c.Value
+	}
+
+	// Validate the whole batch before touching any row
+	seen := make(map[string]struct{}, len(req.Items))
+	for _, item := range req.Items {
+		if _, dup := seen[item.Key]; dup {
+			s.writeError(w, http.StatusBadRequest, "duplicate config key", item.Key)
+			return
+		}
+		seen[item.Key] = struct{}{}
+
+		existing, ok := current[item.Key]
+		if !ok {
+			s.writeError(w, http.StatusBadRequest, "config key not found", item.Key)
+			return
+		}
+		if !s.checkDispatchConfigRange(w, existing, item.Value) {
+			return
+		}
+	}
+
+	for _, item := range req.Items {
+		numericValue := pgtype.Numeric{}
+		if err := numericValue.Scan(fmt.Sprintf("%f", item.Value)); err != nil {
+			s.writeError(w, http.StatusBadRequest, "invalid numeric value", item.Key)
+			return
+		}
+		if _, err := qtx.UpdateDispatchConfigValue(ctx, db.UpdateDispatchConfigValueParams{
+			Key:   item.Key,
+			Value: numericValue,
+		}); err != nil {
+			s.writeError(w, http.StatusInternalServerError, "failed to update config", err.Error())
+			return
+		}
+	}
+
+	after, err := qtx.ListDispatchConfig(ctx)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to fetch dispatch config", err.Error())
+		return
+	}
+
+	actor := actorFromContext(ctx)
+	items := make([]DispatchConfigItem, 0, len(after))
+	for _, c := range after {
+		item := mapDispatchConfigToDTO(c)
+		items = append(items, item)
+		if old, ok := current[c.Key]; ok && old.Value != item.Value {
+			if err := s.logDispatchConfigChange(ctx, qtx, c.Key, previous[c.Key], c.Value, actor); err != nil {
+				s.writeError(w, http.StatusInternalServerError, "failed to record config history", err.Error())
+				return
+			}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to commit config update", err.Error())
+		return
+	}
+
+	s.invalidateStaticData()
+
+	// Trigger a single engine refresh for the whole batch
+	s.scheduleEngineRefresh()
+
+	s.writeJSON(w, http.StatusOK, DispatchConfigResponse{Items: items})
+}
+
+// handleResetDispatchConfig restores every config key to its seeded default value.
+// @Summary Reset dispatch configuration
+// @Description Sets all weights and thresholds back to their default values in one transaction and triggers a single engine refresh
+// @Tags dispatch
+// @Produce json
+// @Success 200 {object} DispatchConfigResponse
+// @Failure 403 {object} APIError
+// @Failure 500 {object} APIError
+// @Router /v1/dispatch/config/reset [post]
+func (s *Server) handleResetDispatchConfig(w http.ResponseWriter, r *http.Request) {
+	if !s.authMw.RequireOneOfRoles(w, r, RoleSuperieur) {
+		return
+	}
+
+	ctx := r.Context()
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to start transaction", err.Error())
+		return
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	qtx := s.queries.WithTx(tx)
+
+	before, err := qtx.ListDispatchConfig(ctx)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to fetch dispatch config", err.Error())
+		return
+	}
+
+	changed, err := qtx.ResetDispatchConfigToDefaults(ctx)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to reset dispatch config", err.Error())
+		return
+	}
+
+	after, err := qtx.ListDispatchConfig(ctx)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to fetch dispatch config", err.Error())
+		return
+	}
+
+	if changed > 0 {
+		actor := actorFromContext(ctx)
+		for _, c := range before {
+			oldValue, _ := numericToFloat64(c.Value)
+			defaultValue, _ := numericToFloat64(c.DefaultValue)
+			if oldValue == defaultValue {
+				continue
+			}
+			if err := s.logDispatchConfigChange(ctx, qtx, c.Key, c.Value, c.DefaultValue, actor); err != nil {
+				s.writeError(w, http.StatusInternalServerError, "failed to record config history", err.Error())
+				return
+			}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to commit config reset", err.Error())
+		return
+	}
+
+	if changed > 0 {
+		s.invalidateStaticData()
+
+		// Trigger engine refresh asynchronously
+		s.scheduleEngineRefresh()
+	}
+	s.log.Info().Int64("changed", changed).Msg("dispatch config reset to defaults")
+
+	items := make([]DispatchConfigItem, 0, len(after))
+	for _, c := range after {
+		items = append(items, mapDispatchConfigToDTO(c))
+	}
+
+	s.writeJSON(w, http.StatusOK, DispatchConfigResponse{Items: items})
+}
+
+// handleGetDispatchConfigHistory returns the change history of a config parameter.
+// @Summary Get dispatch configuration history
+// @Description Returns who changed a weight or threshold, when, and from/to which value
+// @Tags dispatch
+// @Produce json
+// @Param key path string true "Config key"
+// @Param limit query int false "Maximum results" default(50)
+// @Param offset query int false "Results offset" default(0)
+// @Success 200 {object} DispatchConfigHistoryResponse
+// @Failure 404 {object} APIError
+// @Failure 500 {object} APIError
+// @Router /v1/dispatch/config/{key}/history [get]
+func (s *Server) handleGetDispatchConfigHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	key := chi.URLParam(r, "key")
+
+	if _, err := s.queries.GetDispatchConfigValue(ctx, key); err != nil {
+		if isNotFound(err) {
+			s.writeError(w, http.StatusNotFound, "config key not found", key)
+			return
+		}
+		s.writeError(w, http.StatusInternalServerError, "failed to fetch config", err.Error())
+		return
+	}
+
+	limit, offset := s.paginate(r, 50)
+	rows, err := s.queries.ListDispatchConfigHistory(ctx, db.ListDispatchConfigHistoryParams{
+		ConfigKey: key,
+		Limit:     limit,
+		Offset:    offset,
+	})
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to fetch config history", err.Error())
+		return
+	}
+
+	changes := make([]DispatchConfigChange, 0, len(rows))
+	for _, row := range rows {
+		changes = append(changes, DispatchConfigChange{
+			ID:        row.ID,
+			Key:       key,
+			Actor:     optionalString(row.Actor),
+			OldValue:  numericPtr(row.OldValue),
+			NewValue:  numericPtr(row.NewValue),
+			ChangedAt: row.ChangedAt.Time,
+		})
+	}
+
+	s.writeJSON(w, http.StatusOK, DispatchConfigHistoryResponse{Key: key, Changes: changes})
+}
+
+// handleGetEffectiveDispatchConfig compares the stored config with the engine's loaded values.
+// @Summary Get effective dispatch configuration
+// @Description Returns the DB config, the values the engine is actually using, and the keys that drifted. An unreachable engine is reported, not treated as an error.
+// @Tags dispatch
+// @Produce json
+// @Success 200 {object} EffectiveDispatchConfigResponse
+// @Failure 500 {object} APIError
+// @Router /v1/dispatch/config/effective [get]
+func (s *Server) handleGetEffectiveDispatchConfig(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	configs, err := s.queries.ListDispatchConfig(ctx)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to fetch dispatch config", err.Error())
+		return
+	}
+
+	resp := EffectiveDispatchConfigResponse{
+		DB:    make(map[string]float64, len(configs)),
+		Drift: make([]DispatchConfigDrift, 0),
+	}
+	for _, c := range configs {
+		value, _ := numericToFloat64(c.Value)
+		resp.DB[c.Key] = value
+	}
+
+	engine, err := s.fetchEngineConfig(ctx)
+	if err != nil {
+		s.log.Warn().Err(err).Msg("failed to fetch engine config")
+		resp.EngineError = err.Error()
+		s.writeJSON(w, http.StatusOK, resp)
+		return
+	}
+	resp.Engine = engine
+	resp.EngineReachable = true
+
+	keys := make([]string, 0, len(resp.DB)+len(engine))
+	for k := range resp.DB {
+		keys = append(keys, k)
+	}
+	for k := range engine {
+		if _, ok := resp.DB[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	// Values go through NUMERIC and JSON on the way to the engine, allow for rounding
+	const epsilon = 1e-9
+	for _, k := range keys {
+		dbValue, inDB := resp.DB[k]
+		engineValue, inEngine := engine[k]
+		if inDB && inEngine && math.Abs(dbValue-engineValue) <= epsilon {
+			continue
+		}
+		drift := DispatchConfigDrift{Key: k}
+		if inDB {
+			v := dbValue
+			drift.DBValue = &v
+		}
+		if inEngine {
+			v := engineValue
+			drift.EngineValue = &v
+		}
+		resp.Drift = append(resp.Drift, drift)
+	}
+	resp.InSync = len(resp.Drift) == 0
+
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
 // =============================================================================
 // Static Data Handler (Engine Startup)
 // =============================================================================
@@ -99,11 +453,66 @@ func (s *Server) handleUpdateDispatchConfig(w http.ResponseWriter, r *http.Reque
 // @Tags dispatch
 // @Produce json
 // @Success 200 {object} StaticDataResponse
+// @Success 304 "Not modified (If-None-Match matches the ETag)"
 // @Failure 500 {object} APIError
 // @Router /v1/dispatch/static [get]
 func (s *Server) handleGetDispatchStatic(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+	gen := s.staticDataGen.Load()
+	snapshot := s.staticData.Load()
+	if snapshot == nil || snapshot.gen != gen || time.Now().After(snapshot.expiresAt) {
+		data, err := s.buildDispatchStatic(r.Context())
+		if err != nil {
+			s.writeError(w, http.StatusInternalServerError, "failed to fetch static data", err.Error())
+			return
+		}
+		body, err := json.Marshal(data)
+		if err != nil {
+			s.writeError(w, http.StatusInternalServerError, "failed to encode static data", err.Error())
+			return
+		}
+		sum := sha256.Sum256(body)
+		snapshot = &staticDataSnapshot{
+			body:      body,
+			etag:      `"` + hex.EncodeToString(sum[:16]) + `"`,
+			expiresAt: time.Now().Add(s.cfg.Dispatch.StaticCacheTTL),
+			gen:       gen,
+		}
+		// Data read before a concurrent invalidation must not be cached
+		if s.cfg.Dispatch.StaticCacheTTL > 0 && s.staticDataGen.Load() == gen {
+			s.staticData.Store(snapshot)
+		}
+	}
+
+	w.Header().Set("ETag", snapshot.etag)
+	if r.Header.Get("If-None-Match") == snapshot.etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(snapshot.body)
+}
 
+// staticDataSnapshot is an encoded /v1/dispatch/static response shared between requests.
+type staticDataSnapshot struct {
+	body      []byte
+	etag      string
+	expiresAt time.Time
+	// gen is the staticDataGen the snapshot was built under
+	gen uint64
+}
+
+// invalidateStaticData drops the cached static data so the next request reloads it.
+// Bumping the generation also discards snapshots still being built from older data.
+// Besides config changes it is called after unit status, station, creation and deletion
+// changes are committed, since each base carries its live available and total unit counts.
+func (s *Server) invalidateStaticData() {
+	s.staticDataGen.Add(1)
+	s.staticData.Store(nil)
+}
+
+// buildDispatchStatic loads configuration, unit types, event types and bases.
+func (s *Server) buildDispatchStatic(ctx context.Context) (StaticDataResponse, error) {
 	// Fetch all static data in parallel
 	type result struct {
 		configs    []db.DispatchConfig
@@ -137,8 +546,7 @@ func (s *Server) handleGetDispatchStatic(w http.ResponseWriter, r *http.Request)
 
 	res := <-ch
 	if res.err != nil {
-		s.writeError(w, http.StatusInternalServerError, "failed to fetch static data", res.err.Error())
-		return
+		return StaticDataResponse{}, res.err
 	}
 
 	// Map to DTOs
@@ -162,11 +570,12 @@ func (s *Server) handleGetDispatchStatic(w http.ResponseWriter, r *http.Request)
 	eventTypes := make([]EventTypeResponse, 0, len(res.eventTypes))
 	for _, et := range res.eventTypes {
 		eventTypes = append(eventTypes, EventTypeResponse{
-			Code:                 et.Code,
-			Name:                 et.Name,
-			Description:          et.Description,
-			DefaultSeverity:      et.DefaultSeverity,
-			RecommendedUnitTypes: et.RecommendedUnitTypes,
+			Code:                  et.Code,
+			Name:                  et.Name,
+			Description:           et.Description,
+			DefaultSeverity:       et.DefaultSeverity,
+			RecommendedUnitTypes:  et.RecommendedUnitTypes,
+			RecommendedUnitCounts: parseUnitCounts(et.RecommendedUnitCounts, et.RecommendedUnitTypes),
 		})
 	}
 
@@ -180,12 +589,12 @@ func (s *Server) handleGetDispatchStatic(w http.ResponseWriter, r *http.Request)
 		})
 	}
 
-	s.writeJSON(w, http.StatusOK, StaticDataResponse{
+	return StaticDataResponse{
 		Config:     configItems,
 		UnitTypes:  unitTypes,
 		EventTypes: eventTypes,
 		Bases:      bases,
-	})
+	}, nil
 }
 
 // =============================================================================
@@ -215,7 +624,7 @@ func (s *Server) handleGetDispatchCandidates(w http.ResponseWriter, r *http.Requ
 	// Get intervention details
 	intervention, err := s.queries.GetInterventionForDispatch(ctx, interventionID)
 	if err != nil {
-		if err == pgx.ErrNoRows {
+		if isNotFound(err) {
 			s.writeError(w, http.StatusNotFound, "intervention not found", nil)
 			return
 		}
@@ -223,6 +632,37 @@ func (s *Server) handleGetDispatchCandidates(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	candidates, unitCounts, err := s.fetchDispatchCandidates(ctx, intervention)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to fetch candidates", err.Error())
+		return
+	}
+
+	// Map to DTOs
+	candidateDTOs := make([]DispatchCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		candidateDTOs = append(candidateDTOs, mapCandidateToDTO(c))
+	}
+
+	s.writeJSON(w, http.StatusOK, DispatchCandidatesResponse{
+		InterventionID:        uuidToString(intervention.InterventionID),
+		EventSeverity:         intervention.EventSeverity,
+		RecommendedUnitTypes:  intervention.RecommendedUnitTypes,
+		RecommendedUnitCounts: unitCounts,
+		Candidates:            candidateDTOs,
+		SuggestedUnitIDs:      suggestUnitsByCount(candidates, unitCounts),
+	})
+}
+
+// fetchDispatchCandidates ranks candidate units for an intervention, returning them along
+// with the recommended unit counts per type.
+func (s *Server) fetchDispatchCandidates(ctx context.Context, intervention db.GetInterventionForDispatchRow) ([]db.ListDispatchCandidatesRow, map[string]int32, error) {
+	return s.fetchEventCandidates(ctx, intervention.EventID, intervention.RecommendedUnitTypes, intervention.RecommendedUnitCounts)
+}
+
+// fetchEventCandidates ranks candidate units around an event for the given recommended
+// unit types and raw per-type counts.
+func (s *Server) fetchEventCandidates(ctx context.Context, eventID pgtype.UUID, unitTypes []string, rawCounts []byte) ([]db.ListDispatchCandidatesRow, map[string]int32, error) {
 	// Get max candidates from config (default 10)
 	maxCandidates := int32(10)
 	if cfg, err := s.queries.GetDispatchConfigValue(ctx, "max_candidates_per_dispatch"); err == nil {
@@ -231,47 +671,405 @@ func (s *Server) handleGetDispatchCandidates(w http.ResponseWriter, r *http.Requ
 		}
 	}
 
-	// Fetch candidates
+	// Make sure the candidate list is long enough to fill the recommended counts
+	unitCounts := parseUnitCounts(rawCounts, unitTypes)
+	var requiredUnits int32
+	for _, n := range unitCounts {
+		requiredUnits += n
+	}
+	if requiredUnits > maxCandidates {
+		maxCandidates = requiredUnits
+	}
+
 	candidates, err := s.queries.ListDispatchCandidates(ctx, db.ListDispatchCandidatesParams{
-		InterventionID: interventionID,
-		UnitTypes:      intervention.RecommendedUnitTypes,
-		MaxCandidates:  maxCandidates,
+		EventID:       eventID,
+		UnitTypes:     unitTypes,
+		MaxCandidates: maxCandidates,
 	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return candidates, unitCounts, nil
+}
+
+// handleAssignCandidate assigns one of the current candidates to an intervention.
+// @Summary Assign a dispatch candidate
+// @Description Checks the unit is among the current candidates, then creates the assignment and sets the unit under_way in one transaction. A unit already active on another assignment is refused unless force=true. Route calculation starts afterwards.
+// @Tags dispatch
+// @Produce json
+// @Param interventionID path string true "Intervention ID"
+// @Param unitID path string true "Unit ID"
+// @Param force query bool false "Assign even if the unit is active on another assignment"
+// @Success 201 {object} AssignmentResponse
+// @Failure 400 {object} APIError
+// @Failure 404 {object} APIError
+// @Failure 409 {object} APIError
+// @Failure 500 {object} APIError
+// @Router /v1/interventions/{interventionID}/candidates/{unitID}/assign [post]
+func (s *Server) handleAssignCandidate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	interventionID, err := s.parseUUIDParam(r, "interventionID")
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, errInvalidInterventionID, err.Error())
+		return
+	}
+	unitID, err := s.parseUUIDParam(r, "unitID")
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, errInvalidUnitID, err.Error())
+		return
+	}
+	force, ok := s.parseForceParam(w, r)
+	if !ok {
+		return
+	}
+
+	intervention, err := s.queries.GetInterventionForDispatch(ctx, interventionID)
+	if err != nil {
+		if isNotFound(err) {
+			s.writeError(w, http.StatusNotFound, "intervention not found", nil)
+			return
+		}
+		s.writeError(w, http.StatusInternalServerError, "failed to fetch intervention", err.Error())
+		return
+	}
+
+	candidates, _, err := s.fetchDispatchCandidates(ctx, intervention)
 	if err != nil {
 		s.writeError(w, http.StatusInternalServerError, "failed to fetch candidates", err.Error())
 		return
 	}
+	isCandidate := false
+	for _, c := range candidates {
+		if c.ID == unitID {
+			isCandidate = true
+			break
+		}
+	}
+	if !isCandidate {
+		s.writeError(w, http.StatusConflict, "unit is not a current candidate", uuidToString(unitID))
+		return
+	}
 
-	// Map to DTOs
-	candidateDTOs := make([]DispatchCandidate, 0, len(candidates))
+	s.dispatchCandidate(w, r, interventionID, unitID, force)
+}
+
+// handleAutoAssign assigns the top-ranked available candidate to an intervention.
+// @Summary Auto-assign the closest available unit
+// @Description Picks the closest immediately available candidate of a recommended unit type (within max_candidates_per_dispatch), then assigns it like the candidate assign endpoint. A candidate taken by a concurrent request is skipped for the next one.
+// @Tags dispatch
+// @Produce json
+// @Param interventionID path string true "Intervention ID"
+// @Success 201 {object} AssignmentResponse
+// @Failure 400 {object} APIError
+// @Failure 404 {object} APIError
+// @Failure 409 {object} APIError
+// @Failure 500 {object} APIError
+// @Router /v1/interventions/{interventionID}/auto-assign [post]
+func (s *Server) handleAutoAssign(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	interventionID, err := s.parseUUIDParam(r, "interventionID")
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, errInvalidInterventionID, err.Error())
+		return
+	}
+
+	intervention, err := s.queries.GetInterventionForDispatch(ctx, interventionID)
+	if err != nil {
+		if isNotFound(err) {
+			s.writeError(w, http.StatusNotFound, "intervention not found", nil)
+			return
+		}
+		s.writeError(w, http.StatusInternalServerError, "failed to fetch intervention", err.Error())
+		return
+	}
+
+	candidates, _, err := s.fetchDispatchCandidates(ctx, intervention)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to fetch candidates", err.Error())
+		return
+	}
+	// Candidates are sorted by estimated travel time; under_way units are only preemption options
 	for _, c := range candidates {
-		candidateDTOs = append(candidateDTOs, mapCandidateToDTO(c))
+		if !isImmediatelyAvailable(c.Status) {
+			continue
+		}
+		dispatched, err := s.createCandidateAssignment(ctx, interventionID, c.ID, false, true)
+		if errors.Is(err, errUnitAlreadyAssigned) || errors.Is(err, errUnitNotAvailable) || isNotFound(err) {
+			// Taken or changed since the candidates were ranked
+			continue
+		}
+		if err != nil {
+			s.writeAssignUnitError(w, err, c.ID)
+			return
+		}
+		s.finishCandidateDispatch(w, r, dispatched)
+		return
 	}
+	s.writeError(w, http.StatusConflict, "no available units", map[string]interface{}{
+		"recommended_unit_types": intervention.RecommendedUnitTypes,
+	})
+}
 
-	s.writeJSON(w, http.StatusOK, DispatchCandidatesResponse{
-		InterventionID:       uuidToString(intervention.InterventionID),
-		EventSeverity:        intervention.EventSeverity,
-		RecommendedUnitTypes: intervention.RecommendedUnitTypes,
-		Candidates:           candidateDTOs,
+// errUnitNotAvailable is returned by createCandidateAssignment when the locked unit is no longer available.
+var errUnitNotAvailable = errors.New("unit is no longer available")
+
+// isImmediatelyAvailable reports whether a unit in status can be dispatched without preempting it.
+func isImmediatelyAvailable(status db.UnitStatus) bool {
+	return status == db.UnitStatusAvailable || status == db.UnitStatusAvailableHidden
+}
+
+// candidateDispatch is a committed candidate assignment along with the unit status it replaced.
+type candidateDispatch struct {
+	assignment db.InterventionAssignment
+	unit       db.GetUnitRow
+}
+
+// dispatchCandidate creates the assignment and sets the unit under_way in one transaction,
+// then starts the route calculation and writes the created assignment. The unit is locked and,
+// unless force is set, refused when it is already active on another assignment.
+func (s *Server) dispatchCandidate(w http.ResponseWriter, r *http.Request, interventionID, unitID pgtype.UUID, force bool) {
+	dispatched, err := s.createCandidateAssignment(r.Context(), interventionID, unitID, force, false)
+	if err != nil {
+		s.writeAssignUnitError(w, err, unitID)
+		return
+	}
+	s.finishCandidateDispatch(w, r, dispatched)
+}
+
+// createCandidateAssignment locks the unit, sets it under_way through changeUnitStatus and
+// creates the assignment in one transaction. With requireAvailable the locked unit must still be immediately available,
+// otherwise errUnitNotAvailable is returned and nothing is written.
+func (s *Server) createCandidateAssignment(ctx context.Context, interventionID, unitID pgtype.UUID, force, requireAvailable bool) (candidateDispatch, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return candidateDispatch{}, fmt.Errorf("start transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	qtx := s.queries.WithTx(tx)
+
+	status, err := lockUnitForAssignment(ctx, qtx, unitID, force)
+	if err != nil {
+		return candidateDispatch{}, err
+	}
+	if requireAvailable && !isImmediatelyAvailable(status) {
+		return candidateDispatch{}, errUnitNotAvailable
+	}
+
+	unit, _, err := changeUnitStatus(ctx, qtx, unitID, db.UnitStatusUnderWay, nil)
+	if err != nil {
+		return candidateDispatch{}, err
+	}
+
+	assignment, err := qtx.CreateAssignment(ctx, db.CreateAssignmentParams{
+		InterventionID: interventionID,
+		UnitID:         unitID,
+		Status:         db.AssignmentStatusDispatched,
+	})
+	if err != nil {
+		return candidateDispatch{}, fmt.Errorf("create assignment: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return candidateDispatch{}, fmt.Errorf("commit assignment: %w", err)
+	}
+	return candidateDispatch{assignment: assignment, unit: unit}, nil
+}
+
+// finishCandidateDispatch logs a committed candidate assignment, starts its route calculation
+// and writes the created assignment.
+func (s *Server) finishCandidateDispatch(w http.ResponseWriter, r *http.Request, dispatched candidateDispatch) {
+	ctx := r.Context()
+	unit, assignment := dispatched.unit, dispatched.assignment
+	s.invalidateStaticData()
+
+	s.logUnitStatusChange(ctx, unit.ID, unit.CallSign, string(unit.Status), string(db.UnitStatusUnderWay), actorFromContext(ctx))
+	s.logAssignmentChange(ctx, eventLogUnitDispatched, assignment.ID, actorFromContext(ctx))
+
+	s.routeNewAssignment(assignment.InterventionID, assignment.UnitID)
+
+	s.writeJSON(w, http.StatusCreated, mapAssignment(assignment))
+}
+
+// handleGetNearestUnits returns the nearest available unit of each recommended type for an event.
+// @Summary Get nearest units per type
+// @Description Returns the closest available unit for each recommended unit type of the event, with estimated travel time. With per_type=false only the overall closest unit is returned.
+// @Tags dispatch
+// @Produce json
+// @Param eventID path string true "Event ID"
+// @Param per_type query bool false "One unit per recommended type" default(true)
+// @Success 200 {object} NearestUnitsResponse
+// @Failure 400 {object} APIError
+// @Failure 404 {object} APIError
+// @Failure 500 {object} APIError
+// @Router /v1/events/{eventID}/nearest-units [get]
+func (s *Server) handleGetNearestUnits(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	eventID, err := s.parseUUIDParam(r, "eventID")
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, errInvalidEventID, err.Error())
+		return
+	}
+
+	perType := true
+	if raw := r.URL.Query().Get("per_type"); raw != "" {
+		perType, err = strconv.ParseBool(raw)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, "invalid per_type", err.Error())
+			return
+		}
+	}
+
+	event, err := s.queries.GetEvent(ctx, eventID)
+	if err != nil {
+		if isNotFound(err) {
+			s.writeError(w, http.StatusNotFound, "event not found", nil)
+			return
+		}
+		s.writeError(w, http.StatusInternalServerError, "failed to fetch event", err.Error())
+		return
+	}
+
+	rows, err := s.queries.ListNearestAvailableUnitsByType(ctx, db.ListNearestAvailableUnitsByTypeParams{
+		EventID:   eventID,
+		UnitTypes: event.RecommendedUnitTypes,
+	})
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to fetch nearest units", err.Error())
+		return
+	}
+
+	found := make(map[string]struct{}, len(rows))
+	units := make([]DispatchCandidate, 0, len(rows))
+	for _, row := range rows {
+		found[row.UnitTypeCode] = struct{}{}
+		units = append(units, DispatchCandidate{
+			ID:                uuidToString(row.ID),
+			CallSign:          row.CallSign,
+			UnitTypeCode:      row.UnitTypeCode,
+			HomeBase:          optionalString(row.HomeBaseName),
+			Status:            string(row.Status),
+			Location:          GeoPoint{Latitude: row.Latitude, Longitude: row.Longitude},
+			TravelTimeSeconds: row.TravelTimeSeconds,
+			DistanceMeters:    row.DistanceMeters,
+			OtherUnitsAtBase:  int(row.OtherUnitsAtBase),
+		})
+	}
+
+	sort.Slice(units, func(i, j int) bool {
+		return units[i].TravelTimeSeconds < units[j].TravelTimeSeconds
+	})
+	if !perType && len(units) > 1 {
+		units = units[:1]
+	}
+
+	missing := make([]string, 0)
+	for _, t := range event.RecommendedUnitTypes {
+		if _, ok := found[t]; !ok {
+			missing = append(missing, t)
+		}
+	}
+
+	s.writeJSON(w, http.StatusOK, NearestUnitsResponse{
+		EventID:              uuidToString(eventID),
+		RecommendedUnitTypes: event.RecommendedUnitTypes,
+		Units:                units,
+		MissingTypes:         missing,
 	})
 }
 
+// handleGetStaffingRecommendation turns the event type recommendations into a dispatch plan.
+// @Summary Get staffing recommendation
+// @Description Returns, for each recommended unit type of the event, the recommended count and the closest available units to send, flagging shortfalls when not enough units are available
+// @Tags dispatch
+// @Produce json
+// @Param eventID path string true "Event ID"
+// @Success 200 {object} StaffingRecommendationResponse
+// @Failure 400 {object} APIError
+// @Failure 404 {object} APIError
+// @Failure 500 {object} APIError
+// @Router /v1/events/{eventID}/staffing-recommendation [get]
+func (s *Server) handleGetStaffingRecommendation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	eventID, err := s.parseUUIDParam(r, "eventID")
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, errInvalidEventID, err.Error())
+		return
+	}
+
+	needs, err := s.queries.GetEventStaffingNeeds(ctx, eventID)
+	if err != nil {
+		if isNotFound(err) {
+			s.writeError(w, http.StatusNotFound, "event not found", nil)
+			return
+		}
+		s.writeError(w, http.StatusInternalServerError, "failed to fetch event", err.Error())
+		return
+	}
+
+	candidates, counts, err := s.fetchEventCandidates(ctx, needs.EventID, needs.RecommendedUnitTypes, needs.RecommendedUnitCounts)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to fetch candidates", err.Error())
+		return
+	}
+
+	byID := make(map[string]db.ListDispatchCandidatesRow, len(candidates))
+	for _, c := range candidates {
+		byID[uuidToString(c.ID)] = c
+	}
+	unitsByType := make(map[string][]DispatchCandidate, len(needs.RecommendedUnitTypes))
+	for _, id := range suggestUnitsByCount(candidates, counts) {
+		c := byID[id]
+		unitsByType[c.UnitTypeCode] = append(unitsByType[c.UnitTypeCode], mapCandidateToDTO(c))
+	}
+
+	resp := StaffingRecommendationResponse{
+		EventID:       uuidToString(needs.EventID),
+		EventTypeCode: needs.EventTypeCode,
+		UnitTypes:     make([]StaffingTypeRecommendation, 0, len(needs.RecommendedUnitTypes)),
+		Complete:      true,
+	}
+	for _, unitType := range needs.RecommendedUnitTypes {
+		units := unitsByType[unitType]
+		if units == nil {
+			units = make([]DispatchCandidate, 0)
+		}
+		item := StaffingTypeRecommendation{
+			UnitTypeCode: unitType,
+			Recommended:  counts[unitType],
+			Units:        units,
+		}
+		if missing := item.Recommended - int32(len(units)); missing > 0 {
+			item.Shortfall = missing
+			resp.Complete = false
+		}
+		resp.UnitTypes = append(resp.UnitTypes, item)
+	}
+
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
 // =============================================================================
 // Pending Interventions Handler
 // =============================================================================
 
 // handleListPendingInterventions returns interventions awaiting dispatch.
 // @Summary List pending interventions
-// @Description Returns interventions in planned/created/en_route status for periodic dispatch
+// @Description Returns interventions in planned/created/en_route status for periodic dispatch. Interventions without assigned units come first, then by event severity, priority and age.
 // @Tags dispatch
 // @Produce json
+// @Param only_uncovered query bool false "Only interventions with no assigned unit"
 // @Success 200 {object} PendingInterventionsResponse
 // @Failure 500 {object} APIError
 // @Router /v1/dispatch/pending [get]
 func (s *Server) handleListPendingInterventions(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	rows, err := s.queries.ListPendingInterventions(ctx)
+	onlyUncovered := r.URL.Query().Get("only_uncovered") == "true"
+	rows, err := s.queries.ListPendingInterventions(ctx, onlyUncovered)
 	if err != nil {
 		s.writeError(w, http.StatusInternalServerError, "failed to fetch pending interventions", err.Error())
 		return
@@ -296,6 +1094,40 @@ func (s *Server) handleListPendingInterventions(w http.ResponseWriter, r *http.R
 	s.writeJSON(w, http.StatusOK, PendingInterventionsResponse{Interventions: interventions})
 }
 
+// handleListMissingRoutes returns active assignments whose unit has no route.
+// @Summary List assignments missing a route
+// @Description Returns dispatched assignments whose unit has no stored route, surfacing silent route calculation failures
+// @Tags dispatch
+// @Produce json
+// @Success 200 {object} MissingRoutesResponse
+// @Failure 500 {object} APIError
+// @Router /v1/dispatch/missing-routes [get]
+func (s *Server) handleListMissingRoutes(w http.ResponseWriter, r *http.Request) {
+	rows, err := s.queries.ListAssignmentsMissingRoute(r.Context())
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to list assignments missing a route", err.Error())
+		return
+	}
+
+	assignments := make([]MissingRouteAssignment, 0, len(rows))
+	for _, row := range rows {
+		assignments = append(assignments, MissingRouteAssignment{
+			AssignmentID:       uuidToString(row.AssignmentID),
+			InterventionID:     uuidToString(row.InterventionID),
+			EventID:            uuidToString(row.EventID),
+			InterventionStatus: string(row.InterventionStatus),
+			UnitID:             uuidToString(row.UnitID),
+			CallSign:           row.CallSign,
+			UnitTypeCode:       row.UnitTypeCode,
+			UnitStatus:         string(row.UnitStatus),
+			EventSeverity:      row.EventSeverity,
+			DispatchedAt:       row.DispatchedAt.Time,
+		})
+	}
+
+	s.writeJSON(w, http.StatusOK, MissingRoutesResponse{Assignments: assignments})
+}
+
 // =============================================================================
 // Intervention Dispatch Info Handler
 // =============================================================================
@@ -322,7 +1154,7 @@ func (s *Server) handleGetInterventionDispatchInfo(w http.ResponseWriter, r *htt
 
 	row, err := s.queries.GetInterventionForDispatch(ctx, interventionID)
 	if err != nil {
-		if err == pgx.ErrNoRows {
+		if isNotFound(err) {
 			s.writeError(w, http.StatusNotFound, "intervention not found", nil)
 			return
 		}
@@ -344,17 +1176,119 @@ func (s *Server) handleGetInterventionDispatchInfo(w http.ResponseWriter, r *htt
 	})
 }
 
+// handleGetInterventionReadiness checks the active assignments against the event type recommendations.
+// @Summary Get intervention readiness
+// @Description Compares assigned unit types with the recommended unit types and counts of the event type
+// @Tags dispatch
+// @Produce json
+// @Param interventionID path string true "Intervention ID"
+// @Success 200 {object} InterventionReadiness
+// @Failure 400 {object} APIError
+// @Failure 404 {object} APIError
+// @Failure 500 {object} APIError
+// @Router /v1/interventions/{interventionID}/readiness [get]
+func (s *Server) handleGetInterventionReadiness(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	interventionID, err := s.parseUUIDParam(r, "interventionID")
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, errInvalidInterventionID, err.Error())
+		return
+	}
+
+	intervention, err := s.queries.GetInterventionForDispatch(ctx, interventionID)
+	if err != nil {
+		if isNotFound(err) {
+			s.writeError(w, http.StatusNotFound, "intervention not found", nil)
+			return
+		}
+		s.writeError(w, http.StatusInternalServerError, "failed to fetch intervention", err.Error())
+		return
+	}
+
+	assignments, err := s.queries.ListAssignmentsByIntervention(ctx, interventionID)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to fetch assignments", err.Error())
+		return
+	}
+
+	assigned := make(map[string]int32)
+	for _, a := range assignments {
+		if a.ReleasedAt.Valid || a.Status == db.AssignmentStatusReleased || a.Status == db.AssignmentStatusCancelled {
+			continue
+		}
+		assigned[a.UnitTypeCode]++
+	}
+
+	recommended := parseUnitCounts(intervention.RecommendedUnitCounts, intervention.RecommendedUnitTypes)
+	resp := InterventionReadiness{
+		InterventionID: uuidToString(intervention.InterventionID),
+		EventTypeCode:  intervention.EventTypeCode,
+		Ready:          true,
+		UnitTypes:      make([]ReadinessUnitType, 0, len(recommended)),
+		Satisfied:      make([]string, 0),
+		Missing:        make([]string, 0),
+		Extra:          make([]string, 0),
+	}
+	for unitType, want := range recommended {
+		item := ReadinessUnitType{
+			UnitTypeCode: unitType,
+			Recommended:  want,
+			Assigned:     assigned[unitType],
+		}
+		if item.Assigned < want {
+			item.Missing = want - item.Assigned
+			resp.Missing = append(resp.Missing, unitType)
+			resp.Ready = false
+		} else {
+			item.Satisfied = true
+			resp.Satisfied = append(resp.Satisfied, unitType)
+		}
+		resp.UnitTypes = append(resp.UnitTypes, item)
+	}
+	for unitType := range assigned {
+		if _, ok := recommended[unitType]; !ok {
+			resp.Extra = append(resp.Extra, unitType)
+		}
+	}
+
+	sort.Slice(resp.UnitTypes, func(i, j int) bool { return resp.UnitTypes[i].UnitTypeCode < resp.UnitTypes[j].UnitTypeCode })
+	sort.Strings(resp.Satisfied)
+	sort.Strings(resp.Missing)
+	sort.Strings(resp.Extra)
+
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
 // =============================================================================
 // Helper Functions
 // =============================================================================
 
+// checkDispatchConfigRange rejects values outside the min_value/max_value bounds stored
+// with the key. It writes the error response and returns false when the value is refused.
+func (s *Server) checkDispatchConfigRange(w http.ResponseWriter, item DispatchConfigItem, value float64) bool {
+	if (item.MinValue != nil && value < *item.MinValue) ||
+		(item.MaxValue != nil && value > *item.MaxValue) {
+		s.writeError(w, http.StatusBadRequest, "config value out of range", map[string]interface{}{
+			"key":       item.Key,
+			"value":     value,
+			"min_value": item.MinValue,
+			"max_value": item.MaxValue,
+		})
+		return false
+	}
+	return true
+}
+
 func mapDispatchConfigToDTO(c db.DispatchConfig) DispatchConfigItem {
 	value, _ := numericToFloat64(c.Value)
+	defaultValue, _ := numericToFloat64(c.DefaultValue)
 	item := DispatchConfigItem{
-		Key:         c.Key,
-		Value:       value,
-		Description: c.Description,
-		UpdatedAt:   c.UpdatedAt.Time,
+		Key:          c.Key,
+		Value:        value,
+		DefaultValue: defaultValue,
+		Description:  c.Description,
+		UpdatedAt:    c.UpdatedAt.Time,
 	}
 	if c.MinValue.Valid {
 		if v, err := numericToFloat64(c.MinValue); err == nil {
@@ -400,6 +1334,40 @@ func mapCandidateToDTO(c db.ListDispatchCandidatesRow) DispatchCandidate {
 	return dto
 }
 
+// parseUnitCounts decodes the recommended_unit_counts JSONB of an event type.
+// Recommended types missing from the map default to one unit.
+func parseUnitCounts(raw []byte, recommendedTypes []string) map[string]int32 {
+	counts := make(map[string]int32, len(recommendedTypes))
+	if len(raw) > 0 {
+		_ = json.Unmarshal(raw, &counts)
+	}
+	for _, t := range recommendedTypes {
+		if _, ok := counts[t]; !ok {
+			counts[t] = 1
+		}
+	}
+	return counts
+}
+
+// suggestUnitsByCount picks the closest immediately available candidates,
+// up to the recommended count for each unit type. Candidates are expected
+// to be sorted by distance.
+func suggestUnitsByCount(candidates []db.ListDispatchCandidatesRow, counts map[string]int32) []string {
+	picked := make(map[string]int32, len(counts))
+	suggested := make([]string, 0)
+	for _, c := range candidates {
+		if c.Status != db.UnitStatusAvailable && c.Status != db.UnitStatusAvailableHidden {
+			continue
+		}
+		if picked[c.UnitTypeCode] >= counts[c.UnitTypeCode] {
+			continue
+		}
+		picked[c.UnitTypeCode]++
+		suggested = append(suggested, uuidToString(c.ID))
+	}
+	return suggested
+}
+
 func uuidToString(u pgtype.UUID) string {
 	if !u.Valid {
 		return ""
@@ -407,6 +1375,15 @@ func uuidToString(u pgtype.UUID) string {
 	return uuid.UUID(u.Bytes).String()
 }
 
+// numericPtr converts an optional numeric, returning nil when it is null or not a number.
+func numericPtr(n pgtype.Numeric) *float64 {
+	f, err := numericToFloat64(n)
+	if err != nil {
+		return nil
+	}
+	return &f
+}
+
 func numericToFloat64(n pgtype.Numeric) (float64, error) {
 	if !n.Valid {
 		return 0, fmt.Errorf("numeric is null")
@@ -422,63 +1399,148 @@ func numericToFloat64(n pgtype.Numeric) (float64, error) {
 // Engine Client
 // =============================================================================
 
-// notifyEngineRefresh sends a refresh signal to the decision engine.
-func (s *Server) notifyEngineRefresh(ctx context.Context) {
-	engineURL := s.cfg.EngineURL
-	if engineURL == "" {
-		s.log.Debug().Msg("Engine URL not set, skipping engine refresh notification")
+// scheduleEngineRefresh notifies the engine once DISPATCH_ENGINE_REFRESH_DEBOUNCE has
+// elapsed without another call, so a burst of config edits triggers a single refresh.
+func (s *Server) scheduleEngineRefresh() {
+	window := s.cfg.Dispatch.EngineRefreshDebounce
+	if window <= 0 {
+		go s.notifyEngineRefresh(context.Background())
 		return
 	}
 
+	s.engineRefreshMu.Lock()
+	defer s.engineRefreshMu.Unlock()
+	// A timer that already fired has sent (or is sending) its refresh; this edit needs a new one
+	if s.engineRefreshTimer != nil {
+		s.engineRefreshTimer.Stop()
+	}
+	s.engineRefreshTimer = time.AfterFunc(window, func() {
+		s.notifyEngineRefresh(context.Background())
+	})
+}
+
+// flushEngineRefresh sends a still pending debounced refresh immediately.
+func (s *Server) flushEngineRefresh() {
+	s.engineRefreshMu.Lock()
+	pending := s.engineRefreshTimer != nil && s.engineRefreshTimer.Stop()
+	s.engineRefreshTimer = nil
+	s.engineRefreshMu.Unlock()
+
+	if pending {
+		s.notifyEngineRefresh(context.Background())
+	}
+}
+
+// notifyEngineRefresh sends a refresh signal to every decision engine.
+func (s *Server) notifyEngineRefresh(ctx context.Context) {
+	s.notifyEngines(ctx, "refresh", "/refresh", 5*time.Second, s.log)
+}
+
+// fetchEngineConfig retrieves the config values currently loaded by the decision engine.
+// With several engines configured, the first one is queried.
+func (s *Server) fetchEngineConfig(ctx context.Context) (map[string]float64, error) {
+	if len(s.cfg.EngineURLs) == 0 || strings.TrimSpace(s.cfg.EngineURLs[0]) == "" {
+		return nil, errors.New("engine URL not set")
+	}
+	engineURL := strings.TrimRight(strings.TrimSpace(s.cfg.EngineURLs[0]), "/")
+
 	client := &http.Client{Timeout: 5 * time.Second}
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, engineURL+"/refresh", nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, engineURL+"/config", nil)
 	if err != nil {
-		s.log.Warn().Err(err).Msg("failed to create engine refresh request")
-		return
+		return nil, err
 	}
 
 	resp, err := client.Do(req)
 	if err != nil {
-		s.log.Warn().Err(err).Msg("failed to notify engine of config refresh")
-		return
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		s.log.Warn().Int("status", resp.StatusCode).Msg("engine refresh returned non-OK status")
-		return
+		return nil, fmt.Errorf("engine returned status %d", resp.StatusCode)
 	}
 
-	s.log.Info().Msg("engine config refresh triggered successfully")
+	var payload struct {
+		Values map[string]float64 `json:"values"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("decode engine config: %w", err)
+	}
+	if payload.Values == nil {
+		payload.Values = map[string]float64{}
+	}
+	return payload.Values, nil
 }
 
-// notifyEngineDispatch sends a dispatch trigger to the decision engine.
+// notifyEngineDispatch sends a dispatch trigger to every decision engine.
 func (s *Server) notifyEngineDispatch(ctx context.Context, interventionID string) {
-	engineURL := s.cfg.EngineURL
-	if engineURL == "" {
-		s.log.Debug().Msg("Engine URL not set, skipping engine dispatch notification")
-		return
-	}
+	log := s.log.With().Str("intervention_id", interventionID).Logger()
+	s.notifyEngines(ctx, "dispatch", "/dispatch/"+interventionID, 10*time.Second, log)
+}
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	url := fmt.Sprintf("%s/dispatch/%s", engineURL, interventionID)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
-	if err != nil {
-		s.log.Warn().Err(err).Msg("failed to create engine dispatch request")
+// notifyEngines POSTs path to all ENGINE_CALLBACK_URL engines concurrently and waits for them.
+// Each engine is retried on its own, so one engine being down does not hold back the others.
+func (s *Server) notifyEngines(ctx context.Context, kind, path string, timeout time.Duration, log zerolog.Logger) {
+	if len(s.cfg.EngineURLs) == 0 {
+		log.Debug().Str("kind", kind).Msg("Engine URL not set, skipping engine notification")
 		return
 	}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		s.log.Warn().Err(err).Str("intervention_id", interventionID).Msg("failed to notify engine for dispatch")
-		return
+	client := &http.Client{Timeout: timeout}
+	var wg sync.WaitGroup
+	for _, engineURL := range s.cfg.EngineURLs {
+		engineURL = strings.TrimRight(strings.TrimSpace(engineURL), "/")
+		if engineURL == "" {
+			continue
+		}
+		wg.Add(1)
+		go func(engineURL string) {
+			defer wg.Done()
+			engineLog := log.With().Str("engine", engineURL).Str("kind", kind).Logger()
+			if err := s.postToEngine(ctx, client, engineURL+path); err != nil {
+				engineNotificationsTotal.WithLabelValues(engineURL, kind, "failure").Inc()
+				engineLog.Warn().Err(err).Msg("engine notification failed")
+				return
+			}
+			engineNotificationsTotal.WithLabelValues(engineURL, kind, "success").Inc()
+			engineLog.Info().Msg("engine notification sent successfully")
+		}(engineURL)
 	}
-	defer resp.Body.Close()
+	wg.Wait()
+}
 
-	if resp.StatusCode != http.StatusOK {
-		s.log.Warn().Int("status", resp.StatusCode).Str("intervention_id", interventionID).Msg("engine dispatch returned non-OK status")
-		return
-	}
+// postToEngine POSTs to url, retrying transport errors and 5xx responses up to
+// DISPATCH_ENGINE_RETRIES times with a doubling backoff.
+func (s *Server) postToEngine(ctx context.Context, client *http.Client, url string) error {
+	backoff := s.cfg.Dispatch.EngineRetryBackoff
+	var lastErr error
+	for attempt := 0; attempt <= s.cfg.Dispatch.EngineRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
 
-	s.log.Info().Str("intervention_id", interventionID).Msg("engine dispatch triggered successfully")
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusOK {
+			return nil
+		}
+		lastErr = fmt.Errorf("engine returned status %d", resp.StatusCode)
+		if resp.StatusCode < http.StatusInternalServerError {
+			return lastErr
+		}
+	}
+	return lastErr
 }
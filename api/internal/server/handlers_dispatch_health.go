@@ -0,0 +1,129 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// handleGetDispatchHealth returns the aggregated dispatch health summary.
+// @Summary Get dispatch health
+// @Description Returns pending intervention count, age of the oldest pending intervention, available units and active assignments. Cached for DISPATCH_HEALTH_INTERVAL.
+// @Tags dispatch
+// @Produce json
+// @Success 200 {object} DispatchHealth
+// @Failure 500 {object} APIError
+// @Router /v1/dispatch/health [get]
+func (s *Server) handleGetDispatchHealth(w http.ResponseWriter, r *http.Request) {
+	health, err := s.dispatchHealth(r.Context())
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to compute dispatch health", err.Error())
+		return
+	}
+	s.writeJSON(w, http.StatusOK, health)
+}
+
+// handleDispatchHealthStream pushes the dispatch health summary as Server-Sent Events.
+// @Summary Stream dispatch health
+// @Description Server-Sent Events stream emitting the /v1/dispatch/health payload every DISPATCH_HEALTH_INTERVAL, starting immediately.
+// @Tags dispatch
+// @Produce text/event-stream
+// @Success 200 {string} string "event stream"
+// @Failure 429 {object} APIError
+// @Router /v1/dispatch/health/stream [get]
+func (s *Server) handleDispatchHealthStream(w http.ResponseWriter, r *http.Request) {
+	release, ok := s.acquireStreamSlot(w, r)
+	if !ok {
+		return
+	}
+	defer release()
+
+	rc, ok := s.startEventStream(w)
+	if !ok {
+		return
+	}
+
+	ticker := time.NewTicker(s.dispatchHealthInterval())
+	defer ticker.Stop()
+
+	ctx := r.Context()
+	for {
+		if err := s.writeDispatchHealthEvent(ctx, w); err != nil {
+			if ctx.Err() == nil {
+				s.log.Debug().Err(err).Msg("dispatch health stream stopped")
+			}
+			return
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// writeDispatchHealthEvent writes one SSE frame. A failed computation is sent as
+// an error event so the display can flag stale data without dropping the stream.
+func (s *Server) writeDispatchHealthEvent(ctx context.Context, w http.ResponseWriter) error {
+	health, err := s.dispatchHealth(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		s.log.Warn().Err(err).Msg("failed to compute dispatch health for stream")
+		payload, _ := json.Marshal(APIError{Error: "failed to compute dispatch health"})
+		_, werr := fmt.Fprintf(w, "event: error\ndata: %s\n\n", payload)
+		return werr
+	}
+
+	payload, err := json.Marshal(health)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "data: %s\n\n", payload)
+	return err
+}
+
+// dispatchHealth returns the cached summary, recomputing it once it is older than
+// DISPATCH_HEALTH_INTERVAL. The lock is held while querying so concurrent streams
+// and requests share a single computation.
+func (s *Server) dispatchHealth(ctx context.Context) (DispatchHealth, error) {
+	s.dispatchHealthMu.Lock()
+	defer s.dispatchHealthMu.Unlock()
+
+	if cached := s.dispatchHealthCache; cached != nil && time.Since(cached.ComputedAt) < s.dispatchHealthInterval() {
+		return *cached, nil
+	}
+
+	row, err := s.queries.GetDispatchHealth(ctx)
+	if err != nil {
+		return DispatchHealth{}, err
+	}
+
+	now := time.Now().UTC()
+	health := DispatchHealth{
+		PendingInterventions: row.PendingInterventions,
+		OldestPendingAt:      timestamptzPtr(row.OldestPendingAt),
+		AvailableUnits:       row.AvailableUnits,
+		ActiveAssignments:    row.ActiveAssignments,
+		ComputedAt:           now,
+	}
+	if row.OldestPendingAt.Valid {
+		health.OldestPendingAgeSeconds = now.Sub(row.OldestPendingAt.Time).Seconds()
+	}
+	s.dispatchHealthCache = &health
+	return health, nil
+}
+
+func (s *Server) dispatchHealthInterval() time.Duration {
+	if s.cfg.Dispatch.HealthInterval <= 0 {
+		return 5 * time.Second
+	}
+	return s.cfg.Dispatch.HealthInterval
+}
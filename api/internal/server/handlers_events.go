@@ -2,23 +2,44 @@ package server
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgtype"
+
 	db "fast/pin/internal/db/sqlc"
 )
 
 type CreateEventRequest struct {
-	Title         string  `json:"title" validate:"required,min=3,max=140"`
-	Description   *string `json:"description"`
-	ReportSource  *string `json:"report_source"`
-	Address       *string `json:"address"`
-	Latitude      float64 `json:"latitude" validate:"required,latitude"`
-	Longitude     float64 `json:"longitude" validate:"required,longitude"`
-	Severity      int32   `json:"severity" validate:"required,min=1,max=5"`
-	EventTypeCode string  `json:"event_type_code" validate:"required"`
+	Title        string  `json:"title" validate:"required,min=3,max=140"`
+	Description  *string `json:"description"`
+	ReportSource *string `json:"report_source"`
+	Address      *string `json:"address"`
+	Latitude     float64 `json:"latitude" validate:"required,latitude"`
+	Longitude    float64 `json:"longitude" validate:"required,longitude"`
+	// Severity defaults to the event type's default_severity when omitted.
+	Severity      *int32 `json:"severity" validate:"omitempty,min=1,max=5"`
+	EventTypeCode string `json:"event_type_code" validate:"required"`
+	// ReportedAt back-dates imported events; past values require the it role.
+	ReportedAt *time.Time `json:"reported_at"`
+}
+
+// UpdateEventRequest corrects an event after creation; only the fields present are applied.
+type UpdateEventRequest struct {
+	Severity *int32 `json:"severity" validate:"omitempty,min=1,max=5"`
+	// Latitude and Longitude must be given together.
+	Latitude    *float64 `json:"latitude" validate:"omitempty,latitude"`
+	Longitude   *float64 `json:"longitude" validate:"omitempty,longitude"`
+	Address     *string  `json:"address"`
+	Description *string  `json:"description"`
+	// Version is the event version the change is based on; an If-Match header takes its place.
+	Version *int32 `json:"version"`
 }
 
 type CreateEventLogRequest struct {
@@ -63,6 +84,387 @@ func (s *Server) handleListRecentEventLogs(w http.ResponseWriter, r *http.Reques
 	s.writeJSON(w, http.StatusOK, resp)
 }
 
+// checkReportedAt validates a client-supplied reported_at against the configured skew and
+// age limits. Back-dating beyond the skew is reserved to the it role. It writes the error
+// response and returns false when the timestamp is refused.
+func (s *Server) checkReportedAt(w http.ResponseWriter, r *http.Request, reportedAt *time.Time) bool {
+	if reportedAt == nil {
+		return true
+	}
+	now := time.Now()
+	skew := s.cfg.Event.ReportedAtMaxSkew
+
+	if reportedAt.After(now.Add(skew)) {
+		s.writeError(w, http.StatusBadRequest, "invalid reported_at", "reported_at is in the future")
+		return false
+	}
+	if reportedAt.Before(now.Add(-s.cfg.Event.ReportedAtMaxAge)) {
+		s.writeError(w, http.StatusBadRequest, "invalid reported_at", "reported_at is older than "+s.cfg.Event.ReportedAtMaxAge.String())
+		return false
+	}
+	if reportedAt.Before(now.Add(-skew)) {
+		claims, ok := GetUserFromContext(r.Context())
+		if !ok || !s.authMw.hasRole(claims, RoleIT) {
+			s.writeError(w, http.StatusForbidden, "back-dating events requires the it role", nil)
+			return false
+		}
+		s.log.Info().
+			Str("user", claims.PreferredUsername).
+			Time("reported_at", *reportedAt).
+			Msg("creating back-dated event")
+	}
+	return true
+}
+
+// eventCombination is an event type and severity pair, see EVENT_DISALLOWED_COMBINATIONS.
+type eventCombination struct {
+	EventTypeCode string
+	Severity      int32
+}
+
+func (c eventCombination) String() string {
+	return fmt.Sprintf("%s:%d", c.EventTypeCode, c.Severity)
+}
+
+// parseEventCombinations parses "event_type_code:severity" entries into a lookup set.
+func parseEventCombinations(entries []string) (map[eventCombination]struct{}, error) {
+	combos := make(map[eventCombination]struct{}, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		code, rawSeverity, found := strings.Cut(entry, ":")
+		code = strings.TrimSpace(code)
+		if !found || code == "" {
+			return nil, fmt.Errorf("entry %q must be event_type_code:severity", entry)
+		}
+		severity, err := parseInt32(strings.TrimSpace(rawSeverity))
+		if err != nil || severity < 1 || severity > 5 {
+			return nil, fmt.Errorf("entry %q: severity must be between 1 and 5", entry)
+		}
+		combos[eventCombination{EventTypeCode: code, Severity: severity}] = struct{}{}
+	}
+	return combos, nil
+}
+
+// checkEventCombination refuses event type/severity pairs disallowed by policy.
+// It writes the error response and returns false when the pair is refused.
+func (s *Server) checkEventCombination(w http.ResponseWriter, eventTypeCode string, severity int32) bool {
+	combo := eventCombination{EventTypeCode: eventTypeCode, Severity: severity}
+	if _, disallowed := s.disallowedEventCombos[combo]; disallowed {
+		s.writeError(w, http.StatusBadRequest, "event type and severity combination not allowed", map[string]interface{}{
+			"rule":            combo.String(),
+			"event_type_code": eventTypeCode,
+			"severity":        severity,
+		})
+		return false
+	}
+	return true
+}
+
+// maxTimelineBuckets bounds the number of buckets a timeline request may produce.
+const maxTimelineBuckets = 2000
+
+// parseTimeRange reads an RFC3339 time range from the fromParam and toParam query
+// parameters. to defaults to now and from to defaultSpan before to; with a zero
+// defaultSpan an absent bound is left as the zero time instead. It writes a 400 and
+// returns false on invalid input.
+func (s *Server) parseTimeRange(w http.ResponseWriter, r *http.Request, fromParam, toParam string, defaultSpan time.Duration) (from, to time.Time, ok bool) {
+	query := r.URL.Query()
+
+	if defaultSpan > 0 {
+		to = time.Now().UTC()
+	}
+	if raw := query.Get(toParam); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, "invalid "+toParam, err.Error())
+			return from, to, false
+		}
+		to = parsed.UTC()
+	}
+	if defaultSpan > 0 {
+		from = to.Add(-defaultSpan)
+	}
+	if raw := query.Get(fromParam); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, "invalid "+fromParam, err.Error())
+			return from, to, false
+		}
+		from = parsed.UTC()
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		s.writeError(w, http.StatusBadRequest, "invalid range", fromParam+" must be before "+toParam)
+		return from, to, false
+	}
+	return from, to, true
+}
+
+// parseTimelineRange reads the from, to and interval query parameters shared by the
+// time-bucketed stats endpoints. It writes a 400 and returns false on invalid input.
+func (s *Server) parseTimelineRange(w http.ResponseWriter, r *http.Request) (from, to time.Time, interval time.Duration, ok bool) {
+	query := r.URL.Query()
+
+	from, to, ok = s.parseTimeRange(w, r, "from", "to", 24*time.Hour)
+	if !ok {
+		return from, to, interval, false
+	}
+
+	interval = time.Hour
+	if raw := query.Get("interval"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil || parsed < time.Minute {
+			s.writeError(w, http.StatusBadRequest, "invalid interval", "interval must be a duration of at least 1m")
+			return from, to, interval, false
+		}
+		interval = parsed
+	}
+	if to.Sub(from)/interval > maxTimelineBuckets {
+		s.writeError(w, http.StatusBadRequest, "too many buckets", map[string]int{"max_buckets": maxTimelineBuckets})
+		return from, to, interval, false
+	}
+	return from, to, interval, true
+}
+
+// handleGetEventTimeline godoc
+// @Title Event timeline
+// @Description Returns event counts per time bucket between from and to, with empty buckets as zero. Buckets are aligned to the interval.
+// @Resource Events
+// @Produce json
+// @Param from query string false "Start (RFC3339), defaults to 24h before to"
+// @Param to query string false "End (RFC3339, exclusive), defaults to now"
+// @Param interval query string false "Bucket size as a Go duration" default(1h)
+// @Param by_type query bool false "Split counts by event type"
+// @Param include_deleted query bool false "Also count soft-deleted events" default(false)
+// @Success 200 {object} EventTimelineResponse
+// @Failure 400 {object} APIError
+// @Failure 500 {object} APIError
+// @Route /v1/stats/events/timeline [get]
+func (s *Server) handleGetEventTimeline(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	from, to, interval, ok := s.parseTimelineRange(w, r)
+	if !ok {
+		return
+	}
+
+	params := db.CountEventsByTimeBucketParams{
+		Bucket:         pgtype.Interval{Microseconds: interval.Microseconds(), Valid: true},
+		FromTime:       pgtype.Timestamptz{Time: from, Valid: true},
+		ToTime:         pgtype.Timestamptz{Time: to, Valid: true},
+		IncludeDeleted: query.Get("include_deleted") == "true",
+	}
+
+	buckets := make([]EventTimelineBucket, 0)
+	if query.Get("by_type") == "true" {
+		rows, err := s.queries.CountEventsByTimeBucketAndType(r.Context(), db.CountEventsByTimeBucketAndTypeParams(params))
+		if err != nil {
+			s.writeError(w, http.StatusInternalServerError, "failed to compute event timeline", err.Error())
+			return
+		}
+		// Rows are ordered by bucket then type
+		for _, row := range rows {
+			start := row.BucketStart.Time.UTC()
+			if len(buckets) == 0 || !buckets[len(buckets)-1].Start.Equal(start) {
+				buckets = append(buckets, EventTimelineBucket{Start: start, ByType: make(map[string]int64)})
+			}
+			bucket := &buckets[len(buckets)-1]
+			bucket.Count += row.EventCount
+			bucket.ByType[row.EventTypeCode] = row.EventCount
+		}
+	} else {
+		rows, err := s.queries.CountEventsByTimeBucket(r.Context(), params)
+		if err != nil {
+			s.writeError(w, http.StatusInternalServerError, "failed to compute event timeline", err.Error())
+			return
+		}
+		for _, row := range rows {
+			buckets = append(buckets, EventTimelineBucket{Start: row.BucketStart.Time.UTC(), Count: row.EventCount})
+		}
+	}
+
+	s.writeJSON(w, http.StatusOK, EventTimelineResponse{
+		From:     from,
+		To:       to,
+		Interval: interval.String(),
+		Buckets:  buckets,
+	})
+}
+
+// handleGetEventsExtent godoc
+// @Title Events extent
+// @Description Returns the geographic extent of events as a GeoJSON Feature: the convex hull of their locations plus a bounding box. Events are open until closed (closed_at set).
+// @Resource Events
+// @Produce json
+// @Param status query string false "open, closed or all" default(open)
+// @Param include_deleted query bool false "Also return soft-deleted events" default(false)
+// @Param srid query int false "Output coordinate system of the geometry and bbox" default(4326)
+// @Success 200 {object} EventExtentResponse
+// @Failure 400 {object} APIError
+// @Failure 500 {object} APIError
+// @Route /v1/events/extent [get]
+func (s *Server) handleGetEventsExtent(w http.ResponseWriter, r *http.Request) {
+	srid, ok := s.parseSRIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = "open"
+	}
+
+	var open *bool
+	switch status {
+	case "open", "closed":
+		v := status == "open"
+		open = &v
+	case "all":
+	default:
+		s.writeError(w, http.StatusBadRequest, "invalid status", "status must be open, closed or all")
+		return
+	}
+
+	extent, err := s.queries.GetEventsExtent(r.Context(), db.GetEventsExtentParams{
+		Open:           open,
+		IncludeDeleted: r.URL.Query().Get("include_deleted") == "true",
+	})
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to compute events extent", err.Error())
+		return
+	}
+
+	resp := EventExtentResponse{
+		Type: "Feature",
+		Properties: EventExtentProperties{
+			Status:     status,
+			EventCount: extent.EventCount,
+		},
+	}
+	if extent.EventCount > 0 && extent.HullGeojson != "" {
+		resp.Geometry = RawJSON(extent.HullGeojson)
+		resp.BBox = []float64{extent.MinLon, extent.MinLat, extent.MaxLon, extent.MaxLat}
+		if srid != defaultSRID {
+			projected, err := s.queries.TransformGeoJSON(r.Context(), db.TransformGeoJSONParams{
+				Geojson:  extent.HullGeojson,
+				FromSrid: defaultSRID,
+				ToSrid:   srid,
+			})
+			if err != nil {
+				s.writeError(w, http.StatusInternalServerError, "failed to reproject coordinates", err.Error())
+				return
+			}
+			resp.Geometry = RawJSON(projected.Geojson)
+			resp.BBox = []float64{projected.MinX, projected.MinY, projected.MaxX, projected.MaxY}
+		}
+	}
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
+// handleGetEventHeatmapTimeseries godoc
+// @Title Event heatmap time series
+// @Description Returns event counts per time bucket and per heatmap grid cell (same 0.001° cells as the Prometheus heatmap). Every bucket between from and to is listed, empty ones without cells.
+// @Resource Events
+// @Produce json
+// @Param from query string false "Start (RFC3339), defaults to 24h before to"
+// @Param to query string false "End (RFC3339, exclusive), defaults to now"
+// @Param interval query string false "Bucket size as a Go duration" default(1h)
+// @Param bbox query string false "Bounding box as min_lon,min_lat,max_lon,max_lat"
+// @Param include_deleted query bool false "Also count soft-deleted events" default(false)
+// @Param srid query int false "Only 4326 is accepted: cells are fixed WGS84 grid labels" default(4326)
+// @Success 200 {object} EventHeatmapTimeseriesResponse
+// @Failure 400 {object} APIError
+// @Failure 500 {object} APIError
+// @Route /v1/events/heatmap/timeseries [get]
+func (s *Server) handleGetEventHeatmapTimeseries(w http.ResponseWriter, r *http.Request) {
+	if raw := r.URL.Query().Get("srid"); raw != "" && raw != strconv.Itoa(int(defaultSRID)) {
+		s.writeError(w, http.StatusBadRequest, "unsupported srid", "heatmap cells and bbox are always WGS84 (4326)")
+		return
+	}
+
+	from, to, interval, ok := s.parseTimelineRange(w, r)
+	if !ok {
+		return
+	}
+
+	params := db.CountEventsByTimeBucketAndCellParams{
+		Bucket:         pgtype.Interval{Microseconds: interval.Microseconds(), Valid: true},
+		FromTime:       pgtype.Timestamptz{Time: from, Valid: true},
+		ToTime:         pgtype.Timestamptz{Time: to, Valid: true},
+		IncludeDeleted: r.URL.Query().Get("include_deleted") == "true",
+	}
+	if raw := r.URL.Query().Get("bbox"); raw != "" {
+		bbox, err := parseBBox(raw)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, "invalid bbox", err.Error())
+			return
+		}
+		params.MinLon, params.MinLat, params.MaxLon, params.MaxLat = &bbox[0], &bbox[1], &bbox[2], &bbox[3]
+	}
+
+	rows, err := s.queries.CountEventsByTimeBucketAndCell(r.Context(), params)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to compute heatmap time series", err.Error())
+		return
+	}
+
+	// Buckets share the alignment of the SQL date_bin origin so they line up with /v1/stats/events/timeline.
+	origin := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	start := origin.Add(from.Sub(origin) / interval * interval)
+	if start.After(from) {
+		start = start.Add(-interval)
+	}
+	buckets := make([]EventHeatmapBucket, 0, int(to.Sub(start)/interval)+1)
+	index := make(map[int64]int)
+	for t := start; t.Before(to); t = t.Add(interval) {
+		index[t.Unix()] = len(buckets)
+		buckets = append(buckets, EventHeatmapBucket{Start: t, Cells: make([]EventHeatmapCell, 0)})
+	}
+
+	for _, row := range rows {
+		i, found := index[row.BucketStart.Time.UTC().Unix()]
+		if !found {
+			continue
+		}
+		// Cells are formatted like bucketCoordinate so they match the Prometheus heatmap labels
+		buckets[i].Cells = append(buckets[i].Cells, EventHeatmapCell{
+			LatBucket: strconv.FormatFloat(row.LatCell, 'f', 3, 64),
+			LonBucket: strconv.FormatFloat(row.LonCell, 'f', 3, 64),
+			Count:     row.EventCount,
+		})
+		buckets[i].Count += row.EventCount
+	}
+
+	s.writeJSON(w, http.StatusOK, EventHeatmapTimeseriesResponse{
+		From:     from,
+		To:       to,
+		Interval: interval.String(),
+		Buckets:  buckets,
+	})
+}
+
+// parseBBox parses "min_lon,min_lat,max_lon,max_lat".
+func parseBBox(raw string) ([4]float64, error) {
+	var bbox [4]float64
+	parts := strings.Split(raw, ",")
+	if len(parts) != 4 {
+		return bbox, errors.New("expected min_lon,min_lat,max_lon,max_lat")
+	}
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return bbox, fmt.Errorf("invalid coordinate %q", part)
+		}
+		bbox[i] = v
+	}
+	if bbox[0] >= bbox[2] || bbox[1] >= bbox[3] {
+		return bbox, errors.New("min must be lower than max")
+	}
+	return bbox, nil
+}
+
 // handleListEventTypes godoc
 // @Title List event types
 // @Description Returns catalog of supported incident types.
@@ -81,11 +483,12 @@ func (s *Server) handleListEventTypes(w http.ResponseWriter, r *http.Request) {
 	resp := make([]EventTypeResponse, 0, len(types))
 	for _, t := range types {
 		resp = append(resp, EventTypeResponse{
-			Code:                 t.Code,
-			Name:                 t.Name,
-			Description:          t.Description,
-			DefaultSeverity:      t.DefaultSeverity,
-			RecommendedUnitTypes: t.RecommendedUnitTypes,
+			Code:                  t.Code,
+			Name:                  t.Name,
+			Description:           t.Description,
+			DefaultSeverity:       t.DefaultSeverity,
+			RecommendedUnitTypes:  t.RecommendedUnitTypes,
+			RecommendedUnitCounts: parseUnitCounts(t.RecommendedUnitCounts, t.RecommendedUnitTypes),
 		})
 	}
 
@@ -124,24 +527,237 @@ func (s *Server) handleListUnitTypes(w http.ResponseWriter, r *http.Request) {
 
 // handleListEvents godoc
 // @Title List events
-// @Description Retrieves paginated incident list ordered by creation date.
+// @Description Retrieves paginated incident list ordered by creation date. Passing the cursor parameter (empty for the first page) switches to keyset pagination: the response becomes an EventPageResponse whose next_cursor is passed back to fetch the following page, and offset is ignored.
 // @Resource Events
 // @Produce json
 // @Param limit query int false "Maximum results" default(25)
 // @Param offset query int false "Results offset" default(0)
+// @Param cursor query string false "Opaque cursor returned as next_cursor by the previous page"
+// @Param status query string false "Event status: open, acknowledged or closed"
+// @Param min_severity query int false "Minimum severity (1-5)"
+// @Param max_severity query int false "Maximum severity (1-5)"
+// @Param event_type_code query string false "Event type code, repeatable"
+// @Param responded_by_type query string false "Only events that had a unit of this type assigned"
 // @Param deny_status query string false "Comma-separated intervention statuses to exclude (e.g., completed,cancelled)"
+// @Param srid query int false "Output coordinate system" default(4326)
+// @Param include_deleted query bool false "Also return soft-deleted events" default(false)
 // @Success 200 {array} EventSummaryResponse
+// @Success 200 {object} EventPageResponse
+// @Failure 400 {object} APIError
 // @Failure 500 {object} APIError
 // @Route /v1/events [get]
 func (s *Server) handleListEvents(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+	srid, ok := s.parseSRIDParam(w, r)
+	if !ok {
+		return
+	}
 	limit, offset := s.paginate(r, 25)
-	rows, err := s.queries.ListEvents(ctx, db.ListEventsParams{Limit: limit, Offset: offset})
+
+	filter, filtered, err := parseEventListFilter(r)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid event filter", err.Error())
+		return
+	}
+	_, useCursor := r.URL.Query()["cursor"]
+	includeDeleted := r.URL.Query().Get("include_deleted") == "true"
+
+	if filtered {
+		if useCursor {
+			s.writeError(w, http.StatusBadRequest, "invalid event filter", "filters cannot be combined with cursor pagination")
+			return
+		}
+		filter.IncludeDeleted = includeDeleted
+		filter.Limit = limit
+		filter.Offset = offset
+		rows, err := s.queries.ListEventsFiltered(r.Context(), filter)
+		if err != nil {
+			s.writeError(w, http.StatusInternalServerError, "failed to list events", err.Error())
+			return
+		}
+		events := make([]db.ListEventsRow, 0, len(rows))
+		for _, row := range rows {
+			events = append(events, db.ListEventsRow(row))
+		}
+		s.writeEventSummaries(w, r, events, srid)
+		return
+	}
+
+	// Offset mode is kept for clients that do not know about cursors
+	if !useCursor {
+		rows, err := s.queries.ListEvents(r.Context(), db.ListEventsParams{
+			IncludeDeleted: includeDeleted,
+			Limit:          limit,
+			Offset:         offset,
+		})
+		if err != nil {
+			s.writeError(w, http.StatusInternalServerError, "failed to list events", err.Error())
+			return
+		}
+		s.writeEventSummaries(w, r, rows, srid)
+		return
+	}
+
+	cursor, err := decodeEventCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid cursor", err.Error())
+		return
+	}
+	rows, err := s.queries.ListEventsAfter(r.Context(), db.ListEventsAfterParams{
+		CursorReportedAt: cursor.ReportedAt,
+		CursorID:         cursor.ID,
+		IncludeDeleted:   includeDeleted,
+		Limit:            limit,
+	})
 	if err != nil {
 		s.writeError(w, http.StatusInternalServerError, "failed to list events", err.Error())
 		return
 	}
 
+	events := make([]db.ListEventsRow, 0, len(rows))
+	for _, row := range rows {
+		events = append(events, db.ListEventsRow(row))
+	}
+	summaries, ok := s.mapEventSummaries(w, r, events, srid)
+	if !ok {
+		return
+	}
+
+	resp := EventPageResponse{Events: summaries}
+	// The cursor follows the last fetched row, not the last returned one, so
+	// rows dropped by deny_status are not fetched again on the next page
+	if len(events) > 0 && len(events) == int(limit) {
+		last := events[len(events)-1]
+		next := encodeEventCursor(eventCursor{ReportedAt: last.ReportedAt, ID: last.ID})
+		resp.NextCursor = &next
+	}
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
+// eventStatus derives the lifecycle status of an event, as filtered by ?status= on GET /v1/events.
+func eventStatus(closedAt, acknowledgedAt pgtype.Timestamptz) string {
+	switch {
+	case closedAt.Valid:
+		return "closed"
+	case acknowledgedAt.Valid:
+		return "acknowledged"
+	default:
+		return "open"
+	}
+}
+
+// parseEventListFilter reads the status, min_severity, max_severity, responded_by_type and
+// event_type_code filters of GET /v1/events. filtered reports whether any was set.
+func parseEventListFilter(r *http.Request) (filter db.ListEventsFilteredParams, filtered bool, err error) {
+	query := r.URL.Query()
+
+	if status := query.Get("status"); status != "" {
+		switch status {
+		case "open", "acknowledged", "closed":
+		default:
+			return filter, false, fmt.Errorf("unknown status %q: must be open, acknowledged or closed", status)
+		}
+		filter.Status = &status
+		filtered = true
+	}
+
+	for _, p := range []struct {
+		name string
+		dst  **int32
+	}{
+		{"min_severity", &filter.MinSeverity},
+		{"max_severity", &filter.MaxSeverity},
+	} {
+		raw := query.Get(p.name)
+		if raw == "" {
+			continue
+		}
+		v, err := parseInt32(raw)
+		if err != nil || v < 1 || v > 5 {
+			return filter, false, fmt.Errorf("%s must be an integer between 1 and 5", p.name)
+		}
+		*p.dst = &v
+		filtered = true
+	}
+	if filter.MinSeverity != nil && filter.MaxSeverity != nil && *filter.MinSeverity > *filter.MaxSeverity {
+		return filter, false, errors.New("min_severity must not be greater than max_severity")
+	}
+
+	for _, code := range query["event_type_code"] {
+		if code = strings.TrimSpace(code); code != "" {
+			filter.EventTypeCodes = append(filter.EventTypeCodes, code)
+		}
+	}
+	if len(filter.EventTypeCodes) > 0 {
+		filtered = true
+	}
+
+	if unitType := strings.TrimSpace(query.Get("responded_by_type")); unitType != "" {
+		filter.RespondedByType = &unitType
+		filtered = true
+	}
+
+	return filter, filtered, nil
+}
+
+// eventCursor is the keyset position of GET /v1/events: the last seen (reported_at, id).
+type eventCursor struct {
+	ReportedAt pgtype.Timestamptz
+	ID         pgtype.UUID
+}
+
+// encodeEventCursor serialises the cursor as base64 of "<reported_at RFC3339Nano>|<id>"
+// so clients treat it as opaque.
+func encodeEventCursor(c eventCursor) string {
+	raw := c.ReportedAt.Time.UTC().Format(time.RFC3339Nano) + "|" + uuidString(c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeEventCursor parses a cursor from encodeEventCursor. An empty value
+// starts from the newest event.
+func decodeEventCursor(value string) (eventCursor, error) {
+	if value == "" {
+		return eventCursor{
+			ReportedAt: pgtype.Timestamptz{InfinityModifier: pgtype.Infinity, Valid: true},
+			ID:         pgtype.UUID{Bytes: [16]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, Valid: true},
+		}, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return eventCursor{}, err
+	}
+	ts, id, found := strings.Cut(string(raw), "|")
+	if !found {
+		return eventCursor{}, errors.New("malformed cursor")
+	}
+	reportedAt, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return eventCursor{}, err
+	}
+	eventID, err := pgUUIDFromString(id)
+	if err != nil {
+		return eventCursor{}, err
+	}
+	return eventCursor{
+		ReportedAt: pgtype.Timestamptz{Time: reportedAt, Valid: true},
+		ID:         eventID,
+	}, nil
+}
+
+// writeEventSummaries maps event rows with their assigned units, drops the
+// intervention statuses listed in ?deny_status= and reprojects to srid.
+func (s *Server) writeEventSummaries(w http.ResponseWriter, r *http.Request, rows []db.ListEventsRow, srid int32) {
+	resp, ok := s.mapEventSummaries(w, r, rows, srid)
+	if !ok {
+		return
+	}
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
+// mapEventSummaries builds the summaries written by writeEventSummaries. On
+// failure the error response has already been written and ok is false.
+func (s *Server) mapEventSummaries(w http.ResponseWriter, r *http.Request, rows []db.ListEventsRow, srid int32) ([]EventSummaryResponse, bool) {
+	ctx := r.Context()
 	denySet := s.parseDenySet(r.URL.Query().Get("deny_status"))
 
 	resp := make([]EventSummaryResponse, 0, len(rows))
@@ -154,7 +770,7 @@ func (s *Server) handleListEvents(w http.ResponseWriter, r *http.Request) {
 		assigned, assignErr := s.queries.ListUnitsAssignedToEvent(ctx, row.ID)
 		if assignErr != nil {
 			s.writeError(w, http.StatusInternalServerError, "failed to list assigned units", assignErr.Error())
-			return
+			return nil, false
 		}
 
 		assignedUnits := make([]UnitResponse, 0, len(assigned))
@@ -177,62 +793,275 @@ func (s *Server) handleListEvents(w http.ResponseWriter, r *http.Request) {
 
 		resp = append(resp, mapEventSummary(row, assignedUnits))
 	}
-	s.writeJSON(w, http.StatusOK, resp)
-}
 
-func (s *Server) parseDenySet(denyParam string) map[db.InterventionStatus]struct{} {
-	if denyParam == "" {
-		return nil
+	var points []*GeoPoint
+	for i := range resp {
+		points = append(points, eventSummaryPoints(&resp[i])...)
 	}
-	parts := splitCSV(denyParam)
-	denySet := make(map[db.InterventionStatus]struct{}, len(parts))
-	for _, p := range parts {
-		st := db.InterventionStatus(p)
-		switch st {
-		case db.InterventionStatusCreated, db.InterventionStatusOnSite, db.InterventionStatusCompleted, db.InterventionStatusCancelled:
-			denySet[st] = struct{}{}
-		}
+	if err := s.reprojectPoints(ctx, srid, points); err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to reproject coordinates", err.Error())
+		return nil, false
 	}
-	return denySet
+	return resp, true
 }
 
-// splitCSV trims and splits a comma-separated list.
-func splitCSV(s string) []string {
-	parts := strings.Split(s, ",")
-	out := make([]string, 0, len(parts))
-	for _, p := range parts {
-		trimmed := strings.TrimSpace(p)
-		if trimmed != "" {
-			out = append(out, trimmed)
-		}
-	}
-	return out
+// maxBoundsSpanDegrees is the widest latitude or longitude span accepted by /v1/events/in-bounds.
+const maxBoundsSpanDegrees = 5
+
+// EventBoundsQuery holds the viewport of GET /v1/events/in-bounds.
+type EventBoundsQuery struct {
+	MinLat float64 `validate:"latitude"`
+	MinLon float64 `validate:"longitude"`
+	MaxLat float64 `validate:"latitude"`
+	MaxLon float64 `validate:"longitude"`
 }
 
-// handleCreateEvent godoc
-// @Title Create event
-// @Description Registers a new incident in the system.
+// handleListEventsInBounds godoc
+// @Title List events in bounds
+// @Description Retrieves the events located inside a map viewport, newest first. The box may span at most 5 degrees in each direction. With ?srid= the bounds are read in that coordinate system too (X as lon, Y as lat).
 // @Resource Events
-// @Accept json
 // @Produce json
-// @Param auto_intervention query boolean false "Automatically create an intervention for this event"
-// @Param decision_mode query string false "Decision mode for the auto-created intervention" Enums(auto_suggested, manual) default(auto_suggested)
-// @Param request body CreateEventRequest true "Event payload"
-// @Success 201 {object} EventSummaryResponse
+// @Param min_lat query number true "South edge"
+// @Param min_lon query number true "West edge"
+// @Param max_lat query number true "North edge"
+// @Param max_lon query number true "East edge"
+// @Param limit query int false "Maximum results" default(500)
+// @Param offset query int false "Results offset" default(0)
+// @Param deny_status query string false "Comma-separated intervention statuses to exclude (e.g., completed,cancelled)"
+// @Param srid query int false "Coordinate system of the bounds and the output" default(4326)
+// @Param include_deleted query bool false "Also return soft-deleted events" default(false)
+// @Success 200 {array} EventSummaryResponse
 // @Failure 400 {object} APIError
 // @Failure 500 {object} APIError
-// @Route /v1/events [post]
-func (s *Server) handleCreateEvent(w http.ResponseWriter, r *http.Request) {
-	// Require 'superieur', 'it', or 'manage-events'
-	if !s.authMw.RequireOneOfRoles(w, r, "superieur", "it", "manage-events") {
+// @Route /v1/events/in-bounds [get]
+func (s *Server) handleListEventsInBounds(w http.ResponseWriter, r *http.Request) {
+	srid, ok := s.parseSRIDParam(w, r)
+	if !ok {
 		return
 	}
 
-	var req CreateEventRequest
-	if err := s.decodeAndValidate(r, &req); err != nil {
-		s.writeError(w, http.StatusBadRequest, errInvalidPayload, err.Error())
-		return
-	}
+	query := r.URL.Query()
+	var bounds EventBoundsQuery
+	for _, p := range []struct {
+		name string
+		dst  *float64
+	}{
+		{"min_lat", &bounds.MinLat},
+		{"min_lon", &bounds.MinLon},
+		{"max_lat", &bounds.MaxLat},
+		{"max_lon", &bounds.MaxLon},
+	} {
+		raw := query.Get(p.name)
+		if raw == "" {
+			s.writeError(w, http.StatusBadRequest, "missing "+p.name, nil)
+			return
+		}
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, "invalid "+p.name, err.Error())
+			return
+		}
+		*p.dst = v
+	}
+	if srid != defaultSRID {
+		var ok bool
+		if bounds, ok = s.boundsToWGS84(w, r, bounds, srid); !ok {
+			return
+		}
+	}
+	if err := s.validate.Struct(bounds); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid bounds", err.Error())
+		return
+	}
+	if bounds.MinLat >= bounds.MaxLat || bounds.MinLon >= bounds.MaxLon {
+		s.writeError(w, http.StatusBadRequest, "invalid bounds", "min values must be lower than max values")
+		return
+	}
+	if bounds.MaxLat-bounds.MinLat > maxBoundsSpanDegrees || bounds.MaxLon-bounds.MinLon > maxBoundsSpanDegrees {
+		s.writeError(w, http.StatusBadRequest, "bounds too large", map[string]int{"max_span_degrees": maxBoundsSpanDegrees})
+		return
+	}
+
+	limit, offset := s.paginate(r, 500)
+	rows, err := s.queries.ListEventsInBounds(r.Context(), db.ListEventsInBoundsParams{
+		MinLon:         bounds.MinLon,
+		MinLat:         bounds.MinLat,
+		MaxLon:         bounds.MaxLon,
+		MaxLat:         bounds.MaxLat,
+		IncludeDeleted: r.URL.Query().Get("include_deleted") == "true",
+		Limit:          limit,
+		Offset:         offset,
+	})
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to list events in bounds", err.Error())
+		return
+	}
+
+	events := make([]db.ListEventsRow, 0, len(rows))
+	for _, row := range rows {
+		events = append(events, db.ListEventsRow(row))
+	}
+	s.writeEventSummaries(w, r, events, srid)
+}
+
+// boundsToWGS84 converts bounds given in srid to the WGS84 box enclosing them. It writes
+// a 400 and returns false when the bounds cannot be transformed.
+func (s *Server) boundsToWGS84(w http.ResponseWriter, r *http.Request, bounds EventBoundsQuery, srid int32) (EventBoundsQuery, bool) {
+	if bounds.MinLat >= bounds.MaxLat || bounds.MinLon >= bounds.MaxLon {
+		s.writeError(w, http.StatusBadRequest, "invalid bounds", "min values must be lower than max values")
+		return bounds, false
+	}
+	polygon := fmt.Sprintf(`{"type":"Polygon","coordinates":[[[%[1]g,%[2]g],[%[3]g,%[2]g],[%[3]g,%[4]g],[%[1]g,%[4]g],[%[1]g,%[2]g]]]}`,
+		bounds.MinLon, bounds.MinLat, bounds.MaxLon, bounds.MaxLat)
+	box, err := s.queries.TransformGeoJSON(r.Context(), db.TransformGeoJSONParams{
+		Geojson:  polygon,
+		FromSrid: srid,
+		ToSrid:   defaultSRID,
+	})
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid bounds", err.Error())
+		return bounds, false
+	}
+	return EventBoundsQuery{MinLon: box.MinX, MinLat: box.MinY, MaxLon: box.MaxX, MaxLat: box.MaxY}, true
+}
+
+// minSearchQueryLength is the shortest q accepted by /v1/events/search.
+const minSearchQueryLength = 2
+
+// handleSearchEvents godoc
+// @Title Search events
+// @Description Full-text search over event title, description and address, ordered by relevance then most recent first. Returns an empty array when nothing matches.
+// @Resource Events
+// @Produce json
+// @Param q query string true "Search terms (at least 2 characters)"
+// @Param limit query int false "Maximum results" default(25)
+// @Param offset query int false "Results offset" default(0)
+// @Param deny_status query string false "Comma-separated intervention statuses to exclude (e.g., completed,cancelled)"
+// @Param srid query int false "Output coordinate system" default(4326)
+// @Param include_deleted query bool false "Also return soft-deleted events" default(false)
+// @Success 200 {array} EventSummaryResponse
+// @Failure 400 {object} APIError
+// @Failure 500 {object} APIError
+// @Route /v1/events/search [get]
+func (s *Server) handleSearchEvents(w http.ResponseWriter, r *http.Request) {
+	srid, ok := s.parseSRIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if len([]rune(q)) < minSearchQueryLength {
+		s.writeError(w, http.StatusBadRequest, "invalid search query", fmt.Sprintf("q must be at least %d characters", minSearchQueryLength))
+		return
+	}
+
+	limit, offset := s.paginate(r, 25)
+	rows, err := s.queries.SearchEvents(r.Context(), db.SearchEventsParams{
+		Query:          q,
+		IncludeDeleted: r.URL.Query().Get("include_deleted") == "true",
+		Limit:          limit,
+		Offset:         offset,
+	})
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to search events", err.Error())
+		return
+	}
+
+	events := make([]db.ListEventsRow, 0, len(rows))
+	for _, row := range rows {
+		events = append(events, db.ListEventsRow(row))
+	}
+	s.writeEventSummaries(w, r, events, srid)
+}
+
+func (s *Server) parseDenySet(denyParam string) map[db.InterventionStatus]struct{} {
+	if denyParam == "" {
+		return nil
+	}
+	parts := splitCSV(denyParam)
+	denySet := make(map[db.InterventionStatus]struct{}, len(parts))
+	for _, p := range parts {
+		st := db.InterventionStatus(p)
+		if isKnownInterventionStatus(st) {
+			denySet[st] = struct{}{}
+		}
+	}
+	return denySet
+}
+
+// isKnownInterventionStatus reports whether st is a value of the intervention_status enum.
+func isKnownInterventionStatus(st db.InterventionStatus) bool {
+	switch st {
+	case db.InterventionStatusCreated, db.InterventionStatusOnSite, db.InterventionStatusCompleted, db.InterventionStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// splitCSV trims and splits a comma-separated list.
+func splitCSV(s string) []string {
+	parts := strings.Split(s, ",")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		trimmed := strings.TrimSpace(p)
+		if trimmed != "" {
+			out = append(out, trimmed)
+		}
+	}
+	return out
+}
+
+// handleCreateEvent godoc
+// @Title Create event
+// @Description Registers a new incident in the system. Severity defaults to the event type's default when omitted. An optional reported_at back-dates imported events (it role only).
+// @Resource Events
+// @Accept json
+// @Produce json
+// @Param auto_intervention query boolean false "Automatically create an intervention for this event"
+// @Param decision_mode query string false "Decision mode for the auto-created intervention" Enums(auto_suggested, manual) default(auto_suggested)
+// @Param request body CreateEventRequest true "Event payload"
+// @Success 201 {object} EventSummaryResponse
+// @Failure 400 {object} APIError
+// @Failure 403 {object} APIError
+// @Failure 500 {object} APIError
+// @Route /v1/events [post]
+func (s *Server) handleCreateEvent(w http.ResponseWriter, r *http.Request) {
+	// Require 'superieur', 'it', or 'manage-events'
+	if !s.authMw.RequireOneOfRoles(w, r, "superieur", "it", "manage-events") {
+		return
+	}
+
+	var req CreateEventRequest
+	if err := s.decodeAndValidate(r, &req); err != nil {
+		s.writeError(w, http.StatusBadRequest, errInvalidPayload, err.Error())
+		return
+	}
+	if !s.checkReportedAt(w, r, req.ReportedAt) {
+		return
+	}
+
+	var severity int32
+	if req.Severity != nil {
+		severity = *req.Severity
+	} else {
+		defaultSeverity, err := s.queries.GetEventTypeDefaultSeverity(r.Context(), req.EventTypeCode)
+		if err != nil {
+			if isNotFound(err) {
+				s.writeError(w, http.StatusBadRequest, "unknown event type", req.EventTypeCode)
+				return
+			}
+			s.writeError(w, http.StatusInternalServerError, "failed to fetch event type", err.Error())
+			return
+		}
+		severity = defaultSeverity
+	}
+	if severity < 1 || severity > 5 {
+		s.writeError(w, http.StatusBadRequest, errInvalidPayload, "severity must be between 1 and 5")
+		return
+	}
+	if !s.checkEventCombination(w, req.EventTypeCode, severity) {
+		return
+	}
 
 	params := db.CreateEventParams{
 		Title:         req.Title,
@@ -241,8 +1070,9 @@ func (s *Server) handleCreateEvent(w http.ResponseWriter, r *http.Request) {
 		Address:       req.Address,
 		Longitude:     req.Longitude,
 		Latitude:      req.Latitude,
-		Severity:      req.Severity,
+		Severity:      severity,
 		EventTypeCode: req.EventTypeCode,
+		ReportedAt:    timestamptzFromPtr(req.ReportedAt),
 	}
 
 	row, err := s.queries.CreateEvent(r.Context(), params)
@@ -282,7 +1112,7 @@ func (s *Server) handleCreateEvent(w http.ResponseWriter, r *http.Request) {
 			// Log the creation
 			s.logInterventionStatusChange(r.Context(), intervention.ID, row.ID, "", string(db.InterventionStatusCreated), nil)
 			// Trigger engine dispatch
-			s.notifyEngineDispatch(r.Context(), uuidString(intervention.ID))
+			go s.notifyEngineDispatch(context.Background(), uuidString(intervention.ID))
 		}
 	}
 
@@ -296,6 +1126,7 @@ func (s *Server) handleCreateEvent(w http.ResponseWriter, r *http.Request) {
 // @Resource Events
 // @Produce json
 // @Param eventID path string true "Event ID"
+// @Param srid query int false "Output coordinate system" default(4326)
 // @Success 200 {object} EventDetailResponse
 // @Failure 400 {object} APIError
 // @Failure 404 {object} APIError
@@ -307,7 +1138,16 @@ func (s *Server) handleGetEvent(w http.ResponseWriter, r *http.Request) {
 		s.writeError(w, http.StatusBadRequest, errInvalidEventID, err.Error())
 		return
 	}
+	srid, ok := s.parseSRIDParam(w, r)
+	if !ok {
+		return
+	}
 
+	s.writeEventDetail(w, r, eventID, srid)
+}
+
+// writeEventDetail loads an event with its interventions, assigned units and latest logs.
+func (s *Server) writeEventDetail(w http.ResponseWriter, r *http.Request, eventID pgtype.UUID, srid int32) {
 	eventRow, err := s.queries.GetEvent(r.Context(), eventID)
 	if err != nil {
 		if isNotFound(err) {
@@ -360,6 +1200,11 @@ func (s *Server) handleGetEvent(w http.ResponseWriter, r *http.Request) {
 
 	resp := mapEventDetail(eventRow, interventions, logs)
 	resp.AssignedUnits = assignedUnits
+	if err := s.reprojectPoints(r.Context(), srid, eventSummaryPoints(&resp.EventSummaryResponse)); err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to reproject coordinates", err.Error())
+		return
+	}
+	setVersionETag(w, eventRow.Version)
 	s.writeJSON(w, http.StatusOK, resp)
 }
 
@@ -494,9 +1339,17 @@ func mapEventSummary(row db.ListEventsRow, assignedUnits []UnitResponse) EventSu
 		StartedAt:          timestamptzPtr(row.InterventionStartedAt),
 		CompletedAt:        timestamptzPtr(row.InterventionCompletedAt),
 		AssignedUnits:      assignedUnits,
+		Version:            row.Version,
 	}
 }
 
+// eventSummaryPoints collects the event location and its assigned unit
+// locations for reprojection.
+func eventSummaryPoints(e *EventSummaryResponse) []*GeoPoint {
+	points := []*GeoPoint{&e.Location}
+	return append(points, unitPoints(e.AssignedUnits)...)
+}
+
 func mapCreateEventRow(row db.CreateEventRow) EventSummaryResponse {
 	return EventSummaryResponse{
 		ID:           uuidString(row.ID),
@@ -545,6 +1398,7 @@ func mapEventDetail(event db.GetEventRow, interventions []db.Intervention, logs
 		ReportedAt:         event.ReportedAt.Time,
 		UpdatedAt:          event.UpdatedAt.Time,
 		ClosedAt:           timestamptzPtr(event.ClosedAt),
+		Version:            event.Version,
 	}
 
 	var associatedIntervention *InterventionResponse
@@ -556,6 +1410,8 @@ func mapEventDetail(event db.GetEventRow, interventions []db.Intervention, logs
 	resp := EventDetailResponse{
 		EventSummaryResponse: summary,
 		RecommendedUnitTypes: event.RecommendedUnitTypes,
+		AcknowledgedAt:       timestamptzPtr(event.AcknowledgedAt),
+		AcknowledgedBy:       optionalString(event.AcknowledgedBy),
 		Intervention:         associatedIntervention,
 	}
 
@@ -595,9 +1451,299 @@ func stringPtr(s string) *string {
 	return &s
 }
 
+// StandDownRequest is the request payload for standing down an event.
+type StandDownRequest struct {
+	Reason string `json:"reason" validate:"required,min=3,max=500"`
+}
+
+// handleDeleteEvent godoc
+// @Title Delete event
+// @Description Soft-deletes an event: it is hidden from the event lists unless include_deleted=true is passed, but kept for audit. Events with an intervention that is neither completed nor cancelled cannot be deleted.
+// @Resource Events
+// @Produce json
+// @Param eventID path string true "Event ID"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} APIError
+// @Failure 403 {object} APIError
+// @Failure 404 {object} APIError
+// @Failure 409 {object} APIError
+// @Failure 500 {object} APIError
+// @Route /v1/events/{eventID} [delete]
+func (s *Server) handleDeleteEvent(w http.ResponseWriter, r *http.Request) {
+	if !s.authMw.RequireOneOfRoles(w, r, "superieur", "it") {
+		return
+	}
+
+	ctx := r.Context()
+
+	eventID, err := s.parseUUIDParam(r, "eventID")
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, errInvalidEventID, err.Error())
+		return
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to start transaction", err.Error())
+		return
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	qtx := s.queries.WithTx(tx)
+
+	if _, err := qtx.LockEvent(ctx, eventID); err != nil {
+		if isNotFound(err) {
+			s.writeError(w, http.StatusNotFound, "event not found", nil)
+			return
+		}
+		s.writeError(w, http.StatusInternalServerError, "failed to lock event", err.Error())
+		return
+	}
+
+	interventions, err := qtx.ListInterventionsByEvent(ctx, eventID)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to list interventions", err.Error())
+		return
+	}
+	var active []string
+	for _, intervention := range interventions {
+		if intervention.Status != db.InterventionStatusCompleted && intervention.Status != db.InterventionStatusCancelled {
+			active = append(active, uuidString(intervention.ID))
+		}
+	}
+	if len(active) > 0 {
+		s.writeError(w, http.StatusConflict, "event has active interventions", map[string]interface{}{
+			"intervention_ids": active,
+		})
+		return
+	}
+
+	deleted, err := qtx.SoftDeleteEvent(ctx, eventID)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to delete event", err.Error())
+		return
+	}
+
+	entityType := "event"
+	if _, err := qtx.CreateActivityLog(ctx, db.CreateActivityLogParams{
+		ActivityType: "event_deleted",
+		EntityType:   &entityType,
+		EntityID:     eventID,
+		Actor:        actorFromContext(ctx),
+	}); err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to log event deletion", err.Error())
+		return
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to commit event deletion", err.Error())
+		return
+	}
+
+	s.log.Info().
+		Str("event_id", uuidString(eventID)).
+		Time("deleted_at", deleted.DeletedAt.Time).
+		Msg("event soft-deleted")
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// StandDownResponse summarises what a stand-down cancelled and released.
+type StandDownResponse struct {
+	EventID                  string    `json:"event_id"`
+	Reason                   string    `json:"reason"`
+	ClosedAt                 time.Time `json:"closed_at"`
+	CancelledInterventionIDs []string  `json:"cancelled_intervention_ids"`
+	ReleasedUnitIDs          []string  `json:"released_unit_ids"`
+}
+
+// handleStandDownEvent godoc
+// @Title Stand down event
+// @Description Cancels every non-completed intervention of an event, releases their units and closes the event in one transaction. Used for false alarms.
+// @Resource Events
+// @Accept json
+// @Produce json
+// @Param eventID path string true "Event ID"
+// @Param request body StandDownRequest true "Stand-down reason"
+// @Success 200 {object} StandDownResponse
+// @Failure 400 {object} APIError
+// @Failure 403 {object} APIError
+// @Failure 404 {object} APIError
+// @Failure 500 {object} APIError
+// @Route /v1/events/{eventID}/stand-down [post]
+func (s *Server) handleStandDownEvent(w http.ResponseWriter, r *http.Request) {
+	if !s.authMw.RequireOneOfRoles(w, r, "superieur", "it", "manage-events") {
+		return
+	}
+
+	ctx := r.Context()
+
+	eventID, err := s.parseUUIDParam(r, "eventID")
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, errInvalidEventID, err.Error())
+		return
+	}
+
+	var req StandDownRequest
+	if err := s.decodeAndValidate(r, &req); err != nil {
+		s.writeError(w, http.StatusBadRequest, errInvalidPayload, err.Error())
+		return
+	}
+	actor := actorFromContext(ctx)
+
+	type interventionChange struct {
+		id        pgtype.UUID
+		oldStatus string
+	}
+	var cancelled []interventionChange
+	var released []releasedAssignment
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to start transaction", err.Error())
+		return
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	qtx := s.queries.WithTx(tx)
+
+	// Lock the event so no intervention is created while it is being stood down
+	if _, err := qtx.LockEvent(ctx, eventID); err != nil {
+		if isNotFound(err) {
+			s.writeError(w, http.StatusNotFound, "event not found", nil)
+			return
+		}
+		s.writeError(w, http.StatusInternalServerError, "failed to lock event", err.Error())
+		return
+	}
+
+	interventions, err := qtx.ListInterventionsByEvent(ctx, eventID)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to list interventions", err.Error())
+		return
+	}
+
+	for _, intervention := range interventions {
+		status, err := qtx.LockIntervention(ctx, intervention.ID)
+		if err != nil {
+			s.writeError(w, http.StatusInternalServerError, "failed to lock intervention", err.Error())
+			return
+		}
+		if status == db.InterventionStatusCompleted || status == db.InterventionStatusCancelled {
+			continue
+		}
+
+		units, err := s.releaseInterventionUnits(ctx, qtx, intervention.ID)
+		if err != nil {
+			s.writeError(w, http.StatusInternalServerError, "failed to release assignments", err.Error())
+			return
+		}
+		released = append(released, units...)
+
+		if _, err := qtx.UpdateInterventionStatus(ctx, db.UpdateInterventionStatusParams{
+			ID:      intervention.ID,
+			Column2: db.InterventionStatusCancelled,
+		}); err != nil {
+			s.writeError(w, http.StatusInternalServerError, "failed to cancel intervention", err.Error())
+			return
+		}
+		cancelled = append(cancelled, interventionChange{id: intervention.ID, oldStatus: string(status)})
+	}
+
+	event, err := qtx.GetEvent(ctx, eventID)
+	if err != nil {
+		if isNotFound(err) {
+			s.writeError(w, http.StatusNotFound, "event not found", nil)
+			return
+		}
+		s.writeError(w, http.StatusInternalServerError, "failed to fetch event", err.Error())
+		return
+	}
+	oldStatus := eventStatus(event.ClosedAt, event.AcknowledgedAt)
+
+	closed, err := qtx.CloseEvent(ctx, eventID)
+	if err != nil {
+		if isNotFound(err) {
+			s.writeError(w, http.StatusNotFound, "event not found", nil)
+			return
+		}
+		s.writeError(w, http.StatusInternalServerError, "failed to close event", err.Error())
+		return
+	}
+
+	resp := StandDownResponse{
+		EventID:                  uuidString(eventID),
+		Reason:                   req.Reason,
+		ClosedAt:                 closed.ClosedAt.Time,
+		CancelledInterventionIDs: make([]string, 0, len(cancelled)),
+		ReleasedUnitIDs:          make([]string, 0, len(released)),
+	}
+	for _, c := range cancelled {
+		resp.CancelledInterventionIDs = append(resp.CancelledInterventionIDs, uuidString(c.id))
+	}
+	for _, a := range released {
+		resp.ReleasedUnitIDs = append(resp.ReleasedUnitIDs, uuidString(a.unitID))
+	}
+
+	metadata, _ := json.Marshal(map[string]interface{}{
+		"reason":                     req.Reason,
+		"cancelled_intervention_ids": resp.CancelledInterventionIDs,
+		"released_unit_ids":          resp.ReleasedUnitIDs,
+	})
+	entityType := "event"
+	if _, err := qtx.CreateActivityLog(ctx, db.CreateActivityLogParams{
+		ActivityType: "stand_down",
+		EntityType:   &entityType,
+		EntityID:     eventID,
+		Actor:        actor,
+		NewValue:     &req.Reason,
+		Metadata:     metadata,
+	}); err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to log stand-down", err.Error())
+		return
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to commit stand-down", err.Error())
+		return
+	}
+	if len(released) > 0 {
+		s.invalidateStaticData()
+	}
+
+	if oldStatus != "closed" {
+		if logErr := s.logEventStatusChange(ctx, s.queries, eventID, oldStatus, "closed", actor); logErr != nil {
+			s.log.Warn().Err(logErr).Msg("failed to log event status change")
+		}
+	}
+	for _, c := range cancelled {
+		if logErr := s.logInterventionStatusChange(ctx, c.id, eventID, c.oldStatus, string(db.InterventionStatusCancelled), actor); logErr != nil {
+			s.log.Error().Err(logErr).Msg("failed to log intervention status change")
+		}
+	}
+	for _, a := range released {
+		if logErr := s.logUnitStatusChange(ctx, a.unitID, a.callSign, a.oldStatus, string(db.UnitStatusAvailable), actor); logErr != nil {
+			s.log.Error().Err(logErr).Msg("failed to log unit status change")
+		}
+		s.logAssignmentChange(ctx, eventLogUnitReleased, a.assignmentID, actor)
+		s.observeAssignmentOnSite(ctx, a.assignmentID)
+
+		// Trigger return to station routing
+		go s.calculateAndSaveRouteToStation(context.Background(), a.unitID)
+	}
+
+	s.log.Info().
+		Str("event_id", resp.EventID).
+		Int("cancelled_interventions", len(cancelled)).
+		Int("released_units", len(released)).
+		Str("reason", req.Reason).
+		Msg("event stood down")
+
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
 // UpdateEventAutoSimulatedRequest is the request payload for toggling auto_simulated.
 type UpdateEventAutoSimulatedRequest struct {
-	AutoSimulated bool `json:"auto_simulated"`
+	AutoSimulated bool   `json:"auto_simulated"`
+	Version       *int32 `json:"version"`
 }
 
 // UpdateEventAutoSimulatedResponse is the response when toggling auto_simulated.
@@ -605,6 +1751,154 @@ type UpdateEventAutoSimulatedResponse struct {
 	ID            string    `json:"id"`
 	AutoSimulated bool      `json:"auto_simulated"`
 	UpdatedAt     time.Time `json:"updated_at"`
+	Version       int32     `json:"version"`
+}
+
+// eventFieldChange is the old and new value of one field in an event_updated log entry.
+type eventFieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// handleUpdateEvent godoc
+// @Title Update event
+// @Description Corrects the severity, location, address or description of an event. Only the fields present in the body are changed, and an event_updated entry with the old and new values is added to the event timeline. Changes based on a stale version (If-Match header or version field) are rejected with 409.
+// @Resource Events
+// @Accept json
+// @Produce json
+// @Param eventID path string true "Event ID"
+// @Param request body UpdateEventRequest true "Fields to change"
+// @Success 200 {object} EventDetailResponse
+// @Failure 400 {object} APIError
+// @Failure 403 {object} APIError
+// @Failure 404 {object} APIError
+// @Failure 409 {object} APIError
+// @Failure 500 {object} APIError
+// @Route /v1/events/{eventID} [patch]
+func (s *Server) handleUpdateEvent(w http.ResponseWriter, r *http.Request) {
+	if !s.authMw.RequireOneOfRoles(w, r, "superieur", "it", "manage-events") {
+		return
+	}
+
+	ctx := r.Context()
+
+	eventID, err := s.parseUUIDParam(r, "eventID")
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, errInvalidEventID, err.Error())
+		return
+	}
+
+	var req UpdateEventRequest
+	if err := s.decodeAndValidate(r, &req); err != nil {
+		s.writeError(w, http.StatusBadRequest, errInvalidPayload, err.Error())
+		return
+	}
+	if (req.Latitude == nil) != (req.Longitude == nil) {
+		s.writeError(w, http.StatusBadRequest, errInvalidPayload, "latitude and longitude must be provided together")
+		return
+	}
+	if req.Severity == nil && req.Latitude == nil && req.Address == nil && req.Description == nil {
+		s.writeError(w, http.StatusBadRequest, errInvalidPayload, "no field to update")
+		return
+	}
+	expected, ok := s.expectedVersion(w, r, req.Version)
+	if !ok {
+		return
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to start transaction", err.Error())
+		return
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	qtx := s.queries.WithTx(tx)
+
+	if _, err := qtx.LockEvent(ctx, eventID); err != nil {
+		if isNotFound(err) {
+			s.writeError(w, http.StatusNotFound, "event not found", nil)
+			return
+		}
+		s.writeError(w, http.StatusInternalServerError, "failed to lock event", err.Error())
+		return
+	}
+	current, err := qtx.GetEvent(ctx, eventID)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to fetch event", err.Error())
+		return
+	}
+	if expected != nil && *expected != current.Version {
+		s.writeVersionConflict(w, current.Version, *expected)
+		return
+	}
+
+	changes := make(map[string]eventFieldChange)
+	if req.Severity != nil && *req.Severity != current.Severity {
+		if !s.checkEventCombination(w, current.EventTypeCode, *req.Severity) {
+			return
+		}
+		changes["severity"] = eventFieldChange{Old: current.Severity, New: *req.Severity}
+	}
+	if req.Latitude != nil && (*req.Latitude != current.Latitude || *req.Longitude != current.Longitude) {
+		changes["location"] = eventFieldChange{
+			Old: GeoPoint{Latitude: current.Latitude, Longitude: current.Longitude},
+			New: GeoPoint{Latitude: *req.Latitude, Longitude: *req.Longitude},
+		}
+	}
+	if req.Address != nil && *req.Address != optionalString(current.Address) {
+		changes["address"] = eventFieldChange{Old: current.Address, New: *req.Address}
+	}
+	if req.Description != nil && *req.Description != optionalString(current.Description) {
+		changes["description"] = eventFieldChange{Old: current.Description, New: *req.Description}
+	}
+
+	if len(changes) > 0 {
+		if _, err := qtx.UpdateEvent(ctx, db.UpdateEventParams{
+			Severity:        req.Severity,
+			Longitude:       req.Longitude,
+			Latitude:        req.Latitude,
+			Address:         req.Address,
+			Description:     req.Description,
+			ID:              eventID,
+			ExpectedVersion: expected,
+		}); err != nil {
+			s.writeError(w, http.StatusInternalServerError, "failed to update event", err.Error())
+			return
+		}
+
+		metadata, _ := json.Marshal(map[string]interface{}{"changes": changes})
+		entityType := "event"
+		if _, err := qtx.CreateActivityLog(ctx, db.CreateActivityLogParams{
+			ActivityType: "event_updated",
+			EntityType:   &entityType,
+			EntityID:     eventID,
+			Actor:        actorFromContext(ctx),
+			Metadata:     metadata,
+		}); err != nil {
+			s.writeError(w, http.StatusInternalServerError, "failed to log event update", err.Error())
+			return
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to commit event update", err.Error())
+		return
+	}
+
+	_, severityChanged := changes["severity"]
+	_, locationChanged := changes["location"]
+	if severityChanged || locationChanged {
+		// Keep the heatmap in line with the corrected severity and location
+		go func() {
+			syncCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if syncErr := SyncIncidentMetrics(syncCtx, s.queries, s.log); syncErr != nil {
+				s.log.Warn().Err(syncErr).Msg("failed to sync incident metrics after update")
+			}
+		}()
+	}
+
+	s.writeEventDetail(w, r, eventID, defaultSRID)
 }
 
 // handleUpdateEventAutoSimulated godoc
@@ -619,6 +1913,7 @@ type UpdateEventAutoSimulatedResponse struct {
 // @Failure 400 {object} APIError
 // @Failure 403 {object} APIError
 // @Failure 404 {object} APIError
+// @Failure 409 {object} APIError
 // @Failure 500 {object} APIError
 // @Route /v1/events/{eventID}/auto-simulated [patch]
 func (s *Server) handleUpdateEventAutoSimulated(w http.ResponseWriter, r *http.Request) {
@@ -638,23 +1933,267 @@ func (s *Server) handleUpdateEventAutoSimulated(w http.ResponseWriter, r *http.R
 		s.writeError(w, http.StatusBadRequest, errInvalidPayload, err.Error())
 		return
 	}
+	expected, ok := s.expectedVersion(w, r, req.Version)
+	if !ok {
+		return
+	}
 
 	row, err := s.queries.UpdateEventAutoSimulated(r.Context(), db.UpdateEventAutoSimulatedParams{
-		ID:            eventID,
-		AutoSimulated: req.AutoSimulated,
+		ID:              eventID,
+		AutoSimulated:   req.AutoSimulated,
+		ExpectedVersion: expected,
 	})
 	if err != nil {
 		if isNotFound(err) {
-			s.writeError(w, http.StatusNotFound, "event not found", nil)
+			s.writeEventUpdateNoRows(w, r, eventID, expected)
 			return
 		}
 		s.writeError(w, http.StatusInternalServerError, "failed to update event", err.Error())
 		return
 	}
 
+	setVersionETag(w, row.Version)
 	s.writeJSON(w, http.StatusOK, UpdateEventAutoSimulatedResponse{
 		ID:            uuidString(row.ID),
 		AutoSimulated: row.AutoSimulated,
 		UpdatedAt:     row.UpdatedAt.Time,
+		Version:       row.Version,
+	})
+}
+
+// writeEventUpdateNoRows answers an event update that matched no row: the event is either
+// missing or, when a version was expected, changed since the client read it.
+func (s *Server) writeEventUpdateNoRows(w http.ResponseWriter, r *http.Request, eventID pgtype.UUID, expected *int32) {
+	if expected == nil {
+		s.writeError(w, http.StatusNotFound, "event not found", nil)
+		return
+	}
+	event, err := s.queries.GetEvent(r.Context(), eventID)
+	if err != nil {
+		if isNotFound(err) {
+			s.writeError(w, http.StatusNotFound, "event not found", nil)
+			return
+		}
+		s.writeError(w, http.StatusInternalServerError, "failed to fetch event", err.Error())
+		return
+	}
+	s.writeVersionConflict(w, event.Version, *expected)
+}
+
+// Outcomes of acknowledging one event.
+const (
+	ackOutcomeAcknowledged        = "acknowledged"
+	ackOutcomeNotFound            = "not_found"
+	ackOutcomeClosed              = "closed"
+	ackOutcomeAlreadyAcknowledged = "already_acknowledged"
+	ackOutcomeError               = "error"
+)
+
+// eventAcknowledgement is the result of acknowledgeEvent. For an already acknowledged
+// event AcknowledgedAt/AcknowledgedBy describe the earlier acknowledgement.
+type eventAcknowledgement struct {
+	Outcome        string
+	AcknowledgedAt *time.Time
+	AcknowledgedBy string
+}
+
+// acknowledgeEvent moves an open event to acknowledged in its own transaction and logs it
+// on the event timeline. Events that are missing, closed or already acknowledged are left
+// untouched and reported through the outcome, deleted events as not found; err is only set
+// for database failures.
+func (s *Server) acknowledgeEvent(ctx context.Context, eventID pgtype.UUID, actor *string) (eventAcknowledgement, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return eventAcknowledgement{}, fmt.Errorf("start transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	qtx := s.queries.WithTx(tx)
+
+	acknowledged, err := qtx.AcknowledgeEvent(ctx, db.AcknowledgeEventParams{
+		AcknowledgedBy: actor,
+		ID:             eventID,
 	})
+	if err != nil {
+		if !isNotFound(err) {
+			return eventAcknowledgement{}, fmt.Errorf("acknowledge event: %w", err)
+		}
+		// No row updated: the event is missing, deleted, closed or already acknowledged
+		event, getErr := qtx.GetEvent(ctx, eventID)
+		switch {
+		case isNotFound(getErr):
+			return eventAcknowledgement{Outcome: ackOutcomeNotFound}, nil
+		case getErr != nil:
+			return eventAcknowledgement{}, fmt.Errorf("fetch event: %w", getErr)
+		case event.DeletedAt.Valid:
+			return eventAcknowledgement{Outcome: ackOutcomeNotFound}, nil
+		case event.ClosedAt.Valid:
+			return eventAcknowledgement{Outcome: ackOutcomeClosed}, nil
+		default:
+			return eventAcknowledgement{
+				Outcome:        ackOutcomeAlreadyAcknowledged,
+				AcknowledgedAt: timestamptzPtr(event.AcknowledgedAt),
+				AcknowledgedBy: optionalString(event.AcknowledgedBy),
+			}, nil
+		}
+	}
+
+	entityType := "event"
+	if _, err := qtx.CreateActivityLog(ctx, db.CreateActivityLogParams{
+		ActivityType: "event_acknowledged",
+		EntityType:   &entityType,
+		EntityID:     eventID,
+		Actor:        actor,
+	}); err != nil {
+		return eventAcknowledgement{}, fmt.Errorf("log acknowledgement: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return eventAcknowledgement{}, fmt.Errorf("commit acknowledgement: %w", err)
+	}
+
+	if logErr := s.logEventStatusChange(ctx, s.queries, eventID, "open", "acknowledged", actor); logErr != nil {
+		s.log.Warn().Err(logErr).Msg("failed to log event status change")
+	}
+
+	s.log.Info().
+		Str("event_id", uuidString(eventID)).
+		Str("acknowledged_by", optionalString(actor)).
+		Time("acknowledged_at", acknowledged.AcknowledgedAt.Time).
+		Msg("event acknowledged")
+
+	return eventAcknowledgement{
+		Outcome:        ackOutcomeAcknowledged,
+		AcknowledgedAt: timestamptzPtr(acknowledged.AcknowledgedAt),
+		AcknowledgedBy: optionalString(actor),
+	}, nil
+}
+
+// handleAcknowledgeEvent godoc
+// @Title Acknowledge event
+// @Description Marks an open event as acknowledged, recording who acknowledged it and when, and logs it on the event timeline. Closed or already acknowledged events are rejected; deleted events are reported as not found.
+// @Resource Events
+// @Produce json
+// @Param eventID path string true "Event ID"
+// @Success 200 {object} EventDetailResponse
+// @Failure 400 {object} APIError
+// @Failure 403 {object} APIError
+// @Failure 404 {object} APIError
+// @Failure 409 {object} APIError
+// @Failure 500 {object} APIError
+// @Route /v1/events/{eventID}/acknowledge [post]
+func (s *Server) handleAcknowledgeEvent(w http.ResponseWriter, r *http.Request) {
+	if !s.authMw.RequireOneOfRoles(w, r, "superieur", "it", "manage-events") {
+		return
+	}
+
+	eventID, err := s.parseUUIDParam(r, "eventID")
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, errInvalidEventID, err.Error())
+		return
+	}
+
+	result, err := s.acknowledgeEvent(r.Context(), eventID, actorFromContext(r.Context()))
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to acknowledge event", err.Error())
+		return
+	}
+
+	switch result.Outcome {
+	case ackOutcomeNotFound:
+		s.writeError(w, http.StatusNotFound, "event not found", nil)
+	case ackOutcomeClosed:
+		s.writeError(w, http.StatusConflict, "event is closed", nil)
+	case ackOutcomeAlreadyAcknowledged:
+		s.writeError(w, http.StatusConflict, "event already acknowledged", map[string]interface{}{
+			"acknowledged_at": result.AcknowledgedAt,
+			"acknowledged_by": result.AcknowledgedBy,
+		})
+	default:
+		s.writeEventDetail(w, r, eventID, defaultSRID)
+	}
+}
+
+// BulkAcknowledgeEventsRequest is the request for POST /v1/events/bulk-acknowledge (at most 100 events).
+type BulkAcknowledgeEventsRequest struct {
+	EventIDs []string `json:"event_ids" validate:"required,min=1,max=100,dive,uuid"`
+}
+
+// BulkAcknowledgeEventResult is the outcome for one event of a bulk acknowledgement:
+// acknowledged, not_found, closed, already_acknowledged or error.
+type BulkAcknowledgeEventResult struct {
+	EventID        string     `json:"event_id"`
+	Outcome        string     `json:"outcome"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+	AcknowledgedBy string     `json:"acknowledged_by,omitempty"`
+	Error          string     `json:"error,omitempty"`
+}
+
+// BulkAcknowledgeEventsResponse is the response for POST /v1/events/bulk-acknowledge.
+type BulkAcknowledgeEventsResponse struct {
+	Acknowledged int                          `json:"acknowledged"`
+	Skipped      int                          `json:"skipped"`
+	Results      []BulkAcknowledgeEventResult `json:"results"`
+}
+
+// handleBulkAcknowledgeEvents godoc
+// @Title Bulk acknowledge events
+// @Description Acknowledges up to 100 events at once. Each event is handled independently: open events are acknowledged and logged on their timeline, the others are skipped and reported with their outcome (not_found, closed, already_acknowledged or error).
+// @Resource Events
+// @Accept json
+// @Produce json
+// @Param request body BulkAcknowledgeEventsRequest true "Events to acknowledge"
+// @Success 200 {object} BulkAcknowledgeEventsResponse
+// @Failure 400 {object} APIError
+// @Failure 403 {object} APIError
+// @Route /v1/events/bulk-acknowledge [post]
+func (s *Server) handleBulkAcknowledgeEvents(w http.ResponseWriter, r *http.Request) {
+	if !s.authMw.RequireOneOfRoles(w, r, "superieur", "it", "manage-events") {
+		return
+	}
+
+	ctx := r.Context()
+
+	var req BulkAcknowledgeEventsRequest
+	if err := s.decodeAndValidate(r, &req); err != nil {
+		s.writeError(w, http.StatusBadRequest, errInvalidPayload, err.Error())
+		return
+	}
+	actor := actorFromContext(ctx)
+
+	resp := BulkAcknowledgeEventsResponse{Results: make([]BulkAcknowledgeEventResult, 0, len(req.EventIDs))}
+	seen := make(map[string]struct{}, len(req.EventIDs))
+	for _, rawID := range req.EventIDs {
+		eventID, err := pgUUIDFromString(rawID)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, errInvalidEventID, rawID)
+			return
+		}
+		id := uuidString(eventID)
+		if _, dup := seen[id]; dup {
+			continue
+		}
+		seen[id] = struct{}{}
+
+		item := BulkAcknowledgeEventResult{EventID: id}
+		result, err := s.acknowledgeEvent(ctx, eventID, actor)
+		if err != nil {
+			s.log.Error().Err(err).Str("event_id", id).Msg("failed to acknowledge event in bulk")
+			item.Outcome = ackOutcomeError
+			item.Error = err.Error()
+		} else {
+			item.Outcome = result.Outcome
+			item.AcknowledgedAt = result.AcknowledgedAt
+			item.AcknowledgedBy = result.AcknowledgedBy
+		}
+
+		if item.Outcome == ackOutcomeAcknowledged {
+			resp.Acknowledged++
+		} else {
+			resp.Skipped++
+			s.log.Info().Str("event_id", id).Str("outcome", item.Outcome).Msg("skipped event in bulk acknowledgement")
+		}
+		resp.Results = append(resp.Results, item)
+	}
+
+	s.writeJSON(w, http.StatusOK, resp)
 }
@@ -0,0 +1,270 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	db "fast/pin/internal/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// maxExportLogs bounds the activity logs included in an intervention export.
+const maxExportLogs = 10000
+
+// InterventionExportTimelineEntry is one entry of the merged export timeline: either
+// an activity log or an assignment milestone (dispatched, arrived, released).
+type InterventionExportTimelineEntry struct {
+	At         time.Time `json:"at"`
+	Source     string    `json:"source"`
+	Code       string    `json:"code"`
+	EntityType string    `json:"entity_type,omitempty"`
+	EntityID   string    `json:"entity_id,omitempty"`
+	Actor      string    `json:"actor,omitempty"`
+	OldValue   string    `json:"old_value,omitempty"`
+	NewValue   string    `json:"new_value,omitempty"`
+	Payload    RawJSON   `json:"payload,omitempty"`
+}
+
+// InterventionExportResponse is the self-contained record returned by GET /v1/interventions/{interventionID}/export
+type InterventionExportResponse struct {
+	ExportedAt     time.Time              `json:"exported_at"`
+	ExportedBy     string                 `json:"exported_by,omitempty"`
+	InterventionID string                 `json:"intervention_id"`
+	Event          EventSummaryResponse   `json:"event"`
+	Interventions  []InterventionResponse `json:"interventions"`
+	// Routes are the stored routes of assigned units that still point to one of the event's interventions.
+	Routes   []UnitRouteResponse               `json:"routes"`
+	Timeline []InterventionExportTimelineEntry `json:"timeline"`
+}
+
+// exportErrors collects the first error of concurrently running export queries.
+type exportErrors struct {
+	mu    sync.Mutex
+	stage string
+	err   error
+}
+
+func (e *exportErrors) set(stage string, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.err == nil {
+		e.stage, e.err = stage, err
+	}
+}
+
+// handleExportIntervention godoc
+// @Title Export intervention
+// @Description Returns a self-contained record of an intervention for archival: its event, every intervention on that event with assignments and timings, stored routes and the merged timeline.
+// @Resource Interventions
+// @Produce json
+// @Param interventionID path string true "Intervention ID"
+// @Success 200 {object} InterventionExportResponse
+// @Failure 400 {object} APIError
+// @Failure 403 {object} APIError
+// @Failure 404 {object} APIError
+// @Failure 500 {object} APIError
+// @Route /v1/interventions/{interventionID}/export [get]
+func (s *Server) handleExportIntervention(w http.ResponseWriter, r *http.Request) {
+	if !s.authMw.RequireOneOfRoles(w, r, RoleSuperieur) {
+		return
+	}
+
+	interventionID, err := s.parseUUIDParam(r, "interventionID")
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, errInvalidInterventionID, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	intervention, err := s.queries.GetIntervention(ctx, interventionID)
+	if err != nil {
+		if isNotFound(err) {
+			s.writeError(w, http.StatusNotFound, "intervention not found", nil)
+			return
+		}
+		s.writeError(w, http.StatusInternalServerError, "failed to fetch intervention", err.Error())
+		return
+	}
+
+	// 1. Event, its interventions and its activity logs
+	var (
+		event         db.GetEventRow
+		interventions []db.Intervention
+		logs          []db.ActivityLog
+		errs          exportErrors
+		wg            sync.WaitGroup
+	)
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		row, err := s.queries.GetEvent(ctx, intervention.EventID)
+		if err != nil {
+			errs.set("event", err)
+			return
+		}
+		event = row
+	}()
+	go func() {
+		defer wg.Done()
+		rows, err := s.queries.ListInterventionsByEvent(ctx, intervention.EventID)
+		if err != nil {
+			errs.set("interventions", err)
+			return
+		}
+		interventions = rows
+	}()
+	go func() {
+		defer wg.Done()
+		rows, err := s.queries.ListActivityLogsForEvent(ctx, db.ListActivityLogsForEventParams{
+			EventID: intervention.EventID,
+			Limit:   maxExportLogs,
+			Offset:  0,
+		})
+		if err != nil {
+			errs.set("logs", err)
+			return
+		}
+		logs = rows
+	}()
+	wg.Wait()
+	if errs.err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to export "+errs.stage, errs.err.Error())
+		return
+	}
+
+	// 2. Assignments of every intervention on the event
+	assignments := make([][]db.ListAssignmentsByInterventionRow, len(interventions))
+	for i, iv := range interventions {
+		wg.Add(1)
+		go func(i int, id pgtype.UUID) {
+			defer wg.Done()
+			rows, err := s.queries.ListAssignmentsByIntervention(ctx, id)
+			if err != nil {
+				errs.set("assignments", err)
+				return
+			}
+			assignments[i] = rows
+		}(i, iv.ID)
+	}
+	wg.Wait()
+	if errs.err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to export "+errs.stage, errs.err.Error())
+		return
+	}
+
+	// 3. Stored routes of the assigned units
+	interventionIDs := make(map[string]struct{}, len(interventions))
+	unitIDs := make(map[string]pgtype.UUID)
+	for i, iv := range interventions {
+		interventionIDs[uuidString(iv.ID)] = struct{}{}
+		for _, a := range assignments[i] {
+			unitIDs[uuidString(a.UnitID)] = a.UnitID
+		}
+	}
+	routes := s.exportUnitRoutes(ctx, unitIDs, interventionIDs)
+
+	resp := InterventionExportResponse{
+		ExportedAt:     time.Now().UTC(),
+		ExportedBy:     optionalString(actorFromContext(ctx)),
+		InterventionID: uuidString(interventionID),
+		Event:          mapEventDetail(event, nil, nil).EventSummaryResponse,
+		Interventions:  make([]InterventionResponse, 0, len(interventions)),
+		Routes:         routes,
+		Timeline:       make([]InterventionExportTimelineEntry, 0, len(logs)),
+	}
+
+	for i, iv := range interventions {
+		mapped := mapIntervention(iv)
+		for _, a := range assignments[i] {
+			mapped.Assignments = append(mapped.Assignments, mapAssignmentRow(a))
+			resp.Timeline = append(resp.Timeline, assignmentMilestones(a)...)
+		}
+		resp.Interventions = append(resp.Interventions, mapped)
+	}
+
+	for _, l := range logs {
+		entry := InterventionExportTimelineEntry{
+			At:         l.CreatedAt.Time,
+			Source:     "activity_log",
+			Code:       l.ActivityType,
+			EntityType: optionalString(l.EntityType),
+			EntityID:   uuidStringOptional(l.EntityID),
+			Actor:      optionalString(l.Actor),
+			OldValue:   optionalString(l.OldValue),
+			NewValue:   optionalString(l.NewValue),
+		}
+		if len(l.Metadata) > 0 {
+			entry.Payload = RawJSON(l.Metadata)
+		}
+		resp.Timeline = append(resp.Timeline, entry)
+	}
+	sort.SliceStable(resp.Timeline, func(i, j int) bool { return resp.Timeline[i].At.Before(resp.Timeline[j].At) })
+
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
+// exportUnitRoutes fetches the stored routes of the given units concurrently, keeping
+// only those that belong to one of the exported interventions.
+func (s *Server) exportUnitRoutes(ctx context.Context, unitIDs map[string]pgtype.UUID, interventionIDs map[string]struct{}) []UnitRouteResponse {
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		routes = make([]UnitRouteResponse, 0)
+	)
+	for _, unitID := range unitIDs {
+		wg.Add(1)
+		go func(unitID pgtype.UUID) {
+			defer wg.Done()
+			route, err := s.queries.GetUnitRoute(ctx, unitID)
+			if err != nil {
+				if !isNotFound(err) {
+					s.log.Warn().Err(err).Str("unit_id", uuidString(unitID)).Msg("failed to fetch route for export")
+				}
+				return
+			}
+			if _, ok := interventionIDs[uuidString(route.InterventionID)]; !ok {
+				return
+			}
+			mapped := mapUnitRoute(route)
+			mu.Lock()
+			routes = append(routes, mapped)
+			mu.Unlock()
+		}(unitID)
+	}
+	wg.Wait()
+
+	sort.Slice(routes, func(i, j int) bool { return routes[i].UnitID < routes[j].UnitID })
+	return routes
+}
+
+// assignmentMilestones turns the timings of an assignment into timeline entries.
+func assignmentMilestones(a db.ListAssignmentsByInterventionRow) []InterventionExportTimelineEntry {
+	milestones := []struct {
+		code string
+		at   pgtype.Timestamptz
+	}{
+		{"assignment_dispatched", a.DispatchedAt},
+		{"assignment_arrived", a.ArrivedAt},
+		{"assignment_released", a.ReleasedAt},
+	}
+
+	entries := make([]InterventionExportTimelineEntry, 0, len(milestones))
+	for _, m := range milestones {
+		if !m.at.Valid {
+			continue
+		}
+		entries = append(entries, InterventionExportTimelineEntry{
+			At:         m.at.Time,
+			Source:     "assignment",
+			Code:       m.code,
+			EntityType: "assignment",
+			EntityID:   uuidString(a.ID),
+			NewValue:   a.CallSign,
+		})
+	}
+	return entries
+}
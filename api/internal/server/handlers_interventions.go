@@ -2,7 +2,12 @@ package server
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
 
@@ -34,13 +39,15 @@ type UpdateAssignmentStatusRequest struct {
 
 // handleCreateIntervention godoc
 // @Title Create intervention
-// @Description Starts a new intervention linked to an event.
+// @Description Starts a new intervention linked to an event. Without decision_mode, engine requests default to auto_suggested and others to manual. Without priority, it is derived from the event severity via INTERVENTION_SEVERITY_PRIORITIES. Returns 409 once the event holds the configured maximum of non-cancelled interventions, unless the caller has the superieur role.
 // @Resource Interventions
 // @Accept json
 // @Produce json
 // @Param request body CreateInterventionRequest true "Intervention payload"
 // @Success 201 {object} InterventionResponse
 // @Failure 400 {object} APIError
+// @Failure 404 {object} APIError
+// @Failure 409 {object} APIError
 // @Failure 500 {object} APIError
 // @Route /v1/interventions [post]
 func (s *Server) handleCreateIntervention(w http.ResponseWriter, r *http.Request) {
@@ -58,7 +65,16 @@ func (s *Server) handleCreateIntervention(w http.ResponseWriter, r *http.Request
 
 	priority := req.Priority
 	if priority == 0 {
-		priority = 3
+		event, err := s.queries.GetEvent(r.Context(), eventID)
+		if err != nil {
+			if isNotFound(err) {
+				s.writeError(w, http.StatusNotFound, "event not found", nil)
+				return
+			}
+			s.writeError(w, http.StatusInternalServerError, "failed to fetch event", err.Error())
+			return
+		}
+		priority = s.cfg.Intervention.SeverityPriorities[event.Severity]
 	}
 
 	params := db.CreateInterventionParams{
@@ -73,14 +89,51 @@ func (s *Server) handleCreateIntervention(w http.ResponseWriter, r *http.Request
 		params.Status = db.InterventionStatusCreated
 	}
 	if params.DecisionMode == "" {
-		params.DecisionMode = db.DecisionModeManual
+		params.DecisionMode = s.defaultDecisionMode(r)
 	}
 
-	row, err := s.queries.CreateIntervention(r.Context(), params)
+	ctx := r.Context()
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to start transaction", err.Error())
+		return
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	qtx := s.queries.WithTx(tx)
+
+	if limit := s.cfg.Intervention.MaxPerEvent; limit > 0 && !s.canExceedInterventionCap(r) {
+		// Lock the event so concurrent creations (e.g. engine retries) cannot all pass the check
+		if _, err := qtx.LockEvent(ctx, eventID); err != nil {
+			if isNotFound(err) {
+				s.writeError(w, http.StatusNotFound, "event not found", nil)
+				return
+			}
+			s.writeError(w, http.StatusInternalServerError, "failed to lock event", err.Error())
+			return
+		}
+		existing, err := qtx.CountOpenInterventionsForEvent(ctx, eventID)
+		if err != nil {
+			s.writeError(w, http.StatusInternalServerError, "failed to count interventions", err.Error())
+			return
+		}
+		if existing >= limit {
+			s.writeError(w, http.StatusConflict, "too many interventions for this event", map[string]int64{
+				"existing": existing,
+				"max":      limit,
+			})
+			return
+		}
+	}
+
+	row, err := qtx.CreateIntervention(ctx, params)
 	if err != nil {
 		s.writeError(w, http.StatusInternalServerError, "failed to create intervention", err.Error())
 		return
 	}
+	if err := tx.Commit(ctx); err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to commit intervention", err.Error())
+		return
+	}
 
 	// Trigger engine if in auto_suggested mode
 	if params.DecisionMode == db.DecisionModeAutoSuggested {
@@ -93,12 +146,270 @@ func (s *Server) handleCreateIntervention(w http.ResponseWriter, r *http.Request
 	s.writeJSON(w, http.StatusCreated, mapIntervention(row))
 }
 
+// validateSeverityPriorities checks that every event severity maps to a valid intervention priority.
+func validateSeverityPriorities(mapping map[int32]int32) error {
+	for severity := int32(1); severity <= 5; severity++ {
+		priority, ok := mapping[severity]
+		if !ok {
+			return fmt.Errorf("severity %d has no priority", severity)
+		}
+		if priority < 1 || priority > 5 {
+			return fmt.Errorf("severity %d maps to priority %d, must be between 1 and 5", severity, priority)
+		}
+	}
+	for severity := range mapping {
+		if severity < 1 || severity > 5 {
+			return fmt.Errorf("unknown severity %d, must be between 1 and 5", severity)
+		}
+	}
+	return nil
+}
+
+// interventionTransition is a "from:to" intervention status change.
+type interventionTransition struct {
+	From db.InterventionStatus
+	To   db.InterventionStatus
+}
+
+// parseInterventionTransitions parses "from:to" entries into a lookup set.
+func parseInterventionTransitions(entries []string) (map[interventionTransition]struct{}, error) {
+	transitions := make(map[interventionTransition]struct{}, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		from, to, found := strings.Cut(entry, ":")
+		if !found {
+			return nil, fmt.Errorf("entry %q must be from:to", entry)
+		}
+		t := interventionTransition{
+			From: db.InterventionStatus(strings.TrimSpace(from)),
+			To:   db.InterventionStatus(strings.TrimSpace(to)),
+		}
+		if !isKnownInterventionStatus(t.From) || !isKnownInterventionStatus(t.To) {
+			return nil, fmt.Errorf("entry %q: unknown intervention status", entry)
+		}
+		transitions[t] = struct{}{}
+	}
+	return transitions, nil
+}
+
+// checkInterventionTransition refuses status changes outside INTERVENTION_STATUS_TRANSITIONS
+// unless the caller has the superieur role. Keeping the current status is always allowed.
+// It writes the error response and returns false when the change is refused.
+func (s *Server) checkInterventionTransition(w http.ResponseWriter, r *http.Request, from, to db.InterventionStatus) bool {
+	if from == to || len(s.interventionTransitions) == 0 {
+		return true
+	}
+	if _, ok := s.interventionTransitions[interventionTransition{From: from, To: to}]; ok {
+		return true
+	}
+	if claims, ok := GetUserFromContext(r.Context()); ok && s.authMw.hasRole(claims, RoleSuperieur) {
+		s.log.Info().Str("from", string(from)).Str("to", string(to)).Msg("intervention status transition forced by superieur")
+		return true
+	}
+	s.writeError(w, http.StatusConflict, "intervention status transition not allowed", map[string]string{
+		"from": string(from),
+		"to":   string(to),
+	})
+	return false
+}
+
+// canExceedInterventionCap reports whether the caller may create interventions beyond the per-event cap.
+func (s *Server) canExceedInterventionCap(r *http.Request) bool {
+	claims, ok := GetUserFromContext(r.Context())
+	return ok && s.authMw.hasRole(claims, RoleSuperieur)
+}
+
+// requestSourceHeader lets the engine identify itself when it has no dedicated service account.
+const requestSourceHeader = "X-Request-Source"
+
+// defaultDecisionMode returns auto_suggested for requests issued by the dispatch engine
+// (service account token or source header) and manual for everyone else.
+func (s *Server) defaultDecisionMode(r *http.Request) db.DecisionMode {
+	if s.cfg.Intervention.TrustSourceHeader && strings.EqualFold(r.Header.Get(requestSourceHeader), "engine") {
+		return db.DecisionModeAutoSuggested
+	}
+	if claims, ok := GetUserFromContext(r.Context()); ok {
+		for _, clientID := range s.cfg.Intervention.EngineClientIDs {
+			if claims.AuthorizedParty == clientID || claims.PreferredUsername == "service-account-"+clientID {
+				return db.DecisionModeAutoSuggested
+			}
+		}
+	}
+	return db.DecisionModeManual
+}
+
+// handleListInterventions godoc
+// @Title List interventions
+// @Description Returns interventions created in [created_from, created_to), oldest first, with their event context. Both bounds are optional RFC3339 timestamps. Filters combine with status and priority.
+// @Resource Interventions
+// @Produce json
+// @Param created_from query string false "Only interventions created at or after this time (RFC3339)"
+// @Param created_to query string false "Only interventions created before this time (RFC3339)"
+// @Param status query string false "Filter by intervention status"
+// @Param priority query int false "Filter by priority"
+// @Param limit query int false "Maximum results" default(100)
+// @Param offset query int false "Results offset" default(0)
+// @Success 200 {array} InterventionListItemResponse
+// @Failure 400 {object} APIError
+// @Failure 500 {object} APIError
+// @Route /v1/interventions [get]
+func (s *Server) handleListInterventions(w http.ResponseWriter, r *http.Request) {
+	createdFrom, createdTo, ok := s.parseTimeRange(w, r, "created_from", "created_to", 0)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	limit, offset := s.paginate(r, 100)
+	params := db.ListInterventionsCreatedBetweenParams{
+		CreatedFrom: pgtype.Timestamptz{Time: createdFrom, Valid: !createdFrom.IsZero()},
+		CreatedTo:   pgtype.Timestamptz{Time: createdTo, Valid: !createdTo.IsZero()},
+		Limit:       limit,
+		Offset:      offset,
+	}
+
+	if raw := query.Get("status"); raw != "" {
+		status := db.InterventionStatus(raw)
+		if !isKnownInterventionStatus(status) {
+			s.writeError(w, http.StatusBadRequest, "invalid status", raw)
+			return
+		}
+		params.Status = db.NullInterventionStatus{InterventionStatus: status, Valid: true}
+	}
+	if raw := query.Get("priority"); raw != "" {
+		priority, err := parseInt32(raw)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, "invalid priority", err.Error())
+			return
+		}
+		params.Priority = &priority
+	}
+
+	rows, err := s.queries.ListInterventionsCreatedBetween(r.Context(), params)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to list interventions", err.Error())
+		return
+	}
+
+	resp := make([]InterventionListItemResponse, 0, len(rows))
+	for _, row := range rows {
+		resp = append(resp, InterventionListItemResponse{
+			InterventionResponse: mapIntervention(db.Intervention{
+				ID:           row.ID,
+				EventID:      row.EventID,
+				Status:       row.Status,
+				Priority:     row.Priority,
+				DecisionMode: row.DecisionMode,
+				CreatedBy:    row.CreatedBy,
+				Notes:        row.Notes,
+				CreatedAt:    row.CreatedAt,
+				StartedAt:    row.StartedAt,
+				CompletedAt:  row.CompletedAt,
+			}),
+			EventTitle:    row.EventTitle,
+			EventTypeCode: row.EventTypeCode,
+			EventSeverity: row.EventSeverity,
+			EventAddress:  row.EventAddress,
+		})
+	}
+
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
+// Bounds of the /v1/interventions/overlaps search.
+const (
+	defaultOverlapRadiusMeters = 200
+	maxOverlapRadiusMeters     = 5000
+	defaultOverlapWindow       = 30 * time.Minute
+	maxOverlapWindow           = 24 * time.Hour
+)
+
+// handleListInterventionOverlaps godoc
+// @Title List overlapping interventions
+// @Description Returns pairs of active interventions whose events lie within radius meters and window of each other. Such pairs likely describe the same incident and are candidates for merging. Closest pairs come first.
+// @Resource Interventions
+// @Produce json
+// @Param radius query number false "Maximum distance between the events in meters" default(200)
+// @Param window query string false "Maximum time between the event reports, as a Go duration" default(30m)
+// @Param limit query int false "Maximum results" default(50)
+// @Success 200 {array} InterventionOverlapResponse
+// @Failure 400 {object} APIError
+// @Failure 500 {object} APIError
+// @Route /v1/interventions/overlaps [get]
+func (s *Server) handleListInterventionOverlaps(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	radius := float64(defaultOverlapRadiusMeters)
+	if raw := query.Get("radius"); raw != "" {
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil || parsed <= 0 || parsed > maxOverlapRadiusMeters {
+			s.writeError(w, http.StatusBadRequest, "invalid radius", fmt.Sprintf("radius must be a number of meters in (0, %d]", maxOverlapRadiusMeters))
+			return
+		}
+		radius = parsed
+	}
+
+	window := defaultOverlapWindow
+	if raw := query.Get("window"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil || parsed <= 0 || parsed > maxOverlapWindow {
+			s.writeError(w, http.StatusBadRequest, "invalid window", fmt.Sprintf("window must be a positive duration of at most %s", maxOverlapWindow))
+			return
+		}
+		window = parsed
+	}
+
+	limit, _ := s.paginate(r, 50)
+
+	rows, err := s.queries.ListOverlappingInterventions(r.Context(), db.ListOverlappingInterventionsParams{
+		RadiusMeters: radius,
+		Window:       pgtype.Interval{Microseconds: window.Microseconds(), Valid: true},
+		Limit:        limit,
+	})
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to list overlapping interventions", err.Error())
+		return
+	}
+
+	resp := make([]InterventionOverlapResponse, 0, len(rows))
+	for _, row := range rows {
+		resp = append(resp, InterventionOverlapResponse{
+			First: OverlapSide{
+				InterventionID:     uuidString(row.InterventionID),
+				InterventionStatus: string(row.InterventionStatus),
+				EventID:            uuidString(row.EventID),
+				EventTitle:         row.EventTitle,
+				EventTypeCode:      row.EventTypeCode,
+				EventSeverity:      row.EventSeverity,
+				EventReportedAt:    row.EventReportedAt.Time,
+			},
+			Second: OverlapSide{
+				InterventionID:     uuidString(row.OtherInterventionID),
+				InterventionStatus: string(row.OtherInterventionStatus),
+				EventID:            uuidString(row.OtherEventID),
+				EventTitle:         row.OtherEventTitle,
+				EventTypeCode:      row.OtherEventTypeCode,
+				EventSeverity:      row.OtherEventSeverity,
+				EventReportedAt:    row.OtherEventReportedAt.Time,
+			},
+			DistanceMeters: row.DistanceMeters,
+			TimeGapSeconds: row.TimeGapSeconds,
+		})
+	}
+
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
 // handleGetIntervention godoc
 // @Title Get intervention
 // @Description Returns detailed information about a specific intervention.
 // @Resource Interventions
 // @Produce json
 // @Param interventionID path string true "Intervention ID"
+// @Param active_only query bool false "Only return assignments that have not been released" default(false)
 // @Success 200 {object} InterventionResponse
 // @Failure 400 {object} APIError
 // @Failure 404 {object} APIError
@@ -127,8 +438,13 @@ func (s *Server) handleGetIntervention(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	activeOnly := r.URL.Query().Get("active_only") == "true"
+
 	resp := mapIntervention(row)
 	for _, a := range assignments {
+		if activeOnly && a.ReleasedAt.Valid {
+			continue
+		}
 		resp.Assignments = append(resp.Assignments, mapAssignmentRow(a))
 	}
 
@@ -137,7 +453,7 @@ func (s *Server) handleGetIntervention(w http.ResponseWriter, r *http.Request) {
 
 // handleUpdateInterventionStatus godoc
 // @Title Update intervention status
-// @Description Updates the operational status of an intervention.
+// @Description Updates the operational status of an intervention. Only the changes listed in INTERVENTION_STATUS_TRANSITIONS are accepted, unless the caller has the superieur role. Completing or cancelling it releases its active assignments, sets their units available and removes their routes in the same transaction.
 // @Resource Interventions
 // @Accept json
 // @Produce json
@@ -146,6 +462,7 @@ func (s *Server) handleGetIntervention(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} InterventionResponse
 // @Failure 400 {object} APIError
 // @Failure 404 {object} APIError
+// @Failure 409 {object} APIError
 // @Failure 500 {object} APIError
 // @Route /v1/interventions/{interventionID}/status [patch]
 func (s *Server) handleUpdateInterventionStatus(w http.ResponseWriter, r *http.Request) {
@@ -161,13 +478,28 @@ func (s *Server) handleUpdateInterventionStatus(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	// Fetch current intervention to get old status and event context for logging
-	currentIntervention, err := s.queries.GetIntervention(r.Context(), interventionID)
+	ctx := r.Context()
+	tx, err := s.pool.Begin(ctx)
 	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to start transaction", err.Error())
+		return
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	qtx := s.queries.WithTx(tx)
+
+	// Lock the intervention so concurrent updates cannot both release its units
+	if _, err := qtx.LockIntervention(ctx, interventionID); err != nil {
 		if isNotFound(err) {
 			s.writeError(w, http.StatusNotFound, "intervention not found", nil)
 			return
 		}
+		s.writeError(w, http.StatusInternalServerError, "failed to lock intervention", err.Error())
+		return
+	}
+
+	// Fetch current intervention to get old status and event context for logging
+	currentIntervention, err := qtx.GetIntervention(ctx, interventionID)
+	if err != nil {
 		s.writeError(w, http.StatusInternalServerError, "failed to fetch intervention", err.Error())
 		return
 	}
@@ -175,84 +507,211 @@ func (s *Server) handleUpdateInterventionStatus(w http.ResponseWriter, r *http.R
 	oldStatus := string(currentIntervention.Status)
 	newStatus := req.Status
 
-	row, err := s.queries.UpdateInterventionStatus(r.Context(), db.UpdateInterventionStatusParams{
+	if !s.checkInterventionTransition(w, r, currentIntervention.Status, db.InterventionStatus(newStatus)) {
+		return
+	}
+
+	row, err := qtx.UpdateInterventionStatus(ctx, db.UpdateInterventionStatusParams{
 		ID:      interventionID,
 		Column2: db.InterventionStatus(newStatus),
 	})
 	if err != nil {
-		if isNotFound(err) {
-			s.writeError(w, http.StatusNotFound, "intervention not found", nil)
+		s.writeError(w, http.StatusInternalServerError, "failed to update intervention", err.Error())
+		return
+	}
+
+	// When an intervention ends, release its active assignments and set their units available.
+	// Re-sending the same final status is a no-op.
+	var released []releasedAssignment
+	ended := newStatus == string(db.InterventionStatusCompleted) || newStatus == string(db.InterventionStatusCancelled)
+	if ended && oldStatus != newStatus {
+		released, err = s.releaseInterventionUnits(ctx, qtx, interventionID)
+		if err != nil {
+			s.writeError(w, http.StatusInternalServerError, "failed to release assignments", err.Error())
 			return
 		}
-		s.writeError(w, http.StatusInternalServerError, "failed to update intervention", err.Error())
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to commit intervention status", err.Error())
 		return
 	}
 
 	// Log the status change if it actually changed
 	if oldStatus != newStatus {
-		if logErr := s.logInterventionStatusChange(r.Context(), interventionID, currentIntervention.EventID, oldStatus, newStatus, nil); logErr != nil {
+		if logErr := s.logInterventionStatusChange(ctx, interventionID, currentIntervention.EventID, oldStatus, newStatus, actorFromContext(ctx)); logErr != nil {
 			s.log.Error().Err(logErr).Msg("failed to log intervention status change")
 			// Don't fail the request if logging fails
 		}
 	}
 
-	// When an intervention is completed, release all assignments and set units available.
-	if req.Status == string(db.InterventionStatusCompleted) {
-		s.log.Info().Str("intervention_id", uuidString(interventionID)).Msg("intervention completed, releasing all assigned units")
-		if err := s.releaseInterventionUnits(r.Context(), interventionID); err != nil {
-			s.writeError(w, http.StatusInternalServerError, "failed to release assignments", err.Error())
-			return
+	if len(released) > 0 {
+		s.invalidateStaticData()
+		s.log.Info().Str("intervention_id", uuidString(interventionID)).Int("released_units", len(released)).Str("status", newStatus).Msg("intervention ended, released assigned units")
+	}
+	for _, a := range released {
+		if logErr := s.logUnitStatusChange(ctx, a.unitID, a.callSign, a.oldStatus, string(db.UnitStatusAvailable), actorFromContext(ctx)); logErr != nil {
+			s.log.Error().Err(logErr).Msg("failed to log unit status change")
 		}
+		s.logAssignmentChange(ctx, eventLogUnitReleased, a.assignmentID, actorFromContext(ctx))
+		s.observeAssignmentOnSite(ctx, a.assignmentID)
+
+		// Trigger return to station routing
+		go s.calculateAndSaveRouteToStation(context.Background(), a.unitID)
+	}
 
-		s.observeEventResolution(r.Context(), interventionID, row.CompletedAt)
+	if newStatus == string(db.InterventionStatusCompleted) && oldStatus != newStatus {
+		s.observeEventResolution(ctx, interventionID, row.CompletedAt)
 	}
 
 	s.writeJSON(w, http.StatusOK, mapIntervention(row))
 }
 
-func (s *Server) releaseInterventionUnits(ctx context.Context, interventionID pgtype.UUID) error {
-	assignments, err := s.queries.ListAssignmentsByIntervention(ctx, interventionID)
+// releasedAssignment is an assignment released by releaseInterventionUnits, kept for
+// the logging and routing done once the transaction is committed.
+type releasedAssignment struct {
+	assignmentID pgtype.UUID
+	unitID       pgtype.UUID
+	callSign     string
+	oldStatus    string
+}
+
+// releaseInterventionUnits releases the active assignments of an intervention, sets their
+// units available through changeUnitStatus and removes their intervention routes, using q so the caller's
+// transaction covers all of it. Assignments already released or cancelled are left alone.
+func (s *Server) releaseInterventionUnits(ctx context.Context, q *db.Queries, interventionID pgtype.UUID) ([]releasedAssignment, error) {
+	assignments, err := q.ListAssignmentsByIntervention(ctx, interventionID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	s.log.Info().Str("intervention_id", uuidString(interventionID)).Int("assignment_count", len(assignments)).Msg("releasing intervention units")
-
+	var released []releasedAssignment
 	for _, a := range assignments {
-		if a.Status != db.AssignmentStatusReleased {
-			if _, err := s.queries.UpdateAssignmentStatus(ctx, db.UpdateAssignmentStatusParams{
-				ID:      a.ID,
-				Column2: db.AssignmentStatusReleased,
-			}); err != nil {
-				return err
-			}
-			s.log.Debug().Str("assignment_id", uuidString(a.ID)).Str("unit_id", uuidString(a.UnitID)).Msg("assignment released")
+		if a.ReleasedAt.Valid || a.Status == db.AssignmentStatusReleased || a.Status == db.AssignmentStatusCancelled {
+			continue
 		}
-
-		// Fetch unit for logging
-		unit, err := s.queries.GetUnit(ctx, a.UnitID)
+		if _, err := q.UpdateAssignmentStatus(ctx, db.UpdateAssignmentStatusParams{
+			ID:      a.ID,
+			Column2: db.AssignmentStatusReleased,
+		}); err != nil {
+			return nil, err
+		}
+		unit, _, err := changeUnitStatus(ctx, q, a.UnitID, db.UnitStatusAvailable, nil)
 		if err != nil {
-			s.log.Error().Err(err).Str("unit_id", uuidString(a.UnitID)).Msg("failed to fetch unit for logging")
+			return nil, err
 		}
-
-		if _, err := s.queries.UpdateUnitStatus(ctx, db.UpdateUnitStatusParams{
-			ID:     a.UnitID,
-			Status: db.UnitStatusAvailable,
-		}); err != nil {
-			return err
+		if s.cfg.Routing.ArchiveRoutes {
+			if _, err := q.ArchiveUnitRoute(ctx, a.UnitID); err != nil {
+				return nil, err
+			}
 		}
-
-		if unit.CallSign != "" {
-			s.logUnitStatusChange(ctx, a.UnitID, unit.CallSign, string(unit.Status), string(db.UnitStatusAvailable), nil)
+		if err := q.DeleteUnitRoute(ctx, a.UnitID); err != nil {
+			return nil, err
 		}
+		released = append(released, releasedAssignment{
+			assignmentID: a.ID,
+			unitID:       a.UnitID,
+			callSign:     unit.CallSign,
+			oldStatus:    string(unit.Status),
+		})
+	}
+	return released, nil
+}
 
-		s.observeAssignmentOnSite(ctx, a.ID)
-		s.log.Debug().Str("unit_id", uuidString(a.UnitID)).Msg("unit status set to available")
+// startInterventionOnArrival moves a created intervention to on_site once one of its units
+// arrives, so that it can then be completed by hand or when its last unit is released.
+func (s *Server) startInterventionOnArrival(ctx context.Context, interventionID pgtype.UUID, actor *string) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error().Err(err).Str("intervention_id", uuidString(interventionID)).Msg("failed to start transaction for intervention arrival")
+		return
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	qtx := s.queries.WithTx(tx)
 
-		// Trigger return to station routing
-		go s.calculateAndSaveRouteToStation(context.Background(), a.UnitID)
+	status, err := qtx.LockIntervention(ctx, interventionID)
+	if err != nil {
+		s.log.Error().Err(err).Str("intervention_id", uuidString(interventionID)).Msg("failed to lock intervention for arrival")
+		return
+	}
+	if status != db.InterventionStatusCreated {
+		return
+	}
+
+	row, err := qtx.UpdateInterventionStatus(ctx, db.UpdateInterventionStatusParams{
+		ID:      interventionID,
+		Column2: db.InterventionStatusOnSite,
+	})
+	if err != nil {
+		s.log.Error().Err(err).Str("intervention_id", uuidString(interventionID)).Msg("failed to move intervention on site")
+		return
+	}
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error().Err(err).Str("intervention_id", uuidString(interventionID)).Msg("failed to commit intervention arrival")
+		return
+	}
+
+	if logErr := s.logInterventionStatusChange(ctx, interventionID, row.EventID, string(status), string(db.InterventionStatusOnSite), actor); logErr != nil {
+		s.log.Error().Err(logErr).Msg("failed to log intervention status change")
 	}
-	return nil
+}
+
+// autoCompleteActor is the actor recorded when an intervention is completed on its last release.
+var autoCompleteActor = "system:auto-complete"
+
+// autoCompleteInterventionIfIdle completes an on_site intervention once its last assignment is
+// released. The intervention is locked and the check repeated inside the transaction so a
+// concurrent assignment or status change cannot be overwritten.
+func (s *Server) autoCompleteInterventionIfIdle(ctx context.Context, interventionID pgtype.UUID) {
+	if !s.cfg.Intervention.AutoCompleteOnRelease {
+		return
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error().Err(err).Str("intervention_id", uuidString(interventionID)).Msg("failed to start transaction for auto-completion")
+		return
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	qtx := s.queries.WithTx(tx)
+
+	status, err := qtx.LockIntervention(ctx, interventionID)
+	if err != nil {
+		s.log.Error().Err(err).Str("intervention_id", uuidString(interventionID)).Msg("failed to lock intervention for auto-completion")
+		return
+	}
+	if status != db.InterventionStatusOnSite {
+		return
+	}
+
+	active, err := qtx.CountActiveAssignments(ctx, interventionID)
+	if err != nil {
+		s.log.Error().Err(err).Str("intervention_id", uuidString(interventionID)).Msg("failed to count active assignments")
+		return
+	}
+	if active > 0 {
+		return
+	}
+
+	row, err := qtx.UpdateInterventionStatus(ctx, db.UpdateInterventionStatusParams{
+		ID:      interventionID,
+		Column2: db.InterventionStatusCompleted,
+	})
+	if err != nil {
+		s.log.Error().Err(err).Str("intervention_id", uuidString(interventionID)).Msg("failed to auto-complete intervention")
+		return
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error().Err(err).Str("intervention_id", uuidString(interventionID)).Msg("failed to commit intervention auto-completion")
+		return
+	}
+
+	s.log.Info().Str("intervention_id", uuidString(interventionID)).Msg("last assignment released, intervention auto-completed")
+	if logErr := s.logInterventionStatusChange(ctx, interventionID, row.EventID, string(status), string(db.InterventionStatusCompleted), &autoCompleteActor); logErr != nil {
+		s.log.Error().Err(logErr).Msg("failed to log intervention status change")
+	}
+	s.observeEventResolution(ctx, interventionID, row.CompletedAt)
 }
 
 // handleListInterventionsForEvent godoc
@@ -288,14 +747,17 @@ func (s *Server) handleListInterventionsForEvent(w http.ResponseWriter, r *http.
 
 // handleCreateAssignment godoc
 // @Title Create assignment
-// @Description Assigns a unit to an intervention.
+// @Description Assigns a unit to an intervention. Roles listed in INTERVENTION_SINGLETON_ROLES may only be held by one active assignment. A unit that already has an active assignment is refused unless force=true.
 // @Resource Interventions
 // @Accept json
 // @Produce json
 // @Param interventionID path string true "Intervention ID"
+// @Param force query bool false "Assign even if the unit is active on another assignment"
 // @Param request body CreateAssignmentRequest true "Assignment payload"
 // @Success 201 {object} AssignmentResponse
 // @Failure 400 {object} APIError
+// @Failure 404 {object} APIError
+// @Failure 409 {object} APIError
 // @Failure 500 {object} APIError
 // @Route /v1/interventions/{interventionID}/assignments [post]
 func (s *Server) handleCreateAssignment(w http.ResponseWriter, r *http.Request) {
@@ -322,6 +784,11 @@ func (s *Server) handleCreateAssignment(w http.ResponseWriter, r *http.Request)
 		status = db.AssignmentStatusDispatched
 	}
 
+	force, ok := s.parseForceParam(w, r)
+	if !ok {
+		return
+	}
+
 	params := db.CreateAssignmentParams{
 		InterventionID: interventionID,
 		UnitID:         unitID,
@@ -329,35 +796,136 @@ func (s *Server) handleCreateAssignment(w http.ResponseWriter, r *http.Request)
 		Status:         status,
 	}
 
-	row, err := s.queries.CreateAssignment(r.Context(), params)
+	ctx := r.Context()
+	tx, err := s.pool.Begin(ctx)
 	if err != nil {
-		s.writeError(w, http.StatusInternalServerError, "failed to create assignment", err.Error())
+		s.writeError(w, http.StatusInternalServerError, "failed to start transaction", err.Error())
 		return
 	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	qtx := s.queries.WithTx(tx)
+
+	if req.Role != nil && s.isSingletonRole(*req.Role) {
+		// Lock the intervention so two concurrent requests cannot both pass the check
+		if _, err := qtx.LockIntervention(ctx, interventionID); err != nil {
+			if isNotFound(err) {
+				s.writeError(w, http.StatusNotFound, "intervention not found", nil)
+				return
+			}
+			s.writeError(w, http.StatusInternalServerError, "failed to lock intervention", err.Error())
+			return
+		}
+		existing, err := qtx.CountActiveAssignmentsWithRole(ctx, db.CountActiveAssignmentsWithRoleParams{
+			InterventionID: interventionID,
+			Role:           *req.Role,
+		})
+		if err != nil {
+			s.writeError(w, http.StatusInternalServerError, "failed to check assignment role", err.Error())
+			return
+		}
+		if existing > 0 {
+			s.writeError(w, http.StatusConflict, "role already assigned on this intervention", *req.Role)
+			return
+		}
+	}
 
-	// Fetch unit for logging
-	unit, err := s.queries.GetUnit(r.Context(), unitID)
-	if err != nil {
-		s.log.Error().Err(err).Str("unit_id", req.UnitID).Msg("failed to fetch unit for logging")
+	if _, err := lockUnitForAssignment(ctx, qtx, unitID, force); err != nil {
+		s.writeAssignUnitError(w, err, unitID)
+		return
 	}
 
 	// Update unit status to 'under_way' when assigned
-	if _, err := s.queries.UpdateUnitStatus(r.Context(), db.UpdateUnitStatusParams{
-		ID:     unitID,
-		Status: db.UnitStatusUnderWay,
-	}); err != nil {
-		s.log.Error().Err(err).Str("unit_id", req.UnitID).Msg("failed to update unit status after assignment")
-		// We don't fail the whole request because the assignment was created
-	} else if unit.CallSign != "" {
-		s.logUnitStatusChange(r.Context(), unitID, unit.CallSign, string(unit.Status), string(db.UnitStatusUnderWay), nil)
+	unit, _, err := changeUnitStatus(ctx, qtx, unitID, db.UnitStatusUnderWay, nil)
+	if err != nil {
+		s.writeAssignUnitError(w, err, unitID)
+		return
+	}
+
+	row, err := qtx.CreateAssignment(ctx, params)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to create assignment", err.Error())
+		return
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to commit assignment", err.Error())
+		return
 	}
+	s.invalidateStaticData()
+
+	s.logUnitStatusChange(ctx, unitID, unit.CallSign, string(unit.Status), string(db.UnitStatusUnderWay), actorFromContext(ctx))
 
-	// Calculate and save route for the unit
-	go s.calculateAndSaveRouteForAssignment(context.Background(), interventionID, unitID)
+	s.logAssignmentChange(ctx, eventLogUnitDispatched, row.ID, actorFromContext(ctx))
+
+	s.routeNewAssignment(interventionID, unitID)
 
 	s.writeJSON(w, http.StatusCreated, mapAssignment(row))
 }
 
+// isSingletonRole reports whether at most one active assignment per intervention may hold role.
+func (s *Server) isSingletonRole(role string) bool {
+	for _, singleton := range s.cfg.Intervention.SingletonRoles {
+		if strings.EqualFold(strings.TrimSpace(singleton), role) {
+			return true
+		}
+	}
+	return false
+}
+
+// errUnitAlreadyAssigned is returned by lockUnitForAssignment for a unit that is active on another assignment.
+var errUnitAlreadyAssigned = errors.New("unit already has an active assignment")
+
+// lockUnitForAssignment row-locks the unit for the rest of the transaction, so two concurrent
+// requests cannot both dispatch it, and returns its current status. Unless force is set, a unit
+// that already has an active assignment is refused with errUnitAlreadyAssigned.
+func lockUnitForAssignment(ctx context.Context, qtx *db.Queries, unitID pgtype.UUID, force bool) (db.UnitStatus, error) {
+	status, err := qtx.LockUnit(ctx, unitID)
+	if err != nil {
+		return "", err
+	}
+	if force {
+		return status, nil
+	}
+	active, err := qtx.CountActiveAssignmentsForUnit(ctx, unitID)
+	if err != nil {
+		return "", fmt.Errorf("check unit assignments: %w", err)
+	}
+	if active > 0 {
+		return "", errUnitAlreadyAssigned
+	}
+	return status, nil
+}
+
+// writeAssignUnitError writes the 404, 409 or 500 matching an error from lockUnitForAssignment,
+// changeUnitStatus or createCandidateAssignment.
+func (s *Server) writeAssignUnitError(w http.ResponseWriter, err error, unitID pgtype.UUID) {
+	var transitionErr *unitTransitionError
+	switch {
+	case isNotFound(err):
+		s.writeError(w, http.StatusNotFound, errUnitNotFound, nil)
+	case errors.Is(err, errUnitAlreadyAssigned), errors.Is(err, errUnitNotAvailable):
+		s.writeError(w, http.StatusConflict, err.Error(), uuidString(unitID))
+	case errors.As(err, &transitionErr):
+		s.writeUnitStatusError(w, err)
+	default:
+		s.writeError(w, http.StatusInternalServerError, "failed to assign unit", err.Error())
+	}
+}
+
+// parseForceParam reads the optional ?force= flag. It writes a 400 and returns false when it is not a boolean.
+func (s *Server) parseForceParam(w http.ResponseWriter, r *http.Request) (bool, bool) {
+	raw := r.URL.Query().Get("force")
+	if raw == "" {
+		return false, true
+	}
+	force, err := strconv.ParseBool(raw)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid force", err.Error())
+		return false, false
+	}
+	return force, true
+}
+
 // handleReleaseAssignment godoc
 // @Title Release assignment
 // @Description Marks a unit as released from an intervention.
@@ -382,7 +950,16 @@ func (s *Server) handleReleaseAssignment(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	releasedID, err := s.queries.ReleaseUnitFromIntervention(r.Context(), db.ReleaseUnitFromInterventionParams{
+	ctx := r.Context()
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to start transaction", err.Error())
+		return
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	qtx := s.queries.WithTx(tx)
+
+	releasedID, err := qtx.ReleaseUnitFromIntervention(ctx, db.ReleaseUnitFromInterventionParams{
 		InterventionID: interventionID,
 		UnitID:         unitID,
 	})
@@ -395,26 +972,28 @@ func (s *Server) handleReleaseAssignment(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Fetch unit for logging
-	unit, err := s.queries.GetUnit(r.Context(), unitID)
+	// Set unit available again
+	unit, _, err := changeUnitStatus(ctx, qtx, unitID, db.UnitStatusAvailable, nil)
 	if err != nil {
-		s.log.Error().Err(err).Str("unit_id", uuidString(unitID)).Msg("failed to fetch unit for logging")
+		s.writeUnitStatusError(w, err)
+		return
 	}
 
-	// Set unit available again
-	if _, err := s.queries.UpdateUnitStatus(r.Context(), db.UpdateUnitStatusParams{
-		ID:     unitID,
-		Status: db.UnitStatusAvailable,
-	}); err != nil {
-		s.log.Error().Err(err).Str("unit_id", uuidString(unitID)).Msg("failed to update unit status after release")
-	} else if unit.CallSign != "" {
-		s.logUnitStatusChange(r.Context(), unitID, unit.CallSign, string(unit.Status), string(db.UnitStatusAvailable), nil)
+	if err := tx.Commit(ctx); err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to commit release", err.Error())
+		return
 	}
+	s.invalidateStaticData()
+
+	s.logUnitStatusChange(ctx, unitID, unit.CallSign, string(unit.Status), string(db.UnitStatusAvailable), nil)
 
 	s.observeAssignmentOnSite(r.Context(), releasedID)
+	s.logAssignmentChange(r.Context(), eventLogUnitReleased, releasedID, actorFromContext(r.Context()))
 	// Trigger return to station routing
 	go s.calculateAndSaveRouteToStation(context.Background(), unitID)
 
+	s.autoCompleteInterventionIfIdle(r.Context(), interventionID)
+
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -451,7 +1030,7 @@ func (s *Server) handleListAssignmentsForIntervention(w http.ResponseWriter, r *
 
 // handleUpdateAssignmentStatus godoc
 // @Title Update assignment status
-// @Description Updates the lifecycle state of a dispatched unit.
+// @Description Updates the lifecycle state of a dispatched unit. The first move to arrived or released stamps arrived_at or released_at and records the travel or on-site metric. The first arrival also moves a created intervention on_site. Repeating the current status changes nothing.
 // @Resource Interventions
 // @Accept json
 // @Produce json
@@ -475,25 +1054,57 @@ func (s *Server) handleUpdateAssignmentStatus(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	row, err := s.queries.UpdateAssignmentStatus(r.Context(), db.UpdateAssignmentStatusParams{
-		ID:      assignmentID,
-		Column2: db.AssignmentStatus(req.Status),
-	})
+	ctx := r.Context()
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to start transaction", err.Error())
+		return
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	qtx := s.queries.WithTx(tx)
+
+	// Lock the assignment so a repeated or concurrent update does not record the transition twice
+	prev, err := qtx.LockAssignment(ctx, assignmentID)
 	if err != nil {
 		if isNotFound(err) {
 			s.writeError(w, http.StatusNotFound, "assignment not found", nil)
 			return
 		}
+		s.writeError(w, http.StatusInternalServerError, "failed to lock assignment", err.Error())
+		return
+	}
+
+	row, err := qtx.UpdateAssignmentStatus(ctx, db.UpdateAssignmentStatusParams{
+		ID:      assignmentID,
+		Column2: db.AssignmentStatus(req.Status),
+	})
+	if err != nil {
 		s.writeError(w, http.StatusInternalServerError, "failed to update assignment", err.Error())
 		return
 	}
 
-	if row.Status == db.AssignmentStatusArrived {
+	if err := tx.Commit(ctx); err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to commit assignment", err.Error())
+		return
+	}
+
+	// Timestamps are only stamped once, so a metric is observed only when this update set it
+	if !prev.ArrivedAt.Valid && row.ArrivedAt.Valid {
 		s.observeAssignmentTravel(r.Context(), assignmentID)
+		s.startInterventionOnArrival(r.Context(), row.InterventionID, actorFromContext(r.Context()))
+	}
+	if !prev.ReleasedAt.Valid && row.ReleasedAt.Valid {
+		s.observeAssignmentOnSite(r.Context(), assignmentID)
+	}
+
+	if row.Status == prev.Status {
+		s.writeJSON(w, http.StatusOK, mapAssignment(row))
+		return
 	}
 
 	if row.Status == db.AssignmentStatusReleased {
-		s.observeAssignmentOnSite(r.Context(), assignmentID)
+		s.logAssignmentChange(r.Context(), eventLogUnitReleased, assignmentID, actorFromContext(r.Context()))
+		s.autoCompleteInterventionIfIdle(r.Context(), row.InterventionID)
 	}
 
 	s.writeJSON(w, http.StatusOK, mapAssignment(row))
@@ -514,6 +1125,60 @@ func mapIntervention(row db.Intervention) InterventionResponse {
 	}
 }
 
+// handleListActiveAssignments godoc
+// @Title List active assignments
+// @Description Returns every dispatched or arrived assignment across all interventions, with unit and event context. Ordered by event severity then dispatch time.
+// @Resource Interventions
+// @Produce json
+// @Param unit_type query string false "Filter by unit type code"
+// @Param limit query int false "Maximum results" default(100)
+// @Param offset query int false "Results offset" default(0)
+// @Success 200 {array} ActiveAssignmentResponse
+// @Failure 500 {object} APIError
+// @Route /v1/assignments/active [get]
+func (s *Server) handleListActiveAssignments(w http.ResponseWriter, r *http.Request) {
+	limit, offset := s.paginate(r, 100)
+
+	var unitType *string
+	if ut := strings.TrimSpace(r.URL.Query().Get("unit_type")); ut != "" {
+		unitType = &ut
+	}
+
+	rows, err := s.queries.ListActiveAssignments(r.Context(), db.ListActiveAssignmentsParams{
+		UnitType: unitType,
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to list active assignments", err.Error())
+		return
+	}
+
+	resp := make([]ActiveAssignmentResponse, 0, len(rows))
+	for _, row := range rows {
+		resp = append(resp, ActiveAssignmentResponse{
+			AssignmentResponse: AssignmentResponse{
+				ID:             uuidString(row.ID),
+				InterventionID: uuidString(row.InterventionID),
+				UnitID:         uuidString(row.UnitID),
+				UnitCallSign:   row.CallSign,
+				UnitTypeCode:   row.UnitTypeCode,
+				Role:           optionalString(row.Role),
+				Status:         string(row.Status),
+				DispatchedAt:   row.DispatchedAt.Time,
+				ArrivedAt:      timestamptzPtr(row.ArrivedAt),
+			},
+			UnitStatus:         string(row.UnitStatus),
+			InterventionStatus: string(row.InterventionStatus),
+			EventID:            uuidString(row.EventID),
+			EventTitle:         row.EventTitle,
+			EventSeverity:      row.EventSeverity,
+		})
+	}
+
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
 func mapAssignment(row db.InterventionAssignment) AssignmentResponse {
 	return AssignmentResponse{
 		ID:             uuidString(row.ID),
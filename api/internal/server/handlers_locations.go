@@ -11,10 +11,16 @@ import (
 // @Description  Returns the list of buildings of type 'station'
 // @Tags         buildings
 // @Produce      json
+// @Param        srid query int false "Output coordinate system" default(4326)
 // @Success      200 {array} LocationResponse
+// @Failure      400 {object} APIError
 // @Router       /v1/buildings [get]
 func (s *Server) handleListBuildings(w http.ResponseWriter, r *http.Request) {
     ctx := context.Background()
+    srid, ok := s.parseSRIDParam(w, r)
+    if !ok {
+        return
+    }
     rows, err := s.pool.Query(ctx, `
         SELECT id::text,
                name,
@@ -61,5 +67,14 @@ func (s *Server) handleListBuildings(w http.ResponseWriter, r *http.Request) {
         return
     }
 
+    points := make([]*GeoPoint, 0, len(out))
+    for i := range out {
+        points = append(points, &out[i].Location)
+    }
+    if err := s.reprojectPoints(ctx, srid, points); err != nil {
+        s.writeError(w, http.StatusInternalServerError, "failed to reproject coordinates", err.Error())
+        return
+    }
+
     s.writeJSON(w, http.StatusOK, out)
 }
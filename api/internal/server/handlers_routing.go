@@ -2,8 +2,14 @@ package server
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"sort"
 	"strconv"
+	"sync"
 	"time"
 
 	db "fast/pin/internal/db/sqlc"
@@ -31,6 +37,79 @@ type CalculateRouteResponse struct {
 	RouteGeoJSON             string  `json:"route_geojson"`
 	RouteLengthMeters        float64 `json:"route_length_meters"`
 	EstimatedDurationSeconds float64 `json:"estimated_duration_seconds"`
+	// AlreadyAtDestination is set when both points snap to the same graph vertex;
+	// the route is then zero-length and its geometry a single point at the destination.
+	AlreadyAtDestination bool `json:"already_at_destination,omitempty"`
+}
+
+// found reports whether the calculation produced a usable route.
+func (r CalculateRouteResponse) found() bool {
+	return r.AlreadyAtDestination || (r.RouteGeoJSON != "" && r.RouteLengthMeters > 0)
+}
+
+// CalculateRouteWithExclusionsRequest is the request body for POST /v1/routing/calculate-with-exclusions
+type CalculateRouteWithExclusionsRequest struct {
+	CalculateRouteRequest
+	ExcludedGIDs []int64 `json:"excluded_gids" validate:"required,min=1,max=500,dive,gt=0"`
+}
+
+// CalculateRouteWithExclusionsResponse compares the route with closed edges against the unrestricted one
+type CalculateRouteWithExclusionsResponse struct {
+	ExcludedGIDs []int64                `json:"excluded_gids"`
+	Baseline     CalculateRouteResponse `json:"baseline"`
+	// Rerouted is nil when the closures disconnect the two points.
+	Rerouted             *CalculateRouteResponse `json:"rerouted,omitempty"`
+	Reachable            bool                    `json:"reachable"`
+	DeltaLengthMeters    *float64                `json:"delta_length_meters,omitempty"`
+	DeltaDurationSeconds *float64                `json:"delta_duration_seconds,omitempty"`
+}
+
+// CalculateRoutesResponse lists alternative routes between two points, fastest first
+type CalculateRoutesResponse struct {
+	Routes []CalculateRouteResponse `json:"routes"`
+}
+
+// CalculateMultiRouteRequest is the request body for POST /v1/routing/calculate-multi
+type CalculateMultiRouteRequest struct {
+	// Points are visited in order, e.g. unit position, staging point, event
+	Points []GeoPoint `json:"points" validate:"required,min=2,max=10,dive"`
+}
+
+// routeLegFailure identifies the leg of a multi-leg route that could not be routed
+type routeLegFailure struct {
+	Leg  int      `json:"leg"`
+	From GeoPoint `json:"from"`
+	To   GeoPoint `json:"to"`
+}
+
+// NearestByTimeRequest is the request body for POST /v1/routing/nearest-by-time
+type NearestByTimeRequest struct {
+	Lat       float64  `json:"lat" validate:"required,latitude"`
+	Lon       float64  `json:"lon" validate:"required,longitude"`
+	UnitTypes []string `json:"unit_types"`
+	Statuses  []string `json:"statuses" validate:"dive,unit_status"`
+	MaxUnits  int32    `json:"max_units" validate:"omitempty,gte=1"`
+}
+
+// NearestByTimeUnit is a unit ranked by road travel time to the requested point
+type NearestByTimeUnit struct {
+	UnitID                   string   `json:"unit_id"`
+	CallSign                 string   `json:"call_sign"`
+	UnitTypeCode             string   `json:"unit_type_code"`
+	Status                   string   `json:"status"`
+	Location                 GeoPoint `json:"location"`
+	StraightLineMeters       float64  `json:"straight_line_meters"`
+	Reachable                bool     `json:"reachable"`
+	RouteLengthMeters        *float64 `json:"route_length_meters,omitempty"`
+	EstimatedDurationSeconds *float64 `json:"estimated_duration_seconds,omitempty"`
+	AlreadyAtDestination     bool     `json:"already_at_destination,omitempty"`
+}
+
+// NearestByTimeResponse lists units ordered by ETA; unreachable units come last
+type NearestByTimeResponse struct {
+	Destination GeoPoint            `json:"destination"`
+	Considered  int                 `json:"considered"`
+	Units       []NearestByTimeUnit `json:"units"`
 }
 
 // SaveUnitRouteRequest saves a calculated route for a unit
@@ -57,6 +136,34 @@ type UnitRouteResponse struct {
 	AutoSimulated            bool     `json:"auto_simulated"`
 }
 
+// RouteTurnPoint is a vertex of a route where the heading changes noticeably
+type RouteTurnPoint struct {
+	Location                GeoPoint `json:"location"`
+	HeadingChangeDegrees    float64  `json:"heading_change_degrees"`
+	DistanceFromStartMeters float64  `json:"distance_from_start_meters"`
+}
+
+// AssignmentRouteDestination describes the event an assignment is heading to
+type AssignmentRouteDestination struct {
+	EventID       string   `json:"event_id"`
+	Title         string   `json:"title"`
+	Address       *string  `json:"address,omitempty"`
+	EventTypeCode string   `json:"event_type_code"`
+	Severity      int32    `json:"severity"`
+	Location      GeoPoint `json:"location"`
+}
+
+// AssignmentRouteResponse is the response for GET /v1/assignments/{assignmentID}/route
+type AssignmentRouteResponse struct {
+	AssignmentID   string                     `json:"assignment_id"`
+	InterventionID string                     `json:"intervention_id"`
+	CallSign       string                     `json:"call_sign"`
+	Route          UnitRouteResponse          `json:"route"`
+	TurnPoints     []RouteTurnPoint           `json:"turn_points"`
+	Destination    AssignmentRouteDestination `json:"destination"`
+	ETA            time.Time                  `json:"eta"`
+}
+
 // UpdateProgressRequest updates the progress percentage
 type UpdateProgressRequest struct {
 	ProgressPercent float64 `json:"progress_percent" validate:"required,gte=0,lte=100"`
@@ -72,16 +179,66 @@ type UpdateProgressResponse struct {
 	RemainingSeconds float64 `json:"remaining_seconds"`
 }
 
+// SnapToRouteRequest is the request body for POST /v1/units/{unitID}/route/snap
+type SnapToRouteRequest struct {
+	Latitude  float64 `json:"latitude" validate:"required,latitude"`
+	Longitude float64 `json:"longitude" validate:"required,longitude"`
+}
+
+// SnapToRouteResponse is the nearest point of a unit route to a raw position
+type SnapToRouteResponse struct {
+	UnitID          string   `json:"unit_id"`
+	SnappedLocation GeoPoint `json:"snapped_location"`
+	// ProgressPercent is the progress implied by the snapped point; the stored progress is not changed
+	ProgressPercent        float64 `json:"progress_percent"`
+	CurrentProgressPercent float64 `json:"current_progress_percent"`
+	DistanceMeters         float64 `json:"distance_meters"`
+}
+
 // PositionResponse is a simple lat/lon response
 type PositionResponse struct {
 	Lat float64 `json:"lat"`
 	Lon float64 `json:"lon"`
 }
 
+// UnitBearingResponse is the response for GET /v1/units/{unitID}/bearing
+type UnitBearingResponse struct {
+	UnitID         string   `json:"unit_id"`
+	InterventionID string   `json:"intervention_id"`
+	EventID        string   `json:"event_id"`
+	UnitLocation   GeoPoint `json:"unit_location"`
+	EventLocation  GeoPoint `json:"event_location"`
+	DistanceMeters float64  `json:"distance_meters"`
+	// BearingDegrees is the initial great-circle bearing, clockwise from true north in [0, 360)
+	BearingDegrees float64 `json:"bearing_degrees"`
+}
+
+// UnitRouteResetResponse reports the routing state cleared by a reset
+type UnitRouteResetResponse struct {
+	UnitID                  string   `json:"unit_id"`
+	HadRoute                bool     `json:"had_route"`
+	PreviousInterventionID  *string  `json:"previous_intervention_id,omitempty"`
+	PreviousProgressPercent *float64 `json:"previous_progress_percent,omitempty"`
+	InterventionID          *string  `json:"intervention_id"`
+	ProgressPercent         float64  `json:"progress_percent"`
+}
+
+// RoutingNetworkStatsResponse summarises the routing graph topology
+type RoutingNetworkStatsResponse struct {
+	VertexCount          int64     `json:"vertex_count"`
+	EdgeCount            int64     `json:"edge_count"`
+	ComponentCount       int64     `json:"component_count"`
+	LargestComponentSize int64     `json:"largest_component_size"`
+	ComputedAt           time.Time `json:"computed_at"`
+	Cached               bool      `json:"cached"`
+}
+
 // =============================================================================
 // Route Calculation (Raw SQL for pgRouting)
 // =============================================================================
 
+// calculateRouteSQL takes from lon/lat ($1, $2), to lon/lat ($3, $4) and
+// an optional array of routing_ways gids to treat as closed ($5).
 const calculateRouteSQL = `
 WITH 
 start_vertex AS (
@@ -107,7 +264,7 @@ route_segments AS (
         path.seq,
         rw.cost_s      -- include the original column for API
     FROM pgr_astar(
-        $$
+        format($$
         SELECT
             gid AS id,
             source,
@@ -120,7 +277,8 @@ route_segments AS (
             END AS reverse_cost,
             x1, y1, x2, y2
         FROM routing_ways
-        $$,
+        WHERE gid <> ALL(%L::bigint[])
+        $$, COALESCE($5::bigint[], '{}'::bigint[])),
         (SELECT id FROM start_vertex),
         (SELECT id FROM end_vertex),
         directed := true,
@@ -132,42 +290,555 @@ route_segments AS (
 SELECT 
     COALESCE(ST_AsGeoJSON(ST_MakeLine(geom ORDER BY seq))::text, '') AS route_geojson,
     COALESCE(SUM(length_m), 0)::double precision AS route_length_meters,
-    COALESCE(SUM(cost_s), 0)::double precision AS estimated_duration_seconds
+    COALESCE(SUM(cost_s), 0)::double precision AS estimated_duration_seconds,
+    (SELECT id FROM start_vertex) = (SELECT id FROM end_vertex) AS same_vertex
 FROM route_segments;
 `
 
+// maxAlternativeRoutes caps the number of paths requested from pgr_ksp
+const maxAlternativeRoutes = 3
+
+// alternativeRoutesSQL takes from lon/lat ($1, $2), to lon/lat ($3, $4) and the
+// number of paths ($5). pgr_ksp returns loopless paths, so each one is distinct.
+const alternativeRoutesSQL = `
+WITH
+start_vertex AS (
+    SELECT id
+    FROM routing_ways_vertices_pgr
+    ORDER BY the_geom <-> ST_SetSRID(ST_MakePoint($1, $2), 4326)
+    LIMIT 1
+),
+end_vertex AS (
+    SELECT id
+    FROM routing_ways_vertices_pgr
+    ORDER BY the_geom <-> ST_SetSRID(ST_MakePoint($3, $4), 4326)
+    LIMIT 1
+),
+route_segments AS (
+    SELECT
+        path.path_id,
+        path.path_seq,
+        CASE
+            WHEN path.node = rw.source THEN rw.geom
+            ELSE ST_Reverse(rw.geom)
+        END AS geom,
+        rw.length_m,
+        rw.cost_s
+    FROM pgr_ksp(
+        'SELECT
+            gid AS id,
+            source,
+            target,
+            cost_s AS cost,
+            CASE
+                WHEN reverse_cost_s < 0
+                    THEN cost_s * 2
+                ELSE reverse_cost_s
+            END AS reverse_cost
+        FROM routing_ways',
+        (SELECT id FROM start_vertex),
+        (SELECT id FROM end_vertex),
+        $5::integer,
+        directed := true
+    ) AS path
+    JOIN routing_ways rw ON rw.gid = path.edge
+    WHERE path.edge > 0
+)
+SELECT
+    ST_AsGeoJSON(ST_MakeLine(geom ORDER BY path_seq))::text AS route_geojson,
+    SUM(length_m)::double precision AS route_length_meters,
+    SUM(cost_s)::double precision AS estimated_duration_seconds
+FROM route_segments
+GROUP BY path_id
+ORDER BY estimated_duration_seconds, path_id;
+`
+
+// networkStatsSQL counts the graph size and its connected components.
+// Uses the same edge filter as the component_id precomputation (migration 013).
+const networkStatsSQL = `
+WITH components AS (
+    SELECT component, COUNT(*) AS size
+    FROM pgr_connectedComponents(
+        'SELECT gid AS id, source, target, cost_s AS cost FROM routing_ways WHERE cost_s > 0'
+    )
+    GROUP BY component
+)
+SELECT
+    (SELECT COUNT(*) FROM routing_ways_vertices_pgr)::bigint AS vertex_count,
+    (SELECT COUNT(*) FROM routing_ways)::bigint AS edge_count,
+    (SELECT COUNT(*) FROM components)::bigint AS component_count,
+    COALESCE((SELECT MAX(size) FROM components), 0)::bigint AS largest_component_size;
+`
+
+// calculateRoute runs the pgRouting query between two points, reusing a cached route
+// for the same rounded point pair when ROUTING_ROUTE_CACHE_TTL is set.
+// Use found() on the result: an empty RouteGeoJSON or zero length means no route was found,
+// unless both points snapped to the same vertex and zero-length routes are allowed.
+func (s *Server) calculateRoute(ctx context.Context, fromLon, fromLat, toLon, toLat float64) (CalculateRouteResponse, error) {
+	if s.cfg.Routing.RouteCacheTTL <= 0 {
+		return s.calculateRouteExcluding(ctx, fromLon, fromLat, toLon, toLat, nil)
+	}
+
+	key := newRouteCacheKey(fromLon, fromLat, toLon, toLat)
+	if cached, ok := s.cachedRoute(ctx, key); ok {
+		return cached, nil
+	}
+
+	result, err := s.calculateRouteExcluding(ctx, fromLon, fromLat, toLon, toLat, nil)
+	if err == nil && result.found() {
+		s.cacheRoute(ctx, key, result)
+	}
+	return result, err
+}
+
+// calculateRouteExcluding is calculateRoute with the given routing_ways edges removed from the graph.
+func (s *Server) calculateRouteExcluding(ctx context.Context, fromLon, fromLat, toLon, toLat float64, excludedGIDs []int64) (CalculateRouteResponse, error) {
+	var result CalculateRouteResponse
+	var sameVertex bool
+	err := s.pool.QueryRow(ctx, calculateRouteSQL, fromLon, fromLat, toLon, toLat, excludedGIDs).
+		Scan(&result.RouteGeoJSON, &result.RouteLengthMeters, &result.EstimatedDurationSeconds, &sameVertex)
+	if err != nil {
+		return result, err
+	}
+
+	if sameVertex && s.cfg.Routing.AllowZeroLengthRoutes {
+		result = zeroLengthRoute(toLon, toLat)
+	}
+	return result, nil
+}
+
+// calculateAlternativeRoutes returns up to k distinct routes between two points, fastest first.
+// An empty result means the points are not connected.
+func (s *Server) calculateAlternativeRoutes(ctx context.Context, fromLon, fromLat, toLon, toLat float64, k int) ([]CalculateRouteResponse, error) {
+	rows, err := s.pool.Query(ctx, alternativeRoutesSQL, fromLon, fromLat, toLon, toLat, k)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	routes := make([]CalculateRouteResponse, 0, k)
+	for rows.Next() {
+		var route CalculateRouteResponse
+		if err := rows.Scan(&route.RouteGeoJSON, &route.RouteLengthMeters, &route.EstimatedDurationSeconds); err != nil {
+			return nil, err
+		}
+		routes = append(routes, route)
+	}
+	return routes, rows.Err()
+}
+
+// zeroLengthRoute is the route of a unit already at its destination.
+// The point is encoded as a degenerate LineString so it can still be stored in unit_routes.
+func zeroLengthRoute(lon, lat float64) CalculateRouteResponse {
+	return CalculateRouteResponse{
+		RouteGeoJSON:         fmt.Sprintf(`{"type":"LineString","coordinates":[[%[1]g,%[2]g],[%[1]g,%[2]g]]}`, lon, lat),
+		AlreadyAtDestination: true,
+	}
+}
+
+// appendRouteLeg appends the LineString of a leg to the coordinates of the route so far,
+// dropping the leg's first vertex when it repeats the current end of the route.
+func appendRouteLeg(coords [][2]float64, geojson string) ([][2]float64, error) {
+	var line struct {
+		Type        string       `json:"type"`
+		Coordinates [][2]float64 `json:"coordinates"`
+	}
+	if err := json.Unmarshal([]byte(geojson), &line); err != nil {
+		return nil, err
+	}
+	if line.Type != "LineString" {
+		return nil, fmt.Errorf("unsupported geometry type %q", line.Type)
+	}
+
+	leg := line.Coordinates
+	if len(coords) > 0 && len(leg) > 0 && coords[len(coords)-1] == leg[0] {
+		leg = leg[1:]
+	}
+	return append(coords, leg...), nil
+}
+
 // =============================================================================
 // Handlers
 // =============================================================================
 
-// handleCalculateRoute calculates a route between two points using pgRouting
+// reprojectRoutes transforms the geometry of calculated routes to the target SRID.
+// Nil routes are skipped.
+func (s *Server) reprojectRoutes(ctx context.Context, srid int32, routes ...*CalculateRouteResponse) error {
+	for _, route := range routes {
+		if route == nil {
+			continue
+		}
+		geojson, err := s.reprojectGeoJSON(ctx, srid, route.RouteGeoJSON)
+		if err != nil {
+			return err
+		}
+		route.RouteGeoJSON = geojson
+	}
+	return nil
+}
+
+// reprojectUnitRoute transforms a stored route's geometry and current position to the target SRID.
+func (s *Server) reprojectUnitRoute(ctx context.Context, srid int32, route *UnitRouteResponse) error {
+	geojson, err := s.reprojectGeoJSON(ctx, srid, route.RouteGeoJSON)
+	if err != nil {
+		return err
+	}
+	route.RouteGeoJSON = geojson
+	if route.CurrentLat == nil || route.CurrentLon == nil {
+		return nil
+	}
+	current := GeoPoint{Latitude: *route.CurrentLat, Longitude: *route.CurrentLon}
+	if err := s.reprojectPoints(ctx, srid, []*GeoPoint{&current}); err != nil {
+		return err
+	}
+	route.CurrentLat, route.CurrentLon = &current.Latitude, &current.Longitude
+	return nil
+}
+
+// handleCalculateRoute calculates a route between two points using pgRouting.
+// With ?alternatives=true it returns up to maxAlternativeRoutes distinct routes
+// as a CalculateRoutesResponse instead. The input points are WGS84; ?srid= reprojects
+// the returned geometry.
 func (s *Server) handleCalculateRoute(w http.ResponseWriter, r *http.Request) {
+	srid, ok := s.parseSRIDParam(w, r)
+	if !ok {
+		return
+	}
+
 	var req CalculateRouteRequest
 	if err := s.decodeAndValidate(r, &req); err != nil {
 		s.writeError(w, http.StatusBadRequest, errInvalidPayload, err.Error())
 		return
 	}
 
-	var result CalculateRouteResponse
-	err := s.pool.QueryRow(r.Context(), calculateRouteSQL, req.FromLon, req.FromLat, req.ToLon, req.ToLat).
-		Scan(&result.RouteGeoJSON, &result.RouteLengthMeters, &result.EstimatedDurationSeconds)
+	if r.URL.Query().Get("alternatives") == "true" {
+		s.writeAlternativeRoutes(w, r, req, srid)
+		return
+	}
 
+	result, err := s.calculateRoute(r.Context(), req.FromLon, req.FromLat, req.ToLon, req.ToLat)
 	if err != nil {
 		s.writeError(w, http.StatusInternalServerError, "failed to calculate route", err.Error())
 		return
 	}
 
 	// Check if route was found (empty geojson means no route)
-	if result.RouteGeoJSON == "" || result.RouteLengthMeters == 0 {
+	if !result.found() {
 		s.writeError(w, http.StatusNotFound, "no route found between points", nil)
 		return
 	}
 
+	if err := s.reprojectRoutes(r.Context(), srid, &result); err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to reproject coordinates", err.Error())
+		return
+	}
 	s.writeJSON(w, http.StatusOK, result)
 }
 
-// handleGetUnitRoute gets the stored route for a unit with current interpolated position
+// writeAlternativeRoutes answers handleCalculateRoute when alternatives are requested.
+func (s *Server) writeAlternativeRoutes(w http.ResponseWriter, r *http.Request, req CalculateRouteRequest, srid int32) {
+	routes, err := s.calculateAlternativeRoutes(r.Context(), req.FromLon, req.FromLat, req.ToLon, req.ToLat, maxAlternativeRoutes)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to calculate routes", err.Error())
+		return
+	}
+
+	if len(routes) == 0 {
+		// pgr_ksp yields nothing when both points snap to the same vertex
+		single, err := s.calculateRoute(r.Context(), req.FromLon, req.FromLat, req.ToLon, req.ToLat)
+		if err != nil {
+			s.writeError(w, http.StatusInternalServerError, "failed to calculate route", err.Error())
+			return
+		}
+		if !single.found() {
+			s.writeError(w, http.StatusNotFound, "no route found between points", nil)
+			return
+		}
+		routes = append(routes, single)
+	}
+
+	for i := range routes {
+		if err := s.reprojectRoutes(r.Context(), srid, &routes[i]); err != nil {
+			s.writeError(w, http.StatusInternalServerError, "failed to reproject coordinates", err.Error())
+			return
+		}
+	}
+	s.writeJSON(w, http.StatusOK, CalculateRoutesResponse{Routes: routes})
+}
+
+// handleCalculateMultiRoute calculates a route through an ordered list of waypoints.
+// Each consecutive pair is routed separately; the legs are joined into one LineString
+// and their lengths and durations summed. ?srid= reprojects the returned geometry.
+func (s *Server) handleCalculateMultiRoute(w http.ResponseWriter, r *http.Request) {
+	srid, ok := s.parseSRIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req CalculateMultiRouteRequest
+	if err := s.decodeAndValidate(r, &req); err != nil {
+		s.writeError(w, http.StatusBadRequest, errInvalidPayload, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	var result CalculateRouteResponse
+	coords := make([][2]float64, 0)
+	atDestination := true
+	for i := 1; i < len(req.Points); i++ {
+		from, to := req.Points[i-1], req.Points[i]
+		leg, err := s.calculateRoute(ctx, from.Longitude, from.Latitude, to.Longitude, to.Latitude)
+		if err != nil {
+			s.writeError(w, http.StatusInternalServerError, "failed to calculate route", err.Error())
+			return
+		}
+		if !leg.found() {
+			s.writeError(w, http.StatusNotFound, fmt.Sprintf("no route found for leg %d", i), routeLegFailure{Leg: i, From: from, To: to})
+			return
+		}
+
+		coords, err = appendRouteLeg(coords, leg.RouteGeoJSON)
+		if err != nil {
+			s.writeError(w, http.StatusInternalServerError, "failed to join route legs", err.Error())
+			return
+		}
+		result.RouteLengthMeters += leg.RouteLengthMeters
+		result.EstimatedDurationSeconds += leg.EstimatedDurationSeconds
+		atDestination = atDestination && leg.AlreadyAtDestination
+	}
+
+	if atDestination {
+		last := req.Points[len(req.Points)-1]
+		result = zeroLengthRoute(last.Longitude, last.Latitude)
+	} else {
+		geometry, err := json.Marshal(struct {
+			Type        string       `json:"type"`
+			Coordinates [][2]float64 `json:"coordinates"`
+		}{Type: "LineString", Coordinates: coords})
+		if err != nil {
+			s.writeError(w, http.StatusInternalServerError, "failed to encode route", err.Error())
+			return
+		}
+		result.RouteGeoJSON = string(geometry)
+	}
+
+	if err := s.reprojectRoutes(ctx, srid, &result); err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to reproject coordinates", err.Error())
+		return
+	}
+	s.writeJSON(w, http.StatusOK, result)
+}
+
+// handleCalculateRouteWithExclusions previews the impact of road closures: it routes between two points
+// with the given routing_ways edges removed and reports the difference with the unrestricted route.
+// ?srid= reprojects the returned geometries.
+func (s *Server) handleCalculateRouteWithExclusions(w http.ResponseWriter, r *http.Request) {
+	srid, ok := s.parseSRIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req CalculateRouteWithExclusionsRequest
+	if err := s.decodeAndValidate(r, &req); err != nil {
+		s.writeError(w, http.StatusBadRequest, errInvalidPayload, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	baseline, err := s.calculateRoute(ctx, req.FromLon, req.FromLat, req.ToLon, req.ToLat)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to calculate route", err.Error())
+		return
+	}
+	if !baseline.found() {
+		s.writeError(w, http.StatusNotFound, "no route found between points", nil)
+		return
+	}
+
+	rerouted, err := s.calculateRouteExcluding(ctx, req.FromLon, req.FromLat, req.ToLon, req.ToLat, req.ExcludedGIDs)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to calculate route with exclusions", err.Error())
+		return
+	}
+
+	resp := CalculateRouteWithExclusionsResponse{
+		ExcludedGIDs: req.ExcludedGIDs,
+		Baseline:     baseline,
+	}
+	if rerouted.found() {
+		deltaLength := rerouted.RouteLengthMeters - baseline.RouteLengthMeters
+		deltaDuration := rerouted.EstimatedDurationSeconds - baseline.EstimatedDurationSeconds
+		resp.Rerouted = &rerouted
+		resp.Reachable = true
+		resp.DeltaLengthMeters = &deltaLength
+		resp.DeltaDurationSeconds = &deltaDuration
+	}
+
+	if err := s.reprojectRoutes(ctx, srid, &resp.Baseline, resp.Rerouted); err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to reproject coordinates", err.Error())
+		return
+	}
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
+// routeOrigin is a unit position to route from in calculateRoutesTo.
+type routeOrigin struct {
+	unitID   string
+	location GeoPoint
+}
+
+// calculateRoutesTo routes every origin to (toLon, toLat), running at most
+// ROUTING_NEAREST_CONCURRENCY calculations at a time. The result is aligned with
+// origins; an entry is nil when no route was found or the calculation failed.
+func (s *Server) calculateRoutesTo(ctx context.Context, origins []routeOrigin, toLon, toLat float64) []*CalculateRouteResponse {
+	concurrency := s.cfg.Routing.NearestConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	routes := make([]*CalculateRouteResponse, len(origins))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, origin := range origins {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, origin routeOrigin) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			route, err := s.calculateRoute(ctx, origin.location.Longitude, origin.location.Latitude, toLon, toLat)
+			if err != nil {
+				s.log.Warn().Err(err).Str("unit_id", origin.unitID).Msg("failed to calculate route")
+				return
+			}
+			if route.found() {
+				routes[i] = &route
+			}
+		}(i, origin)
+	}
+	wg.Wait()
+	return routes
+}
+
+// handleNearestByTime ranks units by road ETA to an arbitrary point.
+// Candidates are pre-filtered by straight-line distance (capped by ROUTING_NEAREST_MAX_UNITS)
+// and their routes are computed concurrently. Statuses default to available.
+func (s *Server) handleNearestByTime(w http.ResponseWriter, r *http.Request) {
+	var req NearestByTimeRequest
+	if err := s.decodeAndValidate(r, &req); err != nil {
+		s.writeError(w, http.StatusBadRequest, errInvalidPayload, err.Error())
+		return
+	}
+
+	maxUnits := s.cfg.Routing.NearestMaxUnits
+	if req.MaxUnits > 0 && req.MaxUnits < maxUnits {
+		maxUnits = req.MaxUnits
+	}
+	statuses := req.Statuses
+	if len(statuses) == 0 {
+		statuses = []string{string(db.UnitStatusAvailable)}
+	}
+	var unitTypes []string
+	if len(req.UnitTypes) > 0 {
+		unitTypes = req.UnitTypes
+	}
+
+	ctx := r.Context()
+	rows, err := s.queries.ListUnitsNearPoint(ctx, db.ListUnitsNearPointParams{
+		Longitude: req.Lon,
+		Latitude:  req.Lat,
+		UnitTypes: unitTypes,
+		Statuses:  statuses,
+		MaxUnits:  maxUnits,
+	})
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to list units", err.Error())
+		return
+	}
+
+	units := make([]NearestByTimeUnit, len(rows))
+	origins := make([]routeOrigin, len(rows))
+	for i, row := range rows {
+		units[i] = NearestByTimeUnit{
+			UnitID:             uuidString(row.ID),
+			CallSign:           row.CallSign,
+			UnitTypeCode:       row.UnitTypeCode,
+			Status:             string(row.Status),
+			Location:           GeoPoint{Latitude: row.Latitude, Longitude: row.Longitude},
+			StraightLineMeters: row.DistanceMeters,
+		}
+		origins[i] = routeOrigin{unitID: units[i].UnitID, location: units[i].Location}
+	}
+
+	for i, route := range s.calculateRoutesTo(ctx, origins, req.Lon, req.Lat) {
+		if route == nil {
+			continue
+		}
+		units[i].Reachable = true
+		units[i].RouteLengthMeters = &route.RouteLengthMeters
+		units[i].EstimatedDurationSeconds = &route.EstimatedDurationSeconds
+		units[i].AlreadyAtDestination = route.AlreadyAtDestination
+	}
+
+	sort.SliceStable(units, func(i, j int) bool {
+		a, b := units[i], units[j]
+		if a.Reachable != b.Reachable {
+			return a.Reachable
+		}
+		if a.Reachable && *a.EstimatedDurationSeconds != *b.EstimatedDurationSeconds {
+			return *a.EstimatedDurationSeconds < *b.EstimatedDurationSeconds
+		}
+		return a.StraightLineMeters < b.StraightLineMeters
+	})
+
+	s.writeJSON(w, http.StatusOK, NearestByTimeResponse{
+		Destination: GeoPoint{Latitude: req.Lat, Longitude: req.Lon},
+		Considered:  len(units),
+		Units:       units,
+	})
+}
+
+// handleGetRoutingNetworkStats returns vertex/edge counts and connectivity of the routing graph.
+// Results are cached for ROUTING_NETWORK_STATS_TTL; pass ?refresh=true to recompute.
+func (s *Server) handleGetRoutingNetworkStats(w http.ResponseWriter, r *http.Request) {
+	if !s.authMw.RequireOneOfRoles(w, r, RoleIT) {
+		return
+	}
+
+	s.networkStatsMu.Lock()
+	defer s.networkStatsMu.Unlock()
+
+	refresh := r.URL.Query().Get("refresh") == "true"
+	if cached := s.networkStats; cached != nil && !refresh && time.Since(cached.ComputedAt) < s.cfg.Routing.NetworkStatsTTL {
+		resp := *cached
+		resp.Cached = true
+		s.writeJSON(w, http.StatusOK, resp)
+		return
+	}
+
+	var stats RoutingNetworkStatsResponse
+	err := s.pool.QueryRow(r.Context(), networkStatsSQL).
+		Scan(&stats.VertexCount, &stats.EdgeCount, &stats.ComponentCount, &stats.LargestComponentSize)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to compute network stats", err.Error())
+		return
+	}
+	stats.ComputedAt = time.Now().UTC()
+	s.networkStats = &stats
+
+	s.writeJSON(w, http.StatusOK, stats)
+}
+
+// handleGetUnitRoute gets the stored route for a unit with current interpolated position.
+// ?srid= reprojects the geometry and the position.
 func (s *Server) handleGetUnitRoute(w http.ResponseWriter, r *http.Request) {
+	srid, ok := s.parseSRIDParam(w, r)
+	if !ok {
+		return
+	}
+
 	unitUUID, err := s.parseUUIDParam(r, "unitID")
 	if err != nil {
 		s.writeError(w, http.StatusBadRequest, errInvalidUnitID, err.Error())
@@ -184,6 +855,16 @@ func (s *Server) handleGetUnitRoute(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	resp := mapUnitRoute(route)
+	if err := s.reprojectUnitRoute(r.Context(), srid, &resp); err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to reproject coordinates", err.Error())
+		return
+	}
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
+// mapUnitRoute converts a stored route row to its API representation
+func mapUnitRoute(route db.GetUnitRouteRow) UnitRouteResponse {
 	currentLat := route.CurrentLat
 	currentLon := route.CurrentLon
 	remainingMeters := route.RemainingMeters
@@ -207,9 +888,162 @@ func (s *Server) handleGetUnitRoute(w http.ResponseWriter, r *http.Request) {
 		resp.InterventionID = &id
 	}
 
+	return resp
+}
+
+// handleGetAssignmentRoute gets the route of the unit behind an assignment, with turn points,
+// destination event and ETA. ?srid= reprojects the geometry and every returned position.
+func (s *Server) handleGetAssignmentRoute(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	srid, ok := s.parseSRIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	assignmentID, err := s.parseUUIDParam(r, "assignmentID")
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid assignment id", err.Error())
+		return
+	}
+
+	assignment, err := s.queries.GetAssignmentContext(ctx, assignmentID)
+	if err != nil {
+		if isNotFound(err) {
+			s.writeError(w, http.StatusNotFound, "assignment not found", nil)
+			return
+		}
+		s.writeError(w, http.StatusInternalServerError, "failed to get assignment", err.Error())
+		return
+	}
+
+	route, err := s.queries.GetUnitRoute(ctx, assignment.UnitID)
+	if err != nil {
+		if isNotFound(err) {
+			s.writeError(w, http.StatusNotFound, errRouteNotFound, nil)
+			return
+		}
+		s.writeError(w, http.StatusInternalServerError, "failed to get route", err.Error())
+		return
+	}
+	// The unit may already be routed elsewhere (station return or another intervention)
+	if route.InterventionID != assignment.InterventionID {
+		s.writeError(w, http.StatusNotFound, "route not found for assignment", nil)
+		return
+	}
+
+	event, err := s.queries.GetEvent(ctx, assignment.EventID)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to get event", err.Error())
+		return
+	}
+
+	turnPoints, err := routeTurnPoints(route.RouteGeojson)
+	if err != nil {
+		s.log.Warn().Err(err).Str("unit_id", uuidString(assignment.UnitID)).Msg("failed to parse route geometry for turn points")
+		turnPoints = []RouteTurnPoint{}
+	}
+
+	resp := AssignmentRouteResponse{
+		AssignmentID:   uuidString(assignment.ID),
+		InterventionID: uuidString(assignment.InterventionID),
+		CallSign:       assignment.CallSign,
+		Route:          mapUnitRoute(route),
+		TurnPoints:     turnPoints,
+		Destination: AssignmentRouteDestination{
+			EventID:       uuidString(event.ID),
+			Title:         event.Title,
+			Address:       event.Address,
+			EventTypeCode: event.EventTypeCode,
+			Severity:      event.Severity,
+			Location:      GeoPoint{Latitude: event.Latitude, Longitude: event.Longitude},
+		},
+		ETA: time.Now().UTC().Add(time.Duration(route.RemainingSeconds * float64(time.Second))),
+	}
+
+	// Turn points are computed on the WGS84 geometry above, only their locations are reprojected
+	points := []*GeoPoint{&resp.Destination.Location}
+	for i := range resp.TurnPoints {
+		points = append(points, &resp.TurnPoints[i].Location)
+	}
+	if err := s.reprojectPoints(ctx, srid, points); err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to reproject coordinates", err.Error())
+		return
+	}
+	if err := s.reprojectUnitRoute(ctx, srid, &resp.Route); err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to reproject coordinates", err.Error())
+		return
+	}
 	s.writeJSON(w, http.StatusOK, resp)
 }
 
+// turnMinHeadingChange is the heading change (degrees) above which a route vertex counts as a turn
+const turnMinHeadingChange = 30.0
+
+// routeTurnPoints extracts the vertices of a GeoJSON LineString where the heading changes
+// by at least turnMinHeadingChange degrees
+func routeTurnPoints(geojson string) ([]RouteTurnPoint, error) {
+	points := make([]RouteTurnPoint, 0)
+	if geojson == "" {
+		return points, nil
+	}
+
+	var line struct {
+		Type        string       `json:"type"`
+		Coordinates [][2]float64 `json:"coordinates"`
+	}
+	if err := json.Unmarshal([]byte(geojson), &line); err != nil {
+		return nil, err
+	}
+	if line.Type != "LineString" {
+		return nil, fmt.Errorf("unsupported geometry type %q", line.Type)
+	}
+
+	coords := line.Coordinates
+	distance := 0.0
+	for i := 1; i < len(coords)-1; i++ {
+		prev, cur, next := coords[i-1], coords[i], coords[i+1]
+		distance += haversineMeters(prev[1], prev[0], cur[1], cur[0])
+
+		change := bearingDegrees(cur[1], cur[0], next[1], next[0]) - bearingDegrees(prev[1], prev[0], cur[1], cur[0])
+		// Normalise to (-180, 180] so left and right turns are signed
+		for change > 180 {
+			change -= 360
+		}
+		for change <= -180 {
+			change += 360
+		}
+		if math.Abs(change) < turnMinHeadingChange {
+			continue
+		}
+		points = append(points, RouteTurnPoint{
+			Location:                GeoPoint{Latitude: cur[1], Longitude: cur[0]},
+			HeadingChangeDegrees:    change,
+			DistanceFromStartMeters: distance,
+		})
+	}
+	return points, nil
+}
+
+// bearingDegrees returns the initial bearing from one point to another, clockwise from north
+func bearingDegrees(lat1, lon1, lat2, lon2 float64) float64 {
+	phi1, phi2 := lat1*math.Pi/180, lat2*math.Pi/180
+	dLambda := (lon2 - lon1) * math.Pi / 180
+	y := math.Sin(dLambda) * math.Cos(phi2)
+	x := math.Cos(phi1)*math.Sin(phi2) - math.Sin(phi1)*math.Cos(phi2)*math.Cos(dLambda)
+	return math.Atan2(y, x) * 180 / math.Pi
+}
+
+// haversineMeters returns the great-circle distance between two points
+func haversineMeters(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadiusMeters = 6371000.0
+	phi1, phi2 := lat1*math.Pi/180, lat2*math.Pi/180
+	dPhi := (lat2 - lat1) * math.Pi / 180
+	dLambda := (lon2 - lon1) * math.Pi / 180
+	a := math.Sin(dPhi/2)*math.Sin(dPhi/2) + math.Cos(phi1)*math.Cos(phi2)*math.Sin(dLambda/2)*math.Sin(dLambda/2)
+	return 2 * earthRadiusMeters * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
 // handleSaveUnitRoute saves a calculated route for a unit
 func (s *Server) handleSaveUnitRoute(w http.ResponseWriter, r *http.Request) {
 	unitUUID, err := s.parseUUIDParam(r, "unitID")
@@ -305,6 +1139,7 @@ func (s *Server) handleDeleteUnitRoute(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	s.archiveUnitRoute(r.Context(), unitUUID)
 	err = s.queries.DeleteUnitRoute(r.Context(), unitUUID)
 	if err != nil {
 		s.writeError(w, http.StatusInternalServerError, "failed to delete route", err.Error())
@@ -314,6 +1149,65 @@ func (s *Server) handleDeleteUnitRoute(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// handleResetUnitRoute clears a unit's routing state (route, intervention link, progress)
+// in a single transaction and returns what was cleared.
+func (s *Server) handleResetUnitRoute(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	unitUUID, err := s.parseUUIDParam(r, "unitID")
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, errInvalidUnitID, err.Error())
+		return
+	}
+
+	// Don't race with a background repair writing a fresh route
+	key := uuidString(unitUUID)
+	if _, loaded := s.repairLocks.LoadOrStore(key, struct{}{}); loaded {
+		s.writeError(w, http.StatusConflict, "route repair already in progress", nil)
+		return
+	}
+	defer s.repairLocks.Delete(key)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to begin transaction", err.Error())
+		return
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	qtx := s.queries.WithTx(tx)
+
+	if _, err := qtx.GetUnit(ctx, unitUUID); err != nil {
+		if isNotFound(err) {
+			s.writeError(w, http.StatusNotFound, errUnitNotFound, nil)
+			return
+		}
+		s.writeError(w, http.StatusInternalServerError, "failed to load unit", err.Error())
+		return
+	}
+
+	resp := UnitRouteResetResponse{UnitID: key}
+	cleared, err := qtx.ResetUnitRoute(ctx, unitUUID)
+	switch {
+	case err == nil:
+		resp.HadRoute = true
+		progress := cleared.ProgressPercent
+		resp.PreviousProgressPercent = &progress
+		if cleared.InterventionID.Valid {
+			id := uuidString(cleared.InterventionID)
+			resp.PreviousInterventionID = &id
+		}
+	case !isNotFound(err):
+		s.writeError(w, http.StatusInternalServerError, "failed to reset route", err.Error())
+		return
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to commit route reset", err.Error())
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
 // handleRepairUnitRoute attempts to rebuild a missing route for a unit.
 // If the unit is under_way with an active intervention, it recalculates that route.
 // Otherwise it marks the unit available and calculates a return-to-station route.
@@ -338,6 +1232,18 @@ func (s *Server) handleRepairUnitRoute(w http.ResponseWriter, r *http.Request) {
 	}
 	defer s.repairLocks.Delete(key)
 
+	if !s.tryAcquireRepairSlot() {
+		s.writeError(w, http.StatusTooManyRequests, "too many route repairs in progress", map[string]int{"max_concurrent_repairs": cap(s.repairSlots)})
+		return
+	}
+	// The slot is handed over to the background repair once it starts
+	started := false
+	defer func() {
+		if !started {
+			s.releaseRepairSlot()
+		}
+	}()
+
 	unit, err := s.queries.GetUnit(r.Context(), unitUUID)
 	if err != nil {
 		if isNotFound(err) {
@@ -356,7 +1262,11 @@ func (s *Server) handleRepairUnitRoute(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 		} else {
-			go s.repairRouteToEvent(context.Background(), data)
+			started = true
+			go func() {
+				defer s.releaseRepairSlot()
+				s.repairRouteToEvent(context.Background(), data)
+			}()
 			s.writeJSON(w, http.StatusAccepted, map[string]string{
 				"status": "repair_started",
 				"mode":   "intervention",
@@ -366,15 +1276,16 @@ func (s *Server) handleRepairUnitRoute(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Fallback: ensure unit is available and rebuild route to station.
-	if _, err := s.queries.UpdateUnitStatus(r.Context(), db.UpdateUnitStatusParams{
-		ID:     unitUUID,
-		Status: db.UnitStatusAvailable,
-	}); err != nil {
-		s.writeError(w, http.StatusInternalServerError, "failed to update unit status", err.Error())
+	if err := s.setUnitStatus(r.Context(), unitUUID, db.UnitStatusAvailable); err != nil {
+		s.writeUnitStatusError(w, err)
 		return
 	}
 
-	go s.calculateAndSaveRouteToStation(context.Background(), unitUUID)
+	started = true
+	go func() {
+		defer s.releaseRepairSlot()
+		s.calculateAndSaveRouteToStation(context.Background(), unitUUID)
+	}()
 	s.writeJSON(w, http.StatusAccepted, map[string]string{
 		"status": "repair_started",
 		"mode":   "return_to_station",
@@ -418,9 +1329,94 @@ func (s *Server) handleGetRoutePosition(w http.ResponseWriter, r *http.Request)
 	})
 }
 
+// handleSnapToUnitRoute projects a raw position onto the unit's stored route and returns
+// the nearest point of the route with the progress percent it implies
+func (s *Server) handleSnapToUnitRoute(w http.ResponseWriter, r *http.Request) {
+	unitUUID, err := s.parseUUIDParam(r, "unitID")
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, errInvalidUnitID, err.Error())
+		return
+	}
+
+	var req SnapToRouteRequest
+	if err := s.decodeAndValidate(r, &req); err != nil {
+		s.writeError(w, http.StatusBadRequest, errInvalidPayload, err.Error())
+		return
+	}
+
+	snap, err := s.queries.SnapPointToUnitRoute(r.Context(), db.SnapPointToUnitRouteParams{
+		Longitude: req.Longitude,
+		Latitude:  req.Latitude,
+		UnitID:    unitUUID,
+	})
+	if err != nil {
+		if isNotFound(err) {
+			s.writeError(w, http.StatusNotFound, errRouteNotFound, nil)
+			return
+		}
+		s.writeError(w, http.StatusInternalServerError, "failed to snap position to route", err.Error())
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, SnapToRouteResponse{
+		UnitID:                 uuidString(unitUUID),
+		SnappedLocation:        GeoPoint{Latitude: snap.SnappedLat, Longitude: snap.SnappedLon},
+		ProgressPercent:        snap.ProgressPercent,
+		CurrentProgressPercent: snap.CurrentProgressPercent,
+		DistanceMeters:         snap.DistanceMeters,
+	})
+}
+
+// handleGetUnitBearing returns the straight-line distance and initial bearing from a unit's
+// latest location to the event of its active assignment, so every client shows the same heading
+func (s *Server) handleGetUnitBearing(w http.ResponseWriter, r *http.Request) {
+	unitUUID, err := s.parseUUIDParam(r, "unitID")
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, errInvalidUnitID, err.Error())
+		return
+	}
+
+	data, err := s.queries.GetActiveRouteRepairData(r.Context(), unitUUID)
+	if err != nil {
+		if isNotFound(err) {
+			s.writeError(w, http.StatusNotFound, "unit has no active assignment", nil)
+			return
+		}
+		s.writeError(w, http.StatusInternalServerError, "failed to fetch assignment context", err.Error())
+		return
+	}
+
+	bearing := math.Mod(bearingDegrees(data.UnitLat, data.UnitLon, data.EventLat, data.EventLon)+360, 360)
+	s.writeJSON(w, http.StatusOK, UnitBearingResponse{
+		UnitID:         uuidString(data.UnitID),
+		InterventionID: uuidString(data.InterventionID),
+		EventID:        uuidString(data.EventID),
+		UnitLocation:   GeoPoint{Latitude: data.UnitLat, Longitude: data.UnitLon},
+		EventLocation:  GeoPoint{Latitude: data.EventLat, Longitude: data.EventLon},
+		DistanceMeters: haversineMeters(data.UnitLat, data.UnitLon, data.EventLat, data.EventLon),
+		BearingDegrees: bearing,
+	})
+}
+
+// routeNewAssignment calculates and saves the route of a new assignment in the background
+// when ROUTING_AUTO_ROUTE_ON_ASSIGN is set. At most ROUTING_ASSIGN_ROUTE_CONCURRENCY
+// calculations run at once; the others wait their turn.
+func (s *Server) routeNewAssignment(interventionID, unitID pgtype.UUID) {
+	if !s.cfg.Routing.AutoRouteOnAssign {
+		return
+	}
+	go func() {
+		s.assignRouteSem <- struct{}{}
+		defer func() { <-s.assignRouteSem }()
+		_ = s.calculateAndSaveRouteForAssignment(context.Background(), interventionID, unitID, false)
+	}()
+}
+
 // calculateAndSaveRouteForAssignment calculates a route from unit to event location and saves it.
-// Called asynchronously when a unit is assigned to an intervention.
-func (s *Server) calculateAndSaveRouteForAssignment(ctx context.Context, interventionID, unitID pgtype.UUID) {
+// Called asynchronously when a unit is assigned to an intervention. Recalculations of an existing
+// route (stale refresh, deviation) set fresh so the route cache is bypassed. Failures are logged
+// and returned; errNoRouteFound means pgRouting found no path.
+func (s *Server) calculateAndSaveRouteForAssignment(ctx context.Context, interventionID, unitID pgtype.UUID, fresh bool) error {
 	startTime := time.Now()
 	s.log.Info().
 		Str("unit_id", uuidString(unitID)).
@@ -438,19 +1434,17 @@ func (s *Server) calculateAndSaveRouteForAssignment(ctx context.Context, interve
 			Str("unit_id", uuidString(unitID)).
 			Dur("elapsed_ms", time.Since(startTime)).
 			Msg("failed to get route calculation data")
-		return
+		return err
 	}
 
 	// 2. Calculate the route using pgRouting
-	var routeResult struct {
-		RouteGeoJSON             string  `db:"route_geojson"`
-		RouteLengthMeters        float64 `db:"route_length_meters"`
-		EstimatedDurationSeconds float64 `db:"estimated_duration_seconds"`
+	var routeResult CalculateRouteResponse
+	if fresh {
+		routeResult, err = s.calculateRouteExcluding(ctx, data.UnitLon, data.UnitLat, data.EventLon, data.EventLat, nil)
+	} else {
+		routeResult, err = s.calculateRoute(ctx, data.UnitLon, data.UnitLat, data.EventLon, data.EventLat)
 	}
 
-	err = s.pool.QueryRow(ctx, calculateRouteSQL, data.UnitLon, data.UnitLat, data.EventLon, data.EventLat).
-		Scan(&routeResult.RouteGeoJSON, &routeResult.RouteLengthMeters, &routeResult.EstimatedDurationSeconds)
-
 	if err != nil {
 		s.log.Error().Err(err).
 			Str("unit_id", uuidString(unitID)).
@@ -460,17 +1454,17 @@ func (s *Server) calculateAndSaveRouteForAssignment(ctx context.Context, interve
 			Float64("to_lon", data.EventLon).
 			Dur("elapsed_ms", time.Since(startTime)).
 			Msg("failed to calculate route")
-		return
+		return err
 	}
 
 	// Check if route was found
-	if routeResult.RouteGeoJSON == "" || routeResult.RouteLengthMeters == 0 {
+	if !routeResult.found() {
 		s.log.Warn().
 			Str("unit_id", uuidString(unitID)).
 			Str("intervention_id", uuidString(interventionID)).
 			Dur("elapsed_ms", time.Since(startTime)).
 			Msg("no route found between unit and event location")
-		return
+		return errNoRouteFound
 	}
 
 	// 3. Save the route for the unit
@@ -487,8 +1481,10 @@ func (s *Server) calculateAndSaveRouteForAssignment(ctx context.Context, interve
 			Str("unit_id", uuidString(unitID)).
 			Dur("elapsed_ms", time.Since(startTime)).
 			Msg("failed to save route")
-		return
+		return err
 	}
+	// Background refreshes and deviation repairs bypass the write middleware
+	s.changes.notify()
 
 	elapsed := time.Since(startTime)
 	s.log.Info().
@@ -498,8 +1494,12 @@ func (s *Server) calculateAndSaveRouteForAssignment(ctx context.Context, interve
 		Float64("duration_s", routeResult.EstimatedDurationSeconds).
 		Dur("elapsed_ms", elapsed).
 		Msg("route calculation completed for assignment")
+	return nil
 }
 
+// errNoRouteFound is returned by calculateAndSaveRouteForAssignment when pgRouting finds no path.
+var errNoRouteFound = errors.New("no route found")
+
 // calculateAndSaveRouteToStation calculates a route from unit to its home station and saves it.
 // Called asynchronously when a unit status changes to 'available'.
 func (s *Server) calculateAndSaveRouteToStation(ctx context.Context, unitID pgtype.UUID) {
@@ -519,14 +1519,7 @@ func (s *Server) calculateAndSaveRouteToStation(ctx context.Context, unitID pgty
 	}
 
 	// 2. Calculate the route using pgRouting
-	var routeResult struct {
-		RouteGeoJSON             string  `db:"route_geojson"`
-		RouteLengthMeters        float64 `db:"route_length_meters"`
-		EstimatedDurationSeconds float64 `db:"estimated_duration_seconds"`
-	}
-
-	err = s.pool.QueryRow(ctx, calculateRouteSQL, data.UnitLon, data.UnitLat, data.StationLon, data.StationLat).
-		Scan(&routeResult.RouteGeoJSON, &routeResult.RouteLengthMeters, &routeResult.EstimatedDurationSeconds)
+	routeResult, err := s.calculateRoute(ctx, data.UnitLon, data.UnitLat, data.StationLon, data.StationLat)
 
 	if err != nil {
 		s.log.Error().Err(err).
@@ -541,7 +1534,7 @@ func (s *Server) calculateAndSaveRouteToStation(ctx context.Context, unitID pgty
 	}
 
 	// Check if route was found
-	if routeResult.RouteGeoJSON == "" || routeResult.RouteLengthMeters == 0 {
+	if !routeResult.found() {
 		s.log.Warn().
 			Str("unit_id", uuidString(unitID)).
 			Str("station_id", uuidString(data.StationID)).
@@ -550,7 +1543,8 @@ func (s *Server) calculateAndSaveRouteToStation(ctx context.Context, unitID pgty
 		return
 	}
 
-	// 3. Save the route for the unit (intervention_id is NULL)
+	// 3. Save the route for the unit (intervention_id is NULL), archiving the intervention route it replaces
+	s.archiveUnitRoute(ctx, unitID)
 	_, err = s.queries.SaveUnitRoute(ctx, db.SaveUnitRouteParams{
 		UnitID:                   unitID,
 		InterventionID:           pgtype.UUID{Valid: false},
@@ -566,6 +1560,8 @@ func (s *Server) calculateAndSaveRouteToStation(ctx context.Context, unitID pgty
 			Msg("failed to save route")
 		return
 	}
+	// Runs in the background after the triggering request has already notified
+	s.changes.notify()
 
 	elapsed := time.Since(startTime)
 	s.log.Info().
@@ -578,17 +1574,11 @@ func (s *Server) calculateAndSaveRouteToStation(ctx context.Context, unitID pgty
 }
 
 // repairRouteToEvent recalculates and stores a route for an active intervention.
+// The route cache is bypassed since a repair is asked for when the stored route is wrong.
 func (s *Server) repairRouteToEvent(ctx context.Context, data db.GetActiveRouteRepairDataRow) {
 	startTime := time.Now()
 
-	var routeResult struct {
-		RouteGeoJSON             string  `db:"route_geojson"`
-		RouteLengthMeters        float64 `db:"route_length_meters"`
-		EstimatedDurationSeconds float64 `db:"estimated_duration_seconds"`
-	}
-
-	err := s.pool.QueryRow(ctx, calculateRouteSQL, data.UnitLon, data.UnitLat, data.EventLon, data.EventLat).
-		Scan(&routeResult.RouteGeoJSON, &routeResult.RouteLengthMeters, &routeResult.EstimatedDurationSeconds)
+	routeResult, err := s.calculateRouteExcluding(ctx, data.UnitLon, data.UnitLat, data.EventLon, data.EventLat, nil)
 
 	if err != nil {
 		s.log.Error().Err(err).
@@ -598,7 +1588,7 @@ func (s *Server) repairRouteToEvent(ctx context.Context, data db.GetActiveRouteR
 		return
 	}
 
-	if routeResult.RouteGeoJSON == "" || routeResult.RouteLengthMeters == 0 {
+	if !routeResult.found() {
 		s.log.Warn().
 			Str("unit_id", uuidString(data.UnitID)).
 			Str("intervention_id", uuidString(data.InterventionID)).
@@ -623,6 +1613,8 @@ func (s *Server) repairRouteToEvent(ctx context.Context, data db.GetActiveRouteR
 			Msg("failed to save repaired route")
 		return
 	}
+	// Runs in the background after the 202 has already notified
+	s.changes.notify()
 
 	s.log.Info().
 		Str("unit_id", uuidString(data.UnitID)).
@@ -3,8 +3,14 @@ package server
 import (
 	"context"
 	db "fast/pin/internal/db/sqlc"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 // handleSync godoc
@@ -13,49 +19,242 @@ import (
 // @Resource Common
 // @Produce json
 // @Param limit_events query int false "Maximum events" default(25)
-// @Param limit_logs query int false "Maximum logs" default(3)
+// @Param limit_logs query int false "Maximum logs, capped at SYNC_MAX_LIMIT_LOGS (100)" default(10)
 // @Param deny_status query string false "Comma-separated intervention statuses to exclude (e.g., completed,cancelled)"
 // @Success 200 {object} SyncResponse
+// @Success 207 {object} SyncResponse "Partial response, see errors"
+// @Failure 400 {object} APIError
 // @Failure 500 {object} APIError
 // @Route /v1/sync [get]
 func (s *Server) handleSync(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+	limitEvents, limitLogs, ok := s.parseSyncLimits(w, r)
+	if !ok {
+		return
+	}
+
+	resp, sectionErrors := s.buildSyncResponse(r.Context(), r, limitEvents, limitLogs)
+	if len(sectionErrors) > 0 {
+		resp.Partial = true
+		resp.Errors = sectionErrors
+	}
+	s.writeSyncResponse(w, resp, sectionErrors)
+}
 
-	// Parse parameters
-	limitEvents, _ := strconv.Atoi(r.URL.Query().Get("limit_events"))
+// parseSyncLimits reads limit_events and limit_logs. It writes a 400 and returns false on invalid input.
+func (s *Server) parseSyncLimits(w http.ResponseWriter, r *http.Request) (limitEvents, limitLogs int, ok bool) {
+	limitEvents, _ = strconv.Atoi(r.URL.Query().Get("limit_events"))
 	if limitEvents <= 0 {
 		limitEvents = 25
 	}
-	limitLogs, _ := strconv.Atoi(r.URL.Query().Get("limit_logs"))
-	if limitLogs <= 0 {
-		limitLogs = 10
+	limitLogs = s.cfg.Sync.DefaultLimitLogs
+	if raw := r.URL.Query().Get("limit_logs"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			s.writeError(w, http.StatusBadRequest, "invalid limit_logs", "limit_logs must be a positive integer")
+			return 0, 0, false
+		}
+		limitLogs = parsed
+	}
+	if maxLogs := s.cfg.Sync.MaxLimitLogs; maxLogs > 0 && limitLogs > maxLogs {
+		limitLogs = maxLogs
+	}
+	return limitEvents, limitLogs, true
+}
+
+// buildSyncResponse fetches every sync section. Each section is fetched independently
+// so a failure in one does not prevent the client from receiving the others.
+func (s *Server) buildSyncResponse(ctx context.Context, r *http.Request, limitEvents, limitLogs int) (SyncResponse, map[string]string) {
+	resp := SyncResponse{
+		Events:     []EventSummaryResponse{},
+		Units:      []UnitResponse{},
+		RecentLogs: []ActivityLogResponse{},
 	}
+	sectionErrors := make(map[string]string)
 
-	// Fetch all data concurrently
-	eventsResp, err := s.fetchEventsForSync(ctx, limitEvents, r.URL.Query().Get("deny_status"))
-	if err != nil {
-		s.writeError(w, http.StatusInternalServerError, "failed to fetch events", err.Error())
+	if eventsResp, err := s.fetchEventsForSync(ctx, limitEvents, r.URL.Query().Get("deny_status")); err != nil {
+		s.log.Warn().Err(err).Msg("sync: failed to fetch events")
+		sectionErrors["events"] = err.Error()
+	} else {
+		resp.Events = eventsResp
+	}
+
+	if unitsResp, err := s.fetchUnitsForSync(ctx); err != nil {
+		s.log.Warn().Err(err).Msg("sync: failed to fetch units")
+		sectionErrors["units"] = err.Error()
+	} else {
+		resp.Units = unitsResp
+	}
+
+	if logsResp, err := s.fetchActivityLogsForSync(ctx, limitLogs); err != nil {
+		s.log.Warn().Err(err).Msg("sync: failed to fetch activity logs")
+		sectionErrors["recent_logs"] = err.Error()
+	} else {
+		resp.RecentLogs = logsResp
+	}
+
+	return resp, sectionErrors
+}
+
+// syncPollOverlap widens the since filter of a poll. The cursor is the time the change hub
+// was notified, which can be later than the updated_at/created_at the database stamped on
+// that change (transaction start, clock skew), so rows just before the cursor are repeated.
+const syncPollOverlap = 5 * time.Second
+
+// syncSectionCount is the number of sections fetched by buildSyncResponse.
+const syncSectionCount = 3
+
+// writeSyncResponse writes a 200, a 207 when some sections failed or a 500 when all did.
+func (s *Server) writeSyncResponse(w http.ResponseWriter, resp any, sectionErrors map[string]string) {
+	switch len(sectionErrors) {
+	case 0:
+		s.writeJSON(w, http.StatusOK, resp)
+	case syncSectionCount:
+		s.writeError(w, http.StatusInternalServerError, "failed to fetch sync data", sectionErrors)
+	default:
+		s.writeJSON(w, http.StatusMultiStatus, resp)
+	}
+}
+
+// handleSyncPoll godoc
+// @Title Long-poll sync
+// @Description Long-polling fallback for clients that cannot keep a stream open. Holds the request until data changes after since or wait elapses, then returns the sync sections changed after since. Pass the returned cursor as the next since; without since the full sync payload is returned immediately. Items changed up to 5s before since are returned again, so clients should merge by id.
+// @Resource Common
+// @Produce json
+// @Param since query string false "Cursor (RFC3339) returned by the previous poll"
+// @Param wait query string false "Maximum hold time as a Go duration, capped at SYNC_POLL_MAX_WAIT" default(25s)
+// @Param limit_events query int false "Maximum events" default(25)
+// @Param limit_logs query int false "Maximum logs, capped at SYNC_MAX_LIMIT_LOGS (100)" default(10)
+// @Param deny_status query string false "Comma-separated intervention statuses to exclude (e.g., completed,cancelled)"
+// @Success 200 {object} SyncPollResponse
+// @Success 207 {object} SyncPollResponse "Partial response, see errors"
+// @Failure 400 {object} APIError
+// @Failure 429 {object} APIError
+// @Failure 500 {object} APIError
+// @Route /v1/sync/poll [get]
+func (s *Server) handleSyncPoll(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var since time.Time
+	if raw := query.Get("since"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, "invalid since", err.Error())
+			return
+		}
+		since = parsed.UTC()
+	}
+
+	wait := s.cfg.Sync.PollDefaultWait
+	if raw := query.Get("wait"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil || parsed < 0 {
+			s.writeError(w, http.StatusBadRequest, "invalid wait", "wait must be a non-negative duration")
+			return
+		}
+		wait = parsed
+	}
+	if wait > s.cfg.Sync.PollMaxWait {
+		wait = s.cfg.Sync.PollMaxWait
+	}
+
+	limitEvents, limitLogs, ok := s.parseSyncLimits(w, r)
+	if !ok {
 		return
 	}
 
-	unitsResp, err := s.fetchUnitsForSync(ctx)
-	if err != nil {
-		s.writeError(w, http.StatusInternalServerError, "failed to fetch units", err.Error())
+	release, ok := s.acquireStreamSlot(w, r)
+	if !ok {
 		return
 	}
+	defer release()
 
-	logsResp, err := s.fetchActivityLogsForSync(ctx, limitLogs)
-	if err != nil {
-		s.writeError(w, http.StatusInternalServerError, "failed to fetch activity logs", err.Error())
+	// The held request may outlive HTTP_WRITE_TIMEOUT
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(wait + 10*time.Second))
+
+	resp := SyncPollResponse{Changed: true}
+	if since.IsZero() {
+		resp.Cursor = s.changes.lastChange()
+	} else {
+		resp.Cursor, resp.Changed = s.changes.wait(r.Context(), since, wait)
+	}
+	if resp.Cursor.IsZero() {
+		resp.Cursor = time.Now().UTC()
+	}
+	if r.Context().Err() != nil {
 		return
 	}
 
-	// Write consolidated response
-	s.writeJSON(w, http.StatusOK, SyncResponse{
-		Events:     eventsResp,
-		Units:      unitsResp,
-		RecentLogs: logsResp,
-	})
+	resp.SyncResponse = SyncResponse{
+		Events:     []EventSummaryResponse{},
+		Units:      []UnitResponse{},
+		RecentLogs: []ActivityLogResponse{},
+	}
+	if !resp.Changed {
+		s.writeJSON(w, http.StatusOK, resp)
+		return
+	}
+
+	full, sectionErrors := s.buildSyncResponse(r.Context(), r, limitEvents, limitLogs)
+	if since.IsZero() {
+		resp.SyncResponse = full
+	} else {
+		resp.SyncResponse = syncDelta(full, since.Add(-syncPollOverlap))
+	}
+	if len(sectionErrors) > 0 {
+		resp.Partial = true
+		resp.Errors = sectionErrors
+	}
+	s.writeSyncResponse(w, resp, sectionErrors)
+}
+
+// syncDelta keeps the parts of a sync payload that changed after since: events updated
+// or whose intervention or assigned units moved, units updated and logs created.
+func syncDelta(full SyncResponse, since time.Time) SyncResponse {
+	delta := SyncResponse{
+		Events:     make([]EventSummaryResponse, 0),
+		Units:      make([]UnitResponse, 0),
+		RecentLogs: make([]ActivityLogResponse, 0),
+	}
+	after := func(t *time.Time) bool { return t != nil && t.After(since) }
+
+	for _, e := range full.Events {
+		changed := e.UpdatedAt.After(since) || after(e.StartedAt) || after(e.CompletedAt) || after(e.ClosedAt)
+		for _, u := range e.AssignedUnits {
+			changed = changed || u.UpdatedAt.After(since)
+		}
+		if changed {
+			delta.Events = append(delta.Events, e)
+		}
+	}
+	for _, u := range full.Units {
+		if u.UpdatedAt.After(since) || after(u.LastContact) {
+			delta.Units = append(delta.Units, u)
+		}
+	}
+	for _, l := range full.RecentLogs {
+		if l.CreatedAt.After(since) {
+			delta.RecentLogs = append(delta.RecentLogs, l)
+		}
+	}
+	return delta
+}
+
+// newDenySet builds a deny set from configured statuses, rejecting values
+// that are not part of the intervention_status enum.
+func newDenySet(statuses []string) (map[db.InterventionStatus]struct{}, error) {
+	denySet := make(map[db.InterventionStatus]struct{}, len(statuses))
+	for _, raw := range statuses {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		st := db.InterventionStatus(raw)
+		if !isKnownInterventionStatus(st) {
+			return nil, fmt.Errorf("unknown intervention status %q", raw)
+		}
+		denySet[st] = struct{}{}
+	}
+	return denySet, nil
 }
 
 // fetchEventsForSync retrieves events with assigned units, filtered by deny_status
@@ -67,14 +266,11 @@ func (s *Server) fetchEventsForSync(ctx context.Context, limit int, denyStatusPa
 
 	denySet := s.parseDenySet(denyStatusParam)
 	if denySet == nil {
-		// Default behavior: exclude completed/cancelled
-		denySet = map[db.InterventionStatus]struct{}{
-			db.InterventionStatusCompleted: {},
-			db.InterventionStatusCancelled: {},
-		}
+		// Default behavior: exclude the statuses configured in SYNC_DEFAULT_DENY_STATUSES
+		denySet = s.syncDefaultDeny
 	}
 
-	eventsResp := make([]EventSummaryResponse, 0, len(eventRows))
+	visible := make([]db.ListEventsRow, 0, len(eventRows))
 	for _, row := range eventRows {
 		// Skip if intervention status is denied
 		if denySet != nil && row.InterventionStatus.Valid {
@@ -82,37 +278,95 @@ func (s *Server) fetchEventsForSync(ctx context.Context, limit int, denyStatusPa
 				continue
 			}
 		}
+		visible = append(visible, row)
+	}
 
-		// Fetch assigned units for this event
-		assigned, err := s.queries.ListUnitsAssignedToEvent(ctx, row.ID)
-		if err != nil {
-			return nil, err
-		}
-
-		assignedUnits := make([]UnitResponse, 0, len(assigned))
-		for _, u := range assigned {
-			assignedUnits = append(assignedUnits, mapUnitRow(unitRowData{
-				ID:           u.ID,
-				CallSign:     u.CallSign,
-				UnitTypeCode: u.UnitTypeCode,
-				HomeBaseName: u.HomeBaseName,
-				LocationID:   u.LocationID,
-				Status:       u.Status,
-				MicrobitID:   u.MicrobitID,
-				Longitude:    u.Longitude,
-				Latitude:     u.Latitude,
-				LastContact:  u.LastContactAt,
-				CreatedAt:    u.CreatedAt,
-				UpdatedAt:    u.UpdatedAt,
-			}))
-		}
+	assigned, err := s.fetchAssignedUnitsForSync(ctx, visible)
+	if err != nil {
+		return nil, err
+	}
 
-		eventsResp = append(eventsResp, mapEventSummary(row, assignedUnits))
+	eventsResp := make([]EventSummaryResponse, 0, len(visible))
+	for i, row := range visible {
+		eventsResp = append(eventsResp, mapEventSummary(row, assigned[i]))
 	}
 
 	return eventsResp, nil
 }
 
+// fetchAssignedUnitsForSync lists the assigned units of each event, running at most
+// SYNC_UNIT_FETCH_CONCURRENCY queries at once. Results are in the order of rows.
+func (s *Server) fetchAssignedUnitsForSync(ctx context.Context, rows []db.ListEventsRow) ([][]UnitResponse, error) {
+	concurrency := s.cfg.Sync.UnitFetchConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	// Cancel the remaining queries once one fails
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		assigned = make([][]UnitResponse, len(rows))
+		sem      = make(chan struct{}, concurrency)
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	for i, row := range rows {
+		// Stop launching queries once one failed or the request went away
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(i int, eventID pgtype.UUID) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			units, err := s.queries.ListUnitsAssignedToEvent(ctx, eventID)
+			if err != nil {
+				errOnce.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			mapped := make([]UnitResponse, 0, len(units))
+			for _, u := range units {
+				mapped = append(mapped, mapUnitRow(unitRowData{
+					ID:           u.ID,
+					CallSign:     u.CallSign,
+					UnitTypeCode: u.UnitTypeCode,
+					HomeBaseName: u.HomeBaseName,
+					LocationID:   u.LocationID,
+					Status:       u.Status,
+					MicrobitID:   u.MicrobitID,
+					Longitude:    u.Longitude,
+					Latitude:     u.Latitude,
+					LastContact:  u.LastContactAt,
+					CreatedAt:    u.CreatedAt,
+					UpdatedAt:    u.UpdatedAt,
+				}))
+			}
+			assigned[i] = mapped
+		}(i, row.ID)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return assigned, nil
+}
+
 // fetchUnitsForSync retrieves all units
 func (s *Server) fetchUnitsForSync(ctx context.Context) ([]UnitResponse, error) {
 	unitRows, err := s.queries.ListUnits(ctx)
@@ -135,6 +389,7 @@ func (s *Server) fetchUnitsForSync(ctx context.Context) ([]UnitResponse, error)
 			LastContact:  row.LastContactAt,
 			CreatedAt:    row.CreatedAt,
 			UpdatedAt:    row.UpdatedAt,
+			Version:      row.Version,
 		}))
 	}
 
@@ -2,6 +2,9 @@ package server
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
@@ -15,27 +18,62 @@ import (
 
 const errUnitNotFound = "unit not found"
 
+// Values of TELEMETRY_SPEED_CEILING_MODE
+const (
+	speedCeilingReject = "reject"
+	speedCeilingClamp  = "clamp"
+)
+
 type CreateUnitRequest struct {
 	CallSign     string  `json:"call_sign" validate:"required"`
 	UnitTypeCode string  `json:"unit_type_code" validate:"required"`
 	LocationID   *string `json:"location_id"`
-	Status       string  `json:"status" validate:"required,oneof=available available_hidden under_way on_site unavailable offline"`
+	Status       string  `json:"status" validate:"required,unit_status"`
 	Latitude     float64 `json:"latitude" validate:"required,latitude"`
 	Longitude    float64 `json:"longitude" validate:"required,longitude"`
 }
 
 type UpdateUnitStatusRequest struct {
-	Status string `json:"status" validate:"required,oneof=available available_hidden under_way on_site unavailable offline"`
+	Status string `json:"status" validate:"required,unit_status"`
+	// Version is the unit version the change is based on; an If-Match header takes its place.
+	Version *int32 `json:"version"`
 }
 
 type UpdateUnitLocationRequest struct {
 	Latitude   float64    `json:"latitude" validate:"required,latitude"`
 	Longitude  float64    `json:"longitude" validate:"required,longitude"`
 	RecordedAt *time.Time `json:"recorded_at"`
+	Version    *int32     `json:"version"`
+}
+
+// BatchUnitLocationItem is one entry of a PATCH /v1/units/locations payload.
+type BatchUnitLocationItem struct {
+	UnitID     string     `json:"unit_id" validate:"required"`
+	Latitude   float64    `json:"latitude" validate:"required,latitude"`
+	Longitude  float64    `json:"longitude" validate:"required,longitude"`
+	RecordedAt *time.Time `json:"recorded_at"`
+	// Version is the unit version the entry was based on; a stale one fails the entry with 409.
+	Version *int32 `json:"version"`
+}
+
+// BatchUnitLocationResult reports the outcome for one entry, in request order.
+// Status is the status the entry would have had as a single PATCH. Version is the
+// new unit version on success and the current one on a 409.
+type BatchUnitLocationResult struct {
+	Index   int    `json:"index"`
+	UnitID  string `json:"unit_id"`
+	Success bool   `json:"success"`
+	Status  int    `json:"status"`
+	Version *int32 `json:"version,omitempty"`
+	Error   string `json:"error,omitempty"`
 }
 
+// maxBatchUnitLocations bounds the entries accepted by PATCH /v1/units/locations.
+const maxBatchUnitLocations = 500
+
 type UpdateUnitStationRequest struct {
 	LocationID *string `json:"location_id"`
+	Version    *int32  `json:"version"`
 }
 
 type AssignMicrobitRequest struct {
@@ -50,15 +88,32 @@ type UnitTelemetryRequest struct {
 	Status    RawJSON  `json:"status_snapshot"`
 }
 
+// UnitCheckinRequest combines status, position and telemetry sent by a device in one message.
+type UnitCheckinRequest struct {
+	Status     string     `json:"status" validate:"required,unit_status"`
+	Latitude   float64    `json:"latitude" validate:"required,latitude"`
+	Longitude  float64    `json:"longitude" validate:"required,longitude"`
+	RecordedAt *time.Time `json:"recorded_at"`
+	Heading    *int32     `json:"heading"`
+	SpeedKMH   *float64   `json:"speed_kmh" validate:"omitempty,gte=0"`
+	Snapshot   RawJSON    `json:"status_snapshot"`
+}
+
 // handleListUnits godoc
 // @Title List units
 // @Description Returns all operational units with their current status and location.
 // @Resource Units
 // @Produce json
+// @Param srid query int false "Output coordinate system" default(4326)
 // @Success 200 {array} UnitResponse
+// @Failure 400 {object} APIError
 // @Failure 500 {object} APIError
 // @Route /v1/units [get]
 func (s *Server) handleListUnits(w http.ResponseWriter, r *http.Request) {
+	srid, ok := s.parseSRIDParam(w, r)
+	if !ok {
+		return
+	}
 	rows, err := s.queries.ListUnits(r.Context())
 	if err != nil {
 		s.writeError(w, http.StatusInternalServerError, "failed to list units", err.Error())
@@ -80,9 +135,14 @@ func (s *Server) handleListUnits(w http.ResponseWriter, r *http.Request) {
 			LastContact:  row.LastContactAt,
 			CreatedAt:    row.CreatedAt,
 			UpdatedAt:    row.UpdatedAt,
+			Version:      row.Version,
 		}))
 	}
 
+	if err := s.reprojectPoints(r.Context(), srid, unitPoints(resp)); err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to reproject coordinates", err.Error())
+		return
+	}
 	s.writeJSON(w, http.StatusOK, resp)
 }
 
@@ -145,6 +205,7 @@ func (s *Server) handleCreateUnit(w http.ResponseWriter, r *http.Request) {
 		s.writeError(w, http.StatusInternalServerError, "failed to create unit", err.Error())
 		return
 	}
+	s.invalidateStaticData()
 
 	summary := mapCreateUnitRow(row)
 	s.writeJSON(w, http.StatusCreated, summary)
@@ -189,13 +250,130 @@ func (s *Server) handleDeleteUnit(w http.ResponseWriter, r *http.Request) {
 		s.writeError(w, http.StatusInternalServerError, "failed to delete unit", err.Error())
 		return
 	}
+	s.invalidateStaticData()
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// unitStatusTransitions lists the statuses each unit status may change to. Every status
+// change goes through changeUnitStatus, which enforces it. unavailable and offline are reachable from any status
+// and keeping the current status is always allowed; see isAllowedUnitTransition.
+var unitStatusTransitions = map[db.UnitStatus][]db.UnitStatus{
+	db.UnitStatusAvailable:       {db.UnitStatusAvailableHidden, db.UnitStatusUnderWay, db.UnitStatusMaintenance},
+	db.UnitStatusAvailableHidden: {db.UnitStatusAvailable, db.UnitStatusUnderWay, db.UnitStatusMaintenance},
+	db.UnitStatusUnderWay:        {db.UnitStatusOnSite, db.UnitStatusAvailable, db.UnitStatusAvailableHidden, db.UnitStatusReturning},
+	db.UnitStatusOnSite:          {db.UnitStatusAvailable, db.UnitStatusAvailableHidden, db.UnitStatusReturning, db.UnitStatusUnderWay},
+	db.UnitStatusReturning:       {db.UnitStatusAvailable, db.UnitStatusAvailableHidden, db.UnitStatusUnderWay},
+	db.UnitStatusUnavailable:     {db.UnitStatusAvailable, db.UnitStatusAvailableHidden, db.UnitStatusMaintenance},
+	db.UnitStatusOffline:         {db.UnitStatusAvailable, db.UnitStatusAvailableHidden, db.UnitStatusMaintenance},
+	db.UnitStatusMaintenance:     {db.UnitStatusAvailable, db.UnitStatusAvailableHidden},
+}
+
+// isAllowedUnitTransition reports whether a unit may change from one status to another.
+func isAllowedUnitTransition(from, to db.UnitStatus) bool {
+	if from == to || to == db.UnitStatusUnavailable || to == db.UnitStatusOffline {
+		return true
+	}
+	for _, allowed := range unitStatusTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// unitTransitionError is returned by changeUnitStatus for a change outside unitStatusTransitions.
+type unitTransitionError struct {
+	from, to db.UnitStatus
+}
+
+func (e *unitTransitionError) Error() string {
+	return fmt.Sprintf("unit status cannot change from %s to %s", e.from, e.to)
+}
+
+// unitVersionError is returned by changeUnitStatus when the unit changed since the client read it.
+type unitVersionError struct {
+	current, expected int32
+}
+
+func (e *unitVersionError) Error() string {
+	return fmt.Sprintf("unit version is %d, expected %d", e.current, e.expected)
+}
+
+// changeUnitStatus is the single path for unit status changes. Within the caller's transaction
+// it locks the unit, checks the expected version (when set) and unitStatusTransitions, then
+// writes the new status. It returns the unit as it was before the change and the updated row.
+func changeUnitStatus(ctx context.Context, qtx *db.Queries, unitID pgtype.UUID, to db.UnitStatus, expected *int32) (db.GetUnitRow, db.UpdateUnitStatusRow, error) {
+	if _, err := qtx.LockUnit(ctx, unitID); err != nil {
+		return db.GetUnitRow{}, db.UpdateUnitStatusRow{}, err
+	}
+	current, err := qtx.GetUnit(ctx, unitID)
+	if err != nil {
+		return db.GetUnitRow{}, db.UpdateUnitStatusRow{}, fmt.Errorf("fetch unit: %w", err)
+	}
+	if expected != nil && *expected != current.Version {
+		return current, db.UpdateUnitStatusRow{}, &unitVersionError{current: current.Version, expected: *expected}
+	}
+	if !isAllowedUnitTransition(current.Status, to) {
+		return current, db.UpdateUnitStatusRow{}, &unitTransitionError{from: current.Status, to: to}
+	}
+	row, err := qtx.UpdateUnitStatus(ctx, db.UpdateUnitStatusParams{
+		ID:              unitID,
+		Status:          to,
+		ExpectedVersion: expected,
+	})
+	if err != nil {
+		return current, db.UpdateUnitStatusRow{}, fmt.Errorf("update unit status: %w", err)
+	}
+	return current, row, nil
+}
+
+// setUnitStatus runs changeUnitStatus in its own transaction and logs the change once it is committed.
+func (s *Server) setUnitStatus(ctx context.Context, unitID pgtype.UUID, to db.UnitStatus) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("start transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	current, _, err := changeUnitStatus(ctx, s.queries.WithTx(tx), unitID, to, nil)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit unit status: %w", err)
+	}
+	if current.Status != to {
+		s.invalidateStaticData()
+		if logErr := s.logUnitStatusChange(ctx, unitID, current.CallSign, string(current.Status), string(to), actorFromContext(ctx)); logErr != nil {
+			s.log.Error().Err(logErr).Msg("failed to log unit status change")
+		}
+	}
+	return nil
+}
+
+// writeUnitStatusError writes the 404, 409 or 500 matching an error from changeUnitStatus.
+func (s *Server) writeUnitStatusError(w http.ResponseWriter, err error) {
+	var transitionErr *unitTransitionError
+	var versionErr *unitVersionError
+	switch {
+	case isNotFound(err):
+		s.writeError(w, http.StatusNotFound, errUnitNotFound, nil)
+	case errors.As(err, &versionErr):
+		s.writeVersionConflict(w, versionErr.current, versionErr.expected)
+	case errors.As(err, &transitionErr):
+		s.writeError(w, http.StatusConflict, transitionErr.Error(), map[string]string{
+			"current_status":   string(transitionErr.from),
+			"requested_status": string(transitionErr.to),
+		})
+	default:
+		s.writeError(w, http.StatusInternalServerError, "failed to update unit status", err.Error())
+	}
+}
+
 // handleUpdateUnitStatus godoc
 // @Title Update unit status
-// @Description Updates the dispatch readiness of a unit.
+// @Description Updates the dispatch readiness of a unit. Changes outside unitStatusTransitions are rejected with 409, as are changes based on a stale version (If-Match header or version field).
 // @Resource Units
 // @Accept json
 // @Produce json
@@ -204,6 +382,7 @@ func (s *Server) handleDeleteUnit(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} UnitResponse
 // @Failure 400 {object} APIError
 // @Failure 404 {object} APIError
+// @Failure 409 {object} APIError
 // @Failure 500 {object} APIError
 // @Route /v1/units/{unitID}/status [patch]
 func (s *Server) handleUpdateUnitStatus(w http.ResponseWriter, r *http.Request) {
@@ -226,51 +405,45 @@ func (s *Server) handleUpdateUnitStatus(w http.ResponseWriter, r *http.Request)
 		s.writeError(w, http.StatusBadRequest, errInvalidPayload, err.Error())
 		return
 	}
+	expected, ok := s.expectedVersion(w, r, req.Version)
+	if !ok {
+		return
+	}
 
-	// Fetch current unit to get old status for logging
-	currentUnit, err := s.queries.GetUnit(r.Context(), unitID)
+	ctx := r.Context()
+	tx, err := s.pool.Begin(ctx)
 	if err != nil {
-		if isNotFound(err) {
-			s.writeError(w, http.StatusNotFound, errUnitNotFound, nil)
-			return
-		}
-		s.writeError(w, http.StatusInternalServerError, "failed to fetch unit", err.Error())
+		s.writeError(w, http.StatusInternalServerError, "failed to begin transaction", err.Error())
 		return
 	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	qtx := s.queries.WithTx(tx)
 
+	currentUnit, row, err := changeUnitStatus(ctx, qtx, unitID, db.UnitStatus(req.Status), expected)
+	if err != nil {
+		s.writeUnitStatusError(w, err)
+		return
+	}
 	oldStatus := string(currentUnit.Status)
 	newStatus := req.Status
 
-	row, err := s.queries.UpdateUnitStatus(r.Context(), db.UpdateUnitStatusParams{
-		ID:     unitID,
-		Status: db.UnitStatus(newStatus),
-	})
-	if err != nil {
-		if isNotFound(err) {
-			s.writeError(w, http.StatusNotFound, errUnitNotFound, nil)
-			return
-		}
-		s.writeError(w, http.StatusInternalServerError, "failed to update unit", err.Error())
+	if err := tx.Commit(ctx); err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to commit unit status", err.Error())
 		return
 	}
+	s.invalidateStaticData()
 
 	// Log the status change if it actually changed
 	if oldStatus != newStatus {
-		if logErr := s.logUnitStatusChange(r.Context(), unitID, currentUnit.CallSign, oldStatus, newStatus, nil); logErr != nil {
+		if logErr := s.logUnitStatusChange(ctx, unitID, currentUnit.CallSign, oldStatus, newStatus, actorFromContext(ctx)); logErr != nil {
 			s.log.Error().Err(logErr).Msg("failed to log unit status change")
 			// Don't fail the request if logging fails
 		}
 	}
 
-	// Manage routes based on status change
-	if req.Status == "available" {
-		// Unit is returning to station -> calculate return route
-		go s.calculateAndSaveRouteToStation(context.Background(), unitID)
-	} else if req.Status != "under_way" {
-		// Delete route when unit arrives on_site, at station (available_hidden), or goes offline
-		_ = s.queries.DeleteUnitRoute(r.Context(), unitID) // Ignore error
-	}
+	s.updateRouteForStatus(r.Context(), unitID, req.Status)
 
+	setVersionETag(w, row.Version)
 	s.writeJSON(w, http.StatusOK, mapUnitRow(unitRowData{
 		ID:           row.ID,
 		CallSign:     row.CallSign,
@@ -284,12 +457,44 @@ func (s *Server) handleUpdateUnitStatus(w http.ResponseWriter, r *http.Request)
 		LastContact:  row.LastContactAt,
 		CreatedAt:    row.CreatedAt,
 		UpdatedAt:    row.UpdatedAt,
+		Version:      row.Version,
 	}))
 }
 
+// updateRouteForStatus manages the stored route of a unit after a status change.
+func (s *Server) updateRouteForStatus(ctx context.Context, unitID pgtype.UUID, status string) {
+	if status == "available" {
+		// Unit is returning to station -> calculate return route
+		go s.calculateAndSaveRouteToStation(context.Background(), unitID)
+	} else if status != "under_way" {
+		// Delete route when unit arrives on_site, at station (available_hidden), or goes offline
+		s.archiveUnitRoute(ctx, unitID)
+		_ = s.queries.DeleteUnitRoute(ctx, unitID) // Ignore error
+	}
+}
+
+// writeUnitUpdateNoRows answers a unit update that matched no row: the unit is either
+// missing or, when a version was expected, changed since the client read it.
+func (s *Server) writeUnitUpdateNoRows(w http.ResponseWriter, r *http.Request, unitID pgtype.UUID, expected *int32) {
+	if expected == nil {
+		s.writeError(w, http.StatusNotFound, errUnitNotFound, nil)
+		return
+	}
+	unit, err := s.queries.GetUnit(r.Context(), unitID)
+	if err != nil {
+		if isNotFound(err) {
+			s.writeError(w, http.StatusNotFound, errUnitNotFound, nil)
+			return
+		}
+		s.writeError(w, http.StatusInternalServerError, "failed to fetch unit", err.Error())
+		return
+	}
+	s.writeVersionConflict(w, unit.Version, *expected)
+}
+
 // handleUpdateUnitLocation godoc
 // @Title Update unit location
-// @Description Updates the last known location for a unit.
+// @Description Updates the last known location for a unit. Rejected with 409 when based on a stale version (If-Match header or version field).
 // @Resource Units
 // @Accept json
 // @Produce json
@@ -298,6 +503,7 @@ func (s *Server) handleUpdateUnitStatus(w http.ResponseWriter, r *http.Request)
 // @Success 200 {object} UnitResponse
 // @Failure 400 {object} APIError
 // @Failure 404 {object} APIError
+// @Failure 409 {object} APIError
 // @Failure 500 {object} APIError
 // @Route /v1/units/{unitID}/location [patch]
 func (s *Server) handleUpdateUnitLocation(w http.ResponseWriter, r *http.Request) {
@@ -320,22 +526,33 @@ func (s *Server) handleUpdateUnitLocation(w http.ResponseWriter, r *http.Request
 		s.writeError(w, http.StatusBadRequest, errInvalidPayload, err.Error())
 		return
 	}
+	expected, ok := s.expectedVersion(w, r, req.Version)
+	if !ok {
+		return
+	}
 
 	row, err := s.queries.UpdateUnitLocation(r.Context(), db.UpdateUnitLocationParams{
-		Longitude:   req.Longitude,
-		Latitude:    req.Latitude,
-		ContactTime: timestamptzFromPtr(req.RecordedAt),
-		ID:          unitID,
+		Longitude:       req.Longitude,
+		Latitude:        req.Latitude,
+		ContactTime:     timestamptzFromPtr(req.RecordedAt),
+		ID:              unitID,
+		ExpectedVersion: expected,
 	})
 	if err != nil {
 		if isNotFound(err) {
-			s.writeError(w, http.StatusNotFound, errUnitNotFound, nil)
+			s.writeUnitUpdateNoRows(w, r, unitID, expected)
 			return
 		}
 		s.writeError(w, http.StatusInternalServerError, "failed to update unit location", err.Error())
 		return
 	}
 
+	if s.cfg.Telemetry.SnapToRoute {
+		s.snapLocationToRoute(r.Context(), unitID, req.Longitude, req.Latitude)
+	}
+	s.checkRouteDeviation(r.Context(), unitID, req.Longitude, req.Latitude)
+
+	setVersionETag(w, row.Version)
 	s.writeJSON(w, http.StatusOK, mapUnitRow(unitRowData{
 		ID:           row.ID,
 		CallSign:     row.CallSign,
@@ -349,12 +566,156 @@ func (s *Server) handleUpdateUnitLocation(w http.ResponseWriter, r *http.Request
 		LastContact:  row.LastContactAt,
 		CreatedAt:    row.CreatedAt,
 		UpdatedAt:    row.UpdatedAt,
+		Version:      row.Version,
 	}))
 }
 
+// handleBatchUpdateUnitLocations godoc
+// @Title Batch update unit locations
+// @Description Updates the location of several units in one transaction. Entries with an invalid unit id or coordinates, an unknown unit or a stale version are reported as failed, with the status they would have had on their own, without rejecting the rest of the batch. With HTTP_REQUIRE_IF_MATCH entries need a version unless the request carries If-Match: *.
+// @Resource Units
+// @Accept json
+// @Produce json
+// @Param body body []BatchUnitLocationItem true "Location updates (max 500)"
+// @Success 200 {array} BatchUnitLocationResult
+// @Failure 400 {object} APIError
+// @Route /v1/units/locations [patch]
+func (s *Server) handleBatchUpdateUnitLocations(w http.ResponseWriter, r *http.Request) {
+	if !s.authMw.RequireOneOfRoles(w, r, RoleIT, RoleManageRealm) {
+		return
+	}
+
+	s.lastMicrobitMessage.Store(time.Now())
+
+	var items []BatchUnitLocationItem
+	defer r.Body.Close()
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&items); err != nil {
+		s.writeError(w, http.StatusBadRequest, errInvalidPayload, err.Error())
+		return
+	}
+	if len(items) == 0 || len(items) > maxBatchUnitLocations {
+		s.writeError(w, http.StatusBadRequest, errInvalidPayload, fmt.Sprintf("batch must contain between 1 and %d entries", maxBatchUnitLocations))
+		return
+	}
+	// Entries carry their own version; If-Match: * only marks unversioned entries as unconditional
+	unconditional := false
+	switch strings.TrimSpace(r.Header.Get("If-Match")) {
+	case "":
+	case "*":
+		unconditional = true
+	default:
+		s.writeError(w, http.StatusBadRequest, "invalid If-Match header", "batch entries carry their own version; only * is accepted")
+		return
+	}
+
+	results := make([]BatchUnitLocationResult, len(items))
+	params := make([]db.UpdateUnitLocationsBatchParams, 0, len(items))
+	// queued maps each queued statement back to its index in items
+	queued := make([]int, 0, len(items))
+	for i, item := range items {
+		results[i] = BatchUnitLocationResult{Index: i, UnitID: item.UnitID, Status: http.StatusBadRequest}
+		unitID, err := pgUUIDFromString(item.UnitID)
+		if err != nil {
+			results[i].Error = errInvalidUnitID
+			continue
+		}
+		if err := s.validate.Struct(item); err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		if item.Version == nil && !unconditional && s.cfg.HTTP.RequireIfMatch {
+			results[i].Status = http.StatusPreconditionRequired
+			results[i].Error = "version field required"
+			continue
+		}
+		params = append(params, db.UpdateUnitLocationsBatchParams{
+			Longitude:       item.Longitude,
+			Latitude:        item.Latitude,
+			ContactTime:     timestamptzFromPtr(item.RecordedAt),
+			ID:              unitID,
+			ExpectedVersion: item.Version,
+		})
+		queued = append(queued, i)
+	}
+
+	if len(params) > 0 {
+		ctx := r.Context()
+		tx, err := s.pool.Begin(ctx)
+		if err != nil {
+			s.writeError(w, http.StatusInternalServerError, "failed to begin transaction", err.Error())
+			return
+		}
+		defer func() { _ = tx.Rollback(ctx) }()
+		qtx := s.queries.WithTx(tx)
+
+		var batchErr error
+		// noRows lists the queued statements that matched no row: unknown unit or stale version
+		var noRows []int
+		qtx.UpdateUnitLocationsBatch(ctx, params).QueryRow(func(n int, row db.UpdateUnitLocationsBatchRow, err error) {
+			i := queued[n]
+			switch {
+			case err == nil:
+				results[i].Success = true
+				results[i].Status = http.StatusOK
+				results[i].Version = &row.Version
+			case isNotFound(err):
+				noRows = append(noRows, n)
+			default:
+				if batchErr == nil {
+					batchErr = err
+				}
+				results[i].Status = http.StatusInternalServerError
+				results[i].Error = err.Error()
+			}
+		})
+		if batchErr != nil {
+			s.writeError(w, http.StatusInternalServerError, "failed to update unit locations", batchErr.Error())
+			return
+		}
+		for _, n := range noRows {
+			i := queued[n]
+			results[i].Status = http.StatusNotFound
+			results[i].Error = errUnitNotFound
+			if params[n].ExpectedVersion == nil {
+				continue
+			}
+			unit, err := qtx.GetUnit(ctx, params[n].ID)
+			if err != nil {
+				if !isNotFound(err) {
+					s.writeError(w, http.StatusInternalServerError, "failed to fetch unit", err.Error())
+					return
+				}
+				continue
+			}
+			results[i].Status = http.StatusConflict
+			results[i].Version = &unit.Version
+			results[i].Error = "resource was modified since it was read"
+		}
+		if err := tx.Commit(ctx); err != nil {
+			s.writeError(w, http.StatusInternalServerError, "failed to commit unit locations", err.Error())
+			return
+		}
+
+		for n, i := range queued {
+			if !results[i].Success {
+				continue
+			}
+			p := params[n]
+			if s.cfg.Telemetry.SnapToRoute {
+				s.snapLocationToRoute(ctx, p.ID, p.Longitude, p.Latitude)
+			}
+			s.checkRouteDeviation(ctx, p.ID, p.Longitude, p.Latitude)
+		}
+	}
+
+	s.writeJSON(w, http.StatusOK, results)
+}
+
 // handleUpdateUnitStation godoc
 // @Title Update unit station
-// @Description Updates the fire station (location) assignment for a unit.
+// @Description Updates the fire station (location) assignment for a unit. Rejected with 409 when based on a stale version (If-Match header or version field).
 // @Resource Units
 // @Accept json
 // @Produce json
@@ -363,6 +724,7 @@ func (s *Server) handleUpdateUnitLocation(w http.ResponseWriter, r *http.Request
 // @Success 200 {object} UnitResponse
 // @Failure 400 {object} APIError
 // @Failure 404 {object} APIError
+// @Failure 409 {object} APIError
 // @Failure 500 {object} APIError
 // @Route /v1/units/{unitID}/station [patch]
 func (s *Server) handleUpdateUnitStation(w http.ResponseWriter, r *http.Request) {
@@ -381,20 +743,27 @@ func (s *Server) handleUpdateUnitStation(w http.ResponseWriter, r *http.Request)
 		s.writeError(w, http.StatusBadRequest, errInvalidPayload, err.Error())
 		return
 	}
+	expected, ok := s.expectedVersion(w, r, req.Version)
+	if !ok {
+		return
+	}
 
 	row, err := s.queries.UpdateUnitStation(r.Context(), db.UpdateUnitStationParams{
-		ID:         unitID,
-		LocationID: pgUUIDFromStringOptional(req.LocationID),
+		ID:              unitID,
+		LocationID:      pgUUIDFromStringOptional(req.LocationID),
+		ExpectedVersion: expected,
 	})
 	if err != nil {
 		if isNotFound(err) {
-			s.writeError(w, http.StatusNotFound, errUnitNotFound, nil)
+			s.writeUnitUpdateNoRows(w, r, unitID, expected)
 			return
 		}
 		s.writeError(w, http.StatusInternalServerError, "failed to update unit station", err.Error())
 		return
 	}
+	s.invalidateStaticData()
 
+	setVersionETag(w, row.Version)
 	s.writeJSON(w, http.StatusOK, mapUnitRow(unitRowData{
 		ID:           row.ID,
 		CallSign:     row.CallSign,
@@ -408,6 +777,7 @@ func (s *Server) handleUpdateUnitStation(w http.ResponseWriter, r *http.Request)
 		LastContact:  row.LastContactAt,
 		CreatedAt:    row.CreatedAt,
 		UpdatedAt:    row.UpdatedAt,
+		Version:      row.Version,
 	}))
 }
 
@@ -437,6 +807,16 @@ func (s *Server) handleInsertTelemetry(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	speed, clamped, ok := s.checkTelemetrySpeed(unitID, req.SpeedKMH)
+	if !ok {
+		s.writeError(w, http.StatusBadRequest, errImplausibleSpeed, map[string]float64{
+			"speed_kmh":     *req.SpeedKMH,
+			"max_speed_kmh": s.cfg.Telemetry.MaxSpeedKMH,
+		})
+		return
+	}
+	req.SpeedKMH = speed
+
 	row, err := s.queries.InsertUnitTelemetry(r.Context(), db.InsertUnitTelemetryParams{
 		UnitID:         unitID,
 		Longitude:      req.Longitude,
@@ -458,11 +838,469 @@ func (s *Server) handleInsertTelemetry(w http.ResponseWriter, r *http.Request) {
 		Heading:    row.Heading,
 		SpeedKMH:   row.SpeedKmh,
 		Status:     RawJSON(row.StatusSnapshot),
+
+		SpeedClamped: clamped,
+	}
+
+	if s.cfg.Telemetry.SnapToRoute {
+		s.snapTelemetryToRoute(r.Context(), &resp, unitID)
 	}
 
 	s.writeJSON(w, http.StatusCreated, resp)
 }
 
+// maxTelemetryGaps bounds the number of gaps returned by one request.
+const maxTelemetryGaps = 1000
+
+// handleListUnitTelemetry godoc
+// @Title List unit telemetry
+// @Description Returns the telemetry snapshots of a unit, newest first, to replay its track after an intervention.
+// @Resource Units
+// @Produce json
+// @Param unitID path string true "Unit ID"
+// @Param since query string false "Only snapshots recorded at or after this time (RFC3339)"
+// @Param until query string false "Only snapshots recorded before this time (RFC3339)"
+// @Param limit query int false "Maximum results" default(100)
+// @Param offset query int false "Results offset" default(0)
+// @Param srid query int false "Output coordinate system" default(4326)
+// @Success 200 {array} TelemetryResponse
+// @Failure 400 {object} APIError
+// @Failure 404 {object} APIError
+// @Failure 500 {object} APIError
+// @Route /v1/units/{unitID}/telemetry [get]
+func (s *Server) handleListUnitTelemetry(w http.ResponseWriter, r *http.Request) {
+	unitID, err := s.parseUUIDParam(r, "unitID")
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, errInvalidUnitID, err.Error())
+		return
+	}
+	srid, ok := s.parseSRIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	since, until, ok := s.parseTimeRange(w, r, "since", "until", 0)
+	if !ok {
+		return
+	}
+	limit, offset := s.paginate(r, 100)
+	params := db.ListUnitTelemetryParams{
+		UnitID: unitID,
+		Since:  pgtype.Timestamptz{Time: since, Valid: !since.IsZero()},
+		Until:  pgtype.Timestamptz{Time: until, Valid: !until.IsZero()},
+		Limit:  limit,
+		Offset: offset,
+	}
+
+	if _, err := s.queries.GetUnit(r.Context(), unitID); err != nil {
+		if isNotFound(err) {
+			s.writeError(w, http.StatusNotFound, errUnitNotFound, nil)
+			return
+		}
+		s.writeError(w, http.StatusInternalServerError, "failed to fetch unit", err.Error())
+		return
+	}
+
+	rows, err := s.queries.ListUnitTelemetry(r.Context(), params)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to list telemetry", err.Error())
+		return
+	}
+
+	resp := make([]TelemetryResponse, 0, len(rows))
+	for _, row := range rows {
+		resp = append(resp, TelemetryResponse{
+			ID:         row.ID,
+			UnitID:     uuidString(row.UnitID),
+			RecordedAt: row.RecordedAt.Time,
+			Location:   GeoPoint{Latitude: row.Latitude, Longitude: row.Longitude},
+			Heading:    row.Heading,
+			SpeedKMH:   row.SpeedKmh,
+			Status:     RawJSON(row.StatusSnapshot),
+		})
+	}
+
+	points := make([]*GeoPoint, 0, len(resp))
+	for i := range resp {
+		points = append(points, &resp[i].Location)
+	}
+	if err := s.reprojectPoints(r.Context(), srid, points); err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to reproject coordinates", err.Error())
+		return
+	}
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
+// maxTrackPoints bounds the points listed in the MultiPoint feature of a unit track.
+const maxTrackPoints = 10000
+
+// GeoJSONFeatureCollection is a minimal GeoJSON FeatureCollection
+type GeoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []GeoJSONFeature `json:"features"`
+}
+
+// GeoJSONFeature is a GeoJSON Feature; a nil Geometry encodes as null
+type GeoJSONFeature struct {
+	Type       string          `json:"type"`
+	Geometry   json.RawMessage `json:"geometry"`
+	Properties map[string]any  `json:"properties"`
+}
+
+// handleGetUnitTrack godoc
+// @Title Unit track as GeoJSON
+// @Description Returns the telemetry track of a unit between since and until as a GeoJSON FeatureCollection: a LineString of the positions in time order, and a MultiPoint of the same positions whose recorded_at, speed_kmh and heading properties are arrays aligned with its coordinates.
+// @Resource Units
+// @Produce json
+// @Param unitID path string true "Unit ID"
+// @Param since query string false "Start (RFC3339), defaults to 24h before until"
+// @Param until query string false "End (RFC3339, exclusive), defaults to now"
+// @Success 200 {object} GeoJSONFeatureCollection
+// @Failure 400 {object} APIError
+// @Failure 404 {object} APIError
+// @Failure 500 {object} APIError
+// @Route /v1/units/{unitID}/track.geojson [get]
+func (s *Server) handleGetUnitTrack(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	unitID, err := s.parseUUIDParam(r, "unitID")
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, errInvalidUnitID, err.Error())
+		return
+	}
+
+	since, until, ok := s.parseTimeRange(w, r, "since", "until", 24*time.Hour)
+	if !ok {
+		return
+	}
+
+	unit, err := s.queries.GetUnit(ctx, unitID)
+	if err != nil {
+		if isNotFound(err) {
+			s.writeError(w, http.StatusNotFound, errUnitNotFound, nil)
+			return
+		}
+		s.writeError(w, http.StatusInternalServerError, "failed to fetch unit", err.Error())
+		return
+	}
+
+	sinceTS := pgtype.Timestamptz{Time: since, Valid: true}
+	untilTS := pgtype.Timestamptz{Time: until, Valid: true}
+	line, err := s.queries.GetUnitTrackLine(ctx, db.GetUnitTrackLineParams{UnitID: unitID, Since: sinceTS, Until: untilTS})
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to build track", err.Error())
+		return
+	}
+	points, err := s.queries.ListUnitTrackPoints(ctx, db.ListUnitTrackPointsParams{UnitID: unitID, Since: sinceTS, Until: untilTS, Limit: maxTrackPoints})
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to list track points", err.Error())
+		return
+	}
+
+	coordinates := make([][2]float64, 0, len(points))
+	recordedAt := make([]time.Time, 0, len(points))
+	speeds := make([]*float64, 0, len(points))
+	headings := make([]*int32, 0, len(points))
+	for _, p := range points {
+		coordinates = append(coordinates, [2]float64{p.Longitude, p.Latitude})
+		recordedAt = append(recordedAt, p.RecordedAt.Time)
+		speeds = append(speeds, p.SpeedKmh)
+		headings = append(headings, p.Heading)
+	}
+	var multiPoint json.RawMessage
+	if len(coordinates) > 0 {
+		multiPoint, err = json.Marshal(map[string]any{"type": "MultiPoint", "coordinates": coordinates})
+		if err != nil {
+			s.writeError(w, http.StatusInternalServerError, "failed to encode track points", err.Error())
+			return
+		}
+	}
+	var lineString json.RawMessage
+	if line.LineGeojson != nil {
+		lineString = json.RawMessage(*line.LineGeojson)
+	}
+
+	w.Header().Set("Content-Type", "application/geo+json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(GeoJSONFeatureCollection{
+		Type: "FeatureCollection",
+		Features: []GeoJSONFeature{
+			{
+				Type:     "Feature",
+				Geometry: lineString,
+				Properties: map[string]any{
+					"unit_id":     uuidString(unitID),
+					"call_sign":   unit.CallSign,
+					"since":       since,
+					"until":       until,
+					"point_count": line.PointCount,
+				},
+			},
+			{
+				Type:     "Feature",
+				Geometry: multiPoint,
+				Properties: map[string]any{
+					"recorded_at": recordedAt,
+					"speed_kmh":   speeds,
+					"heading":     headings,
+					"truncated":   line.PointCount > int64(len(points)),
+				},
+			},
+		},
+	})
+}
+
+// handleListTelemetryGaps godoc
+// @Title List telemetry gaps
+// @Description Returns the intervals longer than threshold during which the unit sent no telemetry. Silences at the start and end of the range are included.
+// @Resource Units
+// @Produce json
+// @Param unitID path string true "Unit ID"
+// @Param threshold query string false "Minimum gap as a Go duration" default(60s)
+// @Param from query string false "Start (RFC3339), defaults to 24h before to"
+// @Param to query string false "End (RFC3339, exclusive), defaults to now"
+// @Success 200 {object} TelemetryGapsResponse
+// @Failure 400 {object} APIError
+// @Failure 403 {object} APIError
+// @Failure 404 {object} APIError
+// @Failure 500 {object} APIError
+// @Route /v1/units/{unitID}/telemetry/gaps [get]
+func (s *Server) handleListTelemetryGaps(w http.ResponseWriter, r *http.Request) {
+	if !s.authMw.RequireOneOfRoles(w, r, RoleIT) {
+		return
+	}
+
+	unitID, err := s.parseUUIDParam(r, "unitID")
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, errInvalidUnitID, err.Error())
+		return
+	}
+
+	query := r.URL.Query()
+	threshold := time.Minute
+	if raw := query.Get("threshold"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil || parsed <= 0 {
+			s.writeError(w, http.StatusBadRequest, "invalid threshold", "threshold must be a positive duration")
+			return
+		}
+		threshold = parsed
+	}
+	from, to, ok := s.parseTimeRange(w, r, "from", "to", 24*time.Hour)
+	if !ok {
+		return
+	}
+
+	if _, err := s.queries.GetUnit(r.Context(), unitID); err != nil {
+		if isNotFound(err) {
+			s.writeError(w, http.StatusNotFound, errUnitNotFound, nil)
+			return
+		}
+		s.writeError(w, http.StatusInternalServerError, "failed to fetch unit", err.Error())
+		return
+	}
+
+	rows, err := s.queries.ListUnitTelemetryGaps(r.Context(), db.ListUnitTelemetryGapsParams{
+		UnitID:    unitID,
+		FromTime:  pgtype.Timestamptz{Time: from, Valid: true},
+		ToTime:    pgtype.Timestamptz{Time: to, Valid: true},
+		Threshold: pgtype.Interval{Microseconds: threshold.Microseconds(), Valid: true},
+		MaxGaps:   maxTelemetryGaps,
+	})
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to compute telemetry gaps", err.Error())
+		return
+	}
+
+	resp := TelemetryGapsResponse{
+		UnitID:    uuidString(unitID),
+		From:      from,
+		To:        to,
+		Threshold: threshold.String(),
+		Gaps:      make([]TelemetryGap, 0, len(rows)),
+		Truncated: len(rows) == maxTelemetryGaps,
+	}
+	for _, row := range rows {
+		resp.Gaps = append(resp.Gaps, TelemetryGap{
+			Start:           row.GapStart.Time.UTC(),
+			End:             row.GapEnd.Time.UTC(),
+			DurationSeconds: row.GapSeconds,
+		})
+		resp.TotalGapSeconds += row.GapSeconds
+	}
+
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
+// snapTelemetryToRoute projects the telemetry position onto the unit's active route,
+// advancing the route progress, when it lies within TELEMETRY_SNAP_MAX_DISTANCE_METERS.
+// Snapping is best effort: failures are logged and the raw telemetry is kept.
+func (s *Server) snapTelemetryToRoute(ctx context.Context, resp *TelemetryResponse, unitID pgtype.UUID) {
+	snap, err := s.queries.SnapPositionToUnitRoute(ctx, db.SnapPositionToUnitRouteParams{
+		Longitude:         resp.Location.Longitude,
+		Latitude:          resp.Location.Latitude,
+		UnitID:            unitID,
+		MaxDistanceMeters: s.cfg.Telemetry.SnapMaxDistanceMeters,
+	})
+	if err != nil {
+		if !isNotFound(err) {
+			s.log.Warn().Err(err).Str("unit_id", uuidString(unitID)).Msg("failed to snap telemetry to route")
+		}
+		return
+	}
+	resp.SnappedLocation = &GeoPoint{Latitude: snap.SnappedLat, Longitude: snap.SnappedLon}
+	resp.RouteProgressPercent = &snap.ProgressPercent
+}
+
+// snapLocationToRoute advances the unit's route progress to the projection of a location
+// update, when it lies within TELEMETRY_SNAP_MAX_DISTANCE_METERS of the route.
+// Like snapTelemetryToRoute it is best effort and never fails the update.
+func (s *Server) snapLocationToRoute(ctx context.Context, unitID pgtype.UUID, lon, lat float64) {
+	if _, err := s.queries.SnapPositionToUnitRoute(ctx, db.SnapPositionToUnitRouteParams{
+		Longitude:         lon,
+		Latitude:          lat,
+		UnitID:            unitID,
+		MaxDistanceMeters: s.cfg.Telemetry.SnapMaxDistanceMeters,
+	}); err != nil && !isNotFound(err) {
+		s.log.Warn().Err(err).Str("unit_id", uuidString(unitID)).Msg("failed to snap location to route")
+	}
+}
+
+// handleUnitCheckin godoc
+// @Title Unit check-in
+// @Description Updates status and location and stores a telemetry snapshot in a single transaction. Status changes outside unitStatusTransitions are rejected with 409.
+// @Resource Units
+// @Accept json
+// @Produce json
+// @Param unitID path string true "Unit ID"
+// @Param request body UnitCheckinRequest true "Check-in payload"
+// @Success 200 {object} UnitResponse
+// @Failure 400 {object} APIError
+// @Failure 404 {object} APIError
+// @Failure 409 {object} APIError
+// @Failure 500 {object} APIError
+// @Route /v1/units/{unitID}/checkin [post]
+func (s *Server) handleUnitCheckin(w http.ResponseWriter, r *http.Request) {
+	// Require 'it' or 'manage-realm'
+	if !s.authMw.RequireOneOfRoles(w, r, RoleIT, RoleManageRealm) {
+		return
+	}
+
+	// Track microbit network activity
+	s.lastMicrobitMessage.Store(time.Now())
+
+	ctx := r.Context()
+	unitID, err := s.parseUUIDParam(r, "unitID")
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, errInvalidUnitID, err.Error())
+		return
+	}
+
+	var req UnitCheckinRequest
+	if err := s.decodeAndValidate(r, &req); err != nil {
+		s.writeError(w, http.StatusBadRequest, errInvalidPayload, err.Error())
+		return
+	}
+
+	speed, _, ok := s.checkTelemetrySpeed(unitID, req.SpeedKMH)
+	if !ok {
+		s.writeError(w, http.StatusBadRequest, errImplausibleSpeed, map[string]float64{
+			"speed_kmh":     *req.SpeedKMH,
+			"max_speed_kmh": s.cfg.Telemetry.MaxSpeedKMH,
+		})
+		return
+	}
+	req.SpeedKMH = speed
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to begin transaction", err.Error())
+		return
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	qtx := s.queries.WithTx(tx)
+
+	currentUnit, _, err := changeUnitStatus(ctx, qtx, unitID, db.UnitStatus(req.Status), nil)
+	if err != nil {
+		s.writeUnitStatusError(w, err)
+		return
+	}
+
+	row, err := qtx.UpdateUnitLocation(ctx, db.UpdateUnitLocationParams{
+		Longitude:   req.Longitude,
+		Latitude:    req.Latitude,
+		ContactTime: timestamptzFromPtr(req.RecordedAt),
+		ID:          unitID,
+	})
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to update unit location", err.Error())
+		return
+	}
+
+	if _, err := qtx.InsertUnitTelemetry(ctx, db.InsertUnitTelemetryParams{
+		UnitID:         unitID,
+		Longitude:      req.Longitude,
+		Latitude:       req.Latitude,
+		Heading:        req.Heading,
+		SpeedKmh:       req.SpeedKMH,
+		StatusSnapshot: rawJSONOrEmpty(req.Snapshot),
+	}); err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to store telemetry", err.Error())
+		return
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to commit check-in", err.Error())
+		return
+	}
+
+	oldStatus := string(currentUnit.Status)
+	if oldStatus != req.Status {
+		s.invalidateStaticData()
+		if logErr := s.logUnitStatusChange(ctx, unitID, currentUnit.CallSign, oldStatus, req.Status, actorFromContext(ctx)); logErr != nil {
+			s.log.Error().Err(logErr).Msg("failed to log unit status change")
+		}
+		s.updateRouteForStatus(ctx, unitID, req.Status)
+	}
+
+	s.writeJSON(w, http.StatusOK, mapUnitRow(unitRowData{
+		ID:           row.ID,
+		CallSign:     row.CallSign,
+		UnitTypeCode: row.UnitTypeCode,
+		HomeBaseName: &row.HomeBaseName,
+		LocationID:   row.LocationID,
+		Status:       row.Status,
+		MicrobitID:   row.MicrobitID,
+		Longitude:    row.Longitude,
+		Latitude:     row.Latitude,
+		LastContact:  row.LastContactAt,
+		CreatedAt:    row.CreatedAt,
+		UpdatedAt:    row.UpdatedAt,
+	}))
+}
+
+const errImplausibleSpeed = "implausible speed_kmh"
+
+// checkTelemetrySpeed applies the TELEMETRY_MAX_SPEED_KMH ceiling. It returns the
+// speed to store, whether it was clamped, and false when the reading must be rejected.
+func (s *Server) checkTelemetrySpeed(unitID pgtype.UUID, speed *float64) (*float64, bool, bool) {
+	ceiling := s.cfg.Telemetry.MaxSpeedKMH
+	if speed == nil || ceiling <= 0 || *speed <= ceiling {
+		return speed, false, true
+	}
+
+	s.log.Warn().
+		Str("unit_id", uuidString(unitID)).
+		Float64("speed_kmh", *speed).
+		Float64("max_speed_kmh", ceiling).
+		Str("mode", s.cfg.Telemetry.SpeedCeilingMode).
+		Msg("implausible telemetry speed")
+
+	if s.cfg.Telemetry.SpeedCeilingMode == speedCeilingClamp {
+		return &ceiling, true, true
+	}
+	return nil, false, false
+}
+
 type unitRowData struct {
 	ID             pgtype.UUID
 	CallSign       string
@@ -477,6 +1315,7 @@ type unitRowData struct {
 	LastContact    pgtype.Timestamptz
 	CreatedAt      pgtype.Timestamptz
 	UpdatedAt      pgtype.Timestamptz
+	Version        int32
 }
 
 func mapUnitRow(data unitRowData) UnitResponse {
@@ -493,9 +1332,19 @@ func mapUnitRow(data unitRowData) UnitResponse {
 		LastContact:    timestamptzPtr(data.LastContact),
 		CreatedAt:      data.CreatedAt.Time,
 		UpdatedAt:      data.UpdatedAt.Time,
+		Version:        data.Version,
 	}
 }
 
+// unitPoints collects unit locations for reprojection.
+func unitPoints(units []UnitResponse) []*GeoPoint {
+	points := make([]*GeoPoint, 0, len(units))
+	for i := range units {
+		points = append(points, &units[i].Location)
+	}
+	return points
+}
+
 func mapCreateUnitRow(row db.CreateUnitRow) UnitResponse {
 	return UnitResponse{
 		ID:           uuidString(row.ID),
@@ -670,19 +1519,50 @@ func (s *Server) handleGetUnitByMicrobit(w http.ResponseWriter, r *http.Request)
 	}))
 }
 
+// Kinds of distance reported by /v1/units/nearby.
+const (
+	distanceTypeRoad         = "road"
+	distanceTypeStraightLine = "straight_line"
+)
+
+// NearbyUnitResponse is a unit returned by /v1/units/nearby with its distance to the
+// requested point. DistanceType tells whether DistanceMeters follows the road network
+// or is the straight-line distance.
+type NearbyUnitResponse struct {
+	UnitResponse
+	DistanceMeters           float64  `json:"distance_meters"`
+	DistanceType             string   `json:"distance_type"`
+	EstimatedDurationSeconds *float64 `json:"estimated_duration_seconds,omitempty"`
+}
+
+// Bounds of the radius_m parameter of /v1/units/nearby.
+const (
+	defaultNearbyRadiusMeters = 5000
+	maxNearbyRadiusMeters     = 50000
+)
+
 // handleListUnitsNearby godoc
 // @Title List available units nearby
-// @Description Returns available units sorted by distance to a given location.
+// @Description Returns units within radius_m of a given location, sorted by straight-line distance. Only available units are returned unless status is given. With distance=road, the road distance and travel time are computed for the closest units (up to ROUTING_NEAREST_MAX_UNITS); units beyond that or without a route keep the straight-line distance, flagged by distance_type.
 // @Resource Units
 // @Produce json
 // @Param lat query number true "Latitude"
 // @Param lon query number true "Longitude"
+// @Param radius_m query number false "Search radius in meters, at most 50000" default(5000)
+// @Param unit_type_code query string false "Unit type code, repeatable"
 // @Param unit_types query string false "Comma-separated unit type codes"
-// @Success 200 {array} UnitResponse
+// @Param status query string false "Unit status, repeatable" default(available)
+// @Param distance query string false "straight_line or road" default(straight_line)
+// @Param srid query int false "Output coordinate system" default(4326)
+// @Success 200 {array} NearbyUnitResponse
 // @Failure 400 {object} APIError
 // @Failure 500 {object} APIError
 // @Route /v1/units/nearby [get]
 func (s *Server) handleListUnitsNearby(w http.ResponseWriter, r *http.Request) {
+	srid, ok := s.parseSRIDParam(w, r)
+	if !ok {
+		return
+	}
 	latStr := r.URL.Query().Get("lat")
 	lonStr := r.URL.Query().Get("lon")
 
@@ -703,15 +1583,58 @@ func (s *Server) handleListUnitsNearby(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		s.writeError(w, http.StatusBadRequest, "latitude or longitude out of range", nil)
+		return
+	}
+
+	radius := float64(defaultNearbyRadiusMeters)
+	if raw := r.URL.Query().Get("radius_m"); raw != "" {
+		radius, err = strconv.ParseFloat(raw, 64)
+		if err != nil || radius <= 0 || radius > maxNearbyRadiusMeters {
+			s.writeError(w, http.StatusBadRequest, "invalid radius_m", fmt.Sprintf("radius_m must be a number of meters in (0, %d]", maxNearbyRadiusMeters))
+			return
+		}
+	}
+
+	distanceType := r.URL.Query().Get("distance")
+	switch distanceType {
+	case "":
+		distanceType = distanceTypeStraightLine
+	case distanceTypeStraightLine, distanceTypeRoad:
+	default:
+		s.writeError(w, http.StatusBadRequest, "invalid distance", "distance must be straight_line or road")
+		return
+	}
+
 	var unitTypes []string
 	if ut := r.URL.Query().Get("unit_types"); ut != "" {
 		unitTypes = strings.Split(ut, ",")
 	}
+	for _, code := range r.URL.Query()["unit_type_code"] {
+		if code = strings.TrimSpace(code); code != "" {
+			unitTypes = append(unitTypes, code)
+		}
+	}
+
+	var statuses []string
+	for _, status := range r.URL.Query()["status"] {
+		if status = strings.TrimSpace(status); status == "" {
+			continue
+		}
+		if err := s.validate.Var(status, "unit_status"); err != nil {
+			s.writeError(w, http.StatusBadRequest, "invalid status", status)
+			return
+		}
+		statuses = append(statuses, status)
+	}
 
 	params := db.ListAvailableUnitsNearbyParams{
-		Latitude:  lat,
-		Longitude: lon,
-		UnitTypes: unitTypes,
+		Latitude:     lat,
+		Longitude:    lon,
+		UnitTypes:    unitTypes,
+		Statuses:     statuses,
+		RadiusMeters: &radius,
 	}
 
 	rows, err := s.queries.ListAvailableUnitsNearby(r.Context(), params)
@@ -720,24 +1643,214 @@ func (s *Server) handleListUnitsNearby(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp := make([]UnitResponse, 0, len(rows))
+	resp := make([]NearbyUnitResponse, 0, len(rows))
 	for _, row := range rows {
-		dist := row.Distance
-		resp = append(resp, mapUnitRow(unitRowData{
-			ID:             row.ID,
-			CallSign:       row.CallSign,
-			UnitTypeCode:   row.UnitTypeCode,
-			HomeBaseName:   row.HomeBaseName,
-			LocationID:     row.LocationID,
-			Status:         row.Status,
-			MicrobitID:     row.MicrobitID,
-			Longitude:      row.Longitude,
-			Latitude:       row.Latitude,
-			DistanceMeters: &dist,
-			LastContact:    row.LastContactAt,
-			CreatedAt:      row.CreatedAt,
-			UpdatedAt:      row.UpdatedAt,
-		}))
+		resp = append(resp, NearbyUnitResponse{
+			UnitResponse: mapUnitRow(unitRowData{
+				ID:           row.ID,
+				CallSign:     row.CallSign,
+				UnitTypeCode: row.UnitTypeCode,
+				HomeBaseName: row.HomeBaseName,
+				LocationID:   row.LocationID,
+				Status:       row.Status,
+				MicrobitID:   row.MicrobitID,
+				Longitude:    row.Longitude,
+				Latitude:     row.Latitude,
+				LastContact:  row.LastContactAt,
+				CreatedAt:    row.CreatedAt,
+				UpdatedAt:    row.UpdatedAt,
+			}),
+			DistanceMeters: row.Distance,
+			DistanceType:   distanceTypeStraightLine,
+		})
+	}
+
+	if distanceType == distanceTypeRoad {
+		s.applyRoadDistances(r.Context(), resp, lon, lat)
+	}
+
+	points := make([]*GeoPoint, 0, len(resp))
+	for i := range resp {
+		points = append(points, &resp[i].Location)
+	}
+	if err := s.reprojectPoints(r.Context(), srid, points); err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to reproject coordinates", err.Error())
+		return
+	}
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
+// AwayUnitResponse is an available unit that has not returned to its home base.
+type AwayUnitResponse struct {
+	UnitResponse
+	HomeBaseLocation       GeoPoint `json:"home_base_location"`
+	DistanceFromBaseMeters float64  `json:"distance_from_base_meters"`
+}
+
+// handleListUnitsAway godoc
+// @Title List units away from their base
+// @Description Returns available units whose current position is farther than min_distance_m from their home base, farthest first. Units without a home base are not listed.
+// @Resource Units
+// @Produce json
+// @Param min_distance_m query number false "Distance from the home base in meters" default(UNIT_AWAY_DISTANCE_METERS)
+// @Param srid query int false "Output coordinate system" default(4326)
+// @Success 200 {array} AwayUnitResponse
+// @Failure 400 {object} APIError
+// @Failure 500 {object} APIError
+// @Route /v1/units/away [get]
+func (s *Server) handleListUnitsAway(w http.ResponseWriter, r *http.Request) {
+	srid, ok := s.parseSRIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	minDistance := s.cfg.Unit.AwayDistanceMeters
+	if raw := r.URL.Query().Get("min_distance_m"); raw != "" {
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil || parsed < 0 {
+			s.writeError(w, http.StatusBadRequest, "invalid min_distance_m", "min_distance_m must be a non-negative number of meters")
+			return
+		}
+		minDistance = parsed
+	}
+
+	rows, err := s.queries.ListUnitsAwayFromBase(r.Context(), minDistance)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to list units away from base", err.Error())
+		return
+	}
+
+	resp := make([]AwayUnitResponse, 0, len(rows))
+	for _, row := range rows {
+		homeBaseName := row.HomeBaseName
+		resp = append(resp, AwayUnitResponse{
+			UnitResponse: mapUnitRow(unitRowData{
+				ID:           row.ID,
+				CallSign:     row.CallSign,
+				UnitTypeCode: row.UnitTypeCode,
+				HomeBaseName: &homeBaseName,
+				LocationID:   row.LocationID,
+				Status:       row.Status,
+				MicrobitID:   row.MicrobitID,
+				Longitude:    row.Longitude,
+				Latitude:     row.Latitude,
+				LastContact:  row.LastContactAt,
+				CreatedAt:    row.CreatedAt,
+				UpdatedAt:    row.UpdatedAt,
+			}),
+			HomeBaseLocation:       GeoPoint{Latitude: row.HomeBaseLatitude, Longitude: row.HomeBaseLongitude},
+			DistanceFromBaseMeters: row.DistanceFromBase,
+		})
+	}
+
+	points := make([]*GeoPoint, 0, 2*len(resp))
+	for i := range resp {
+		points = append(points, &resp[i].Location, &resp[i].HomeBaseLocation)
+	}
+	if err := s.reprojectPoints(r.Context(), srid, points); err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to reproject coordinates", err.Error())
+		return
+	}
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
+// applyRoadDistances replaces the straight-line distance of the closest units with their
+// road distance to (lon, lat), computed concurrently with calculateRoutesTo;
+// units that are too far down the list or have no route keep the straight-line distance.
+func (s *Server) applyRoadDistances(ctx context.Context, units []NearbyUnitResponse, lon, lat float64) {
+	limit := len(units)
+	if maxUnits := int(s.cfg.Routing.NearestMaxUnits); maxUnits > 0 && maxUnits < limit {
+		limit = maxUnits
+	}
+	origins := make([]routeOrigin, limit)
+	for i := range origins {
+		origins[i] = routeOrigin{unitID: units[i].ID, location: units[i].Location}
+	}
+
+	for i, route := range s.calculateRoutesTo(ctx, origins, lon, lat) {
+		if route == nil {
+			continue
+		}
+		units[i].DistanceMeters = route.RouteLengthMeters
+		units[i].DistanceType = distanceTypeRoad
+		units[i].EstimatedDurationSeconds = &route.EstimatedDurationSeconds
+	}
+}
+
+// stationarySpeedKMH is the telemetry speed at or below which a unit is treated as stopped
+// and left out of the observed speed statistics.
+const stationarySpeedKMH = 5
+
+// UnitTypeSpeed compares the configured and observed speed of a unit type.
+type UnitTypeSpeed struct {
+	UnitTypeCode       string  `json:"unit_type_code"`
+	ConfiguredSpeedKMH *int32  `json:"configured_speed_kmh"`
+	ObservedSpeedKMH   float64 `json:"observed_speed_kmh"`
+	Units              int64   `json:"units"`
+	MovingReadings     int64   `json:"moving_readings"`
+	// Ratio is observed / configured speed, absent when the type has no configured speed.
+	Ratio *float64 `json:"ratio,omitempty"`
+}
+
+// UnitTypeSpeedsResponse is the response for GET /v1/stats/speed.
+type UnitTypeSpeedsResponse struct {
+	From      time.Time       `json:"from"`
+	To        time.Time       `json:"to"`
+	UnitTypes []UnitTypeSpeed `json:"unit_types"`
+}
+
+// handleGetUnitTypeSpeeds godoc
+// @Title Observed speed per unit type
+// @Description Returns the average telemetry speed of each unit type between from and to next to its configured speed_kmh. Readings at or below 5 km/h are treated as stationary and ignored; unit types without moving readings are omitted.
+// @Resource Units
+// @Produce json
+// @Param unit_type query string false "Only this unit type code"
+// @Param from query string false "Start (RFC3339), defaults to 30 days before to"
+// @Param to query string false "End (RFC3339, exclusive), defaults to now"
+// @Success 200 {object} UnitTypeSpeedsResponse
+// @Failure 400 {object} APIError
+// @Failure 500 {object} APIError
+// @Route /v1/stats/speed [get]
+func (s *Server) handleGetUnitTypeSpeeds(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := s.parseTimeRange(w, r, "from", "to", 30*24*time.Hour)
+	if !ok {
+		return
+	}
+
+	var unitType *string
+	if ut := strings.TrimSpace(r.URL.Query().Get("unit_type")); ut != "" {
+		unitType = &ut
+	}
+
+	rows, err := s.queries.ListUnitTypeObservedSpeeds(r.Context(), db.ListUnitTypeObservedSpeedsParams{
+		FromTime:    pgtype.Timestamptz{Time: from, Valid: true},
+		ToTime:      pgtype.Timestamptz{Time: to, Valid: true},
+		MinSpeedKmh: stationarySpeedKMH,
+		UnitType:    unitType,
+	})
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to compute unit type speeds", err.Error())
+		return
+	}
+
+	resp := UnitTypeSpeedsResponse{
+		From:      from,
+		To:        to,
+		UnitTypes: make([]UnitTypeSpeed, 0, len(rows)),
+	}
+	for _, row := range rows {
+		item := UnitTypeSpeed{
+			UnitTypeCode:       row.UnitTypeCode,
+			ConfiguredSpeedKMH: row.ConfiguredSpeedKmh,
+			ObservedSpeedKMH:   row.AvgSpeedKmh,
+			Units:              row.UnitCount,
+			MovingReadings:     row.MovingReadings,
+		}
+		if row.ConfiguredSpeedKmh != nil && *row.ConfiguredSpeedKmh > 0 {
+			ratio := row.AvgSpeedKmh / float64(*row.ConfiguredSpeedKmh)
+			item.Ratio = &ratio
+		}
+		resp.UnitTypes = append(resp.UnitTypes, item)
 	}
 
 	s.writeJSON(w, http.StatusOK, resp)
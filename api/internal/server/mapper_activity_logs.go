@@ -3,6 +3,8 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"strconv"
 
 	db "fast/pin/internal/db/sqlc"
 
@@ -71,3 +73,117 @@ func (s *Server) logInterventionStatusChange(ctx context.Context, interventionID
 	})
 	return err
 }
+
+// logEventStatusChange creates an activity log for an event lifecycle transition
+// (open, acknowledged, closed) so it shows on the event timeline. q lets the caller
+// write it inside its own transaction.
+func (s *Server) logEventStatusChange(ctx context.Context, q *db.Queries, eventID pgtype.UUID, oldStatus, newStatus string, actor *string) error {
+	metadata := map[string]string{"old_status": oldStatus, "new_status": newStatus}
+	metadataJSON, _ := json.Marshal(metadata)
+
+	entityType := "event"
+	_, err := q.CreateActivityLog(ctx, db.CreateActivityLogParams{
+		ActivityType: "status_change",
+		EntityType:   &entityType,
+		EntityID:     eventID,
+		Actor:        actor,
+		OldValue:     &oldStatus,
+		NewValue:     &newStatus,
+		Metadata:     metadataJSON,
+	})
+	return err
+}
+
+// logDispatchConfigChange records a dispatch config value change in dispatch_config_history
+// and as an activity log. q is the caller's transaction, so the history row commits or rolls
+// back with the update itself. The config key is stored in the activity log metadata since
+// entity_id only holds UUIDs.
+func (s *Server) logDispatchConfigChange(ctx context.Context, q *db.Queries, key string, oldValue, newValue pgtype.Numeric, actor *string) error {
+	if err := q.InsertDispatchConfigHistory(ctx, db.InsertDispatchConfigHistoryParams{
+		ConfigKey: key,
+		OldValue:  oldValue,
+		NewValue:  newValue,
+		Actor:     actor,
+	}); err != nil {
+		return fmt.Errorf("insert config history: %w", err)
+	}
+
+	metadata := map[string]string{"key": key}
+	metadataJSON, _ := json.Marshal(metadata)
+
+	entityType := "dispatch_config"
+	oldFloat, _ := numericToFloat64(oldValue)
+	newFloat, _ := numericToFloat64(newValue)
+	oldStr := strconv.FormatFloat(oldFloat, 'f', -1, 64)
+	newStr := strconv.FormatFloat(newFloat, 'f', -1, 64)
+	_, err := q.CreateActivityLog(ctx, db.CreateActivityLogParams{
+		ActivityType: "config_change",
+		EntityType:   &entityType,
+		Actor:        actor,
+		OldValue:     &oldStr,
+		NewValue:     &newStr,
+		Metadata:     metadataJSON,
+	})
+	return err
+}
+
+// logRouteDeviation creates an activity log for a unit found off its route
+func (s *Server) logRouteDeviation(ctx context.Context, unitID, interventionID pgtype.UUID, callSign string, deviationMeters float64) error {
+	metadata := map[string]string{
+		"call_sign":        callSign,
+		"intervention_id":  uuidString(interventionID),
+		"deviation_meters": strconv.FormatFloat(deviationMeters, 'f', 0, 64),
+	}
+	metadataJSON, _ := json.Marshal(metadata)
+
+	entityType := "unit"
+	_, err := s.queries.CreateActivityLog(ctx, db.CreateActivityLogParams{
+		ActivityType: "route_deviation",
+		EntityType:   &entityType,
+		EntityID:     unitID,
+		NewValue:     &callSign,
+		Metadata:     metadataJSON,
+	})
+	return err
+}
+
+// Event timeline codes written for assignment changes.
+const (
+	eventLogUnitDispatched = "unit_dispatched"
+	eventLogUnitReleased   = "unit_released"
+)
+
+// logAssignmentChange records an assignment creation or release on the timeline of its event.
+// It is gated by INTERVENTION_LOG_ASSIGNMENT_CHANGES and never fails the caller.
+func (s *Server) logAssignmentChange(ctx context.Context, code string, assignmentID pgtype.UUID, actor *string) {
+	if !s.cfg.Intervention.LogAssignmentChanges {
+		return
+	}
+
+	assignment, err := s.queries.GetAssignmentContext(ctx, assignmentID)
+	if err != nil {
+		s.log.Warn().Err(err).Str("assignment_id", uuidString(assignmentID)).Msg("failed to load assignment for event log")
+		return
+	}
+
+	metadata := map[string]string{
+		"assignment_id":   uuidString(assignment.ID),
+		"intervention_id": uuidString(assignment.InterventionID),
+		"unit_id":         uuidString(assignment.UnitID),
+		"call_sign":       assignment.CallSign,
+		"unit_type_code":  assignment.UnitTypeCode,
+	}
+	metadataJSON, _ := json.Marshal(metadata)
+
+	entityType := "event"
+	if _, err := s.queries.CreateActivityLog(ctx, db.CreateActivityLogParams{
+		ActivityType: code,
+		EntityType:   &entityType,
+		EntityID:     assignment.EventID,
+		Actor:        actor,
+		NewValue:     &assignment.CallSign,
+		Metadata:     metadataJSON,
+	}); err != nil {
+		s.log.Warn().Err(err).Str("assignment_id", uuidString(assignmentID)).Str("code", code).Msg("failed to write assignment event log")
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rs/zerolog"
 )
@@ -62,6 +63,30 @@ var (
 		[]string{"event_type", "severity"},
 	)
 
+	responseSLOMetTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "api_response_slo_met_total",
+			Help: "Assignments that arrived within the response-time target of their event severity.",
+		},
+		[]string{"severity"},
+	)
+
+	responseSLOMissedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "api_response_slo_missed_total",
+			Help: "Assignments that arrived after the response-time target of their event severity.",
+		},
+		[]string{"severity"},
+	)
+
+	engineNotificationsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "api_engine_notifications_total",
+			Help: "Notifications sent to decision engines, by engine, kind (refresh, dispatch) and outcome.",
+		},
+		[]string{"engine", "kind", "outcome"},
+	)
+
 	// Incident heatmap gauge - persisted from database, survives restarts
 	incidentHeatmapGauge = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -80,7 +105,46 @@ var (
 		[]string{"lat_bucket", "lon_bucket"},
 	)
 
-	metricsSyncMu sync.Mutex
+	// Current number of units per status and unit type, synced from database
+	unitsByStatusGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "api_units_by_status",
+			Help: "Number of units in each status per unit type, synced from database.",
+		},
+		[]string{"status", "unit_type_code"},
+	)
+
+	// Database connection pool gauges, refreshed from pgxpool.Stat()
+	pgxpoolAcquiredConns = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "pgxpool_acquired_conns",
+			Help: "Number of currently acquired connections in the database pool.",
+		},
+	)
+
+	pgxpoolIdleConns = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "pgxpool_idle_conns",
+			Help: "Number of currently idle connections in the database pool.",
+		},
+	)
+
+	pgxpoolTotalConns = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "pgxpool_total_conns",
+			Help: "Total number of connections currently in the database pool.",
+		},
+	)
+
+	pgxpoolMaxConns = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "pgxpool_max_conns",
+			Help: "Maximum size of the database pool.",
+		},
+	)
+
+	metricsSyncMu     sync.Mutex
+	unitMetricsSyncMu sync.Mutex
 )
 
 func init() {
@@ -90,8 +154,16 @@ func init() {
 		incidentHeatmapGauge,
 		incidentCountGauge,
 		assignmentTravelDurationSeconds,
+		responseSLOMetTotal,
+		responseSLOMissedTotal,
 		assignmentOnSiteDurationSeconds,
 		eventResolutionDurationSeconds,
+		engineNotificationsTotal,
+		unitsByStatusGauge,
+		pgxpoolAcquiredConns,
+		pgxpoolIdleConns,
+		pgxpoolTotalConns,
+		pgxpoolMaxConns,
 	)
 }
 
@@ -181,6 +253,76 @@ func StartIncidentMetricsSync(ctx context.Context, queries *db.Queries, log zero
 	}()
 }
 
+// SyncUnitStatusMetrics loads the unit counts per status and unit type from the database
+func SyncUnitStatusMetrics(ctx context.Context, queries *db.Queries) error {
+	unitMetricsSyncMu.Lock()
+	defer unitMetricsSyncMu.Unlock()
+
+	counts, err := queries.CountUnitsByStatus(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to count units by status: %w", err)
+	}
+
+	// Reset so that statuses which dropped to zero disappear
+	unitsByStatusGauge.Reset()
+	for _, c := range counts {
+		unitsByStatusGauge.WithLabelValues(string(c.Status), c.UnitTypeCode).Set(float64(c.UnitCount))
+	}
+	return nil
+}
+
+// StartUnitStatusMetricsSync starts a background goroutine that periodically syncs unit status metrics
+func StartUnitStatusMetricsSync(ctx context.Context, queries *db.Queries, log zerolog.Logger, interval time.Duration) {
+	// Initial sync
+	if err := SyncUnitStatusMetrics(ctx, queries); err != nil {
+		log.Error().Err(err).Msg("initial unit status metrics sync failed")
+	}
+
+	// Periodic sync
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := SyncUnitStatusMetrics(ctx, queries); err != nil {
+					log.Error().Err(err).Msg("periodic unit status metrics sync failed")
+				}
+			}
+		}
+	}()
+}
+
+// StartPoolMetricsSync publishes the database pool statistics to Prometheus every interval.
+func StartPoolMetricsSync(ctx context.Context, pool *pgxpool.Pool, interval time.Duration) {
+	syncPoolMetrics(pool)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				syncPoolMetrics(pool)
+			}
+		}
+	}()
+}
+
+func syncPoolMetrics(pool *pgxpool.Pool) {
+	stat := pool.Stat()
+	pgxpoolAcquiredConns.Set(float64(stat.AcquiredConns()))
+	pgxpoolIdleConns.Set(float64(stat.IdleConns()))
+	pgxpoolTotalConns.Set(float64(stat.TotalConns()))
+	pgxpoolMaxConns.Set(float64(stat.MaxConns()))
+}
+
 // metricsMiddleware records basic request metrics for Prometheus (RPS and latency).
 func (s *Server) metricsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -224,6 +366,15 @@ func (s *Server) observeAssignmentTravel(ctx context.Context, assignmentID pgtyp
 		row.UnitTypeCode,
 		severityLabel,
 	).Observe(duration.Seconds())
+
+	// Response-time SLO: only severities with a configured target are tracked
+	if target, ok := s.cfg.SLO.ResponseTargets[row.Severity]; ok && target > 0 {
+		if duration <= target {
+			responseSLOMetTotal.WithLabelValues(severityLabel).Inc()
+		} else {
+			responseSLOMissedTotal.WithLabelValues(severityLabel).Inc()
+		}
+	}
 }
 
 func (s *Server) observeAssignmentOnSite(ctx context.Context, assignmentID pgtype.UUID) {
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"context"
+	"math"
+	"time"
+
+	db "fast/pin/internal/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// routeCacheResolution is the number of cache cells per degree. 1e-4 degrees is about
+// 11 m of latitude, so only endpoints that snap to the same vertices share a cached route.
+const routeCacheResolution = 10000
+
+// routeCacheKey identifies a route_cache entry by its rounded endpoints.
+type routeCacheKey struct {
+	fromLat, fromLon, toLat, toLon int32
+}
+
+func newRouteCacheKey(fromLon, fromLat, toLon, toLat float64) routeCacheKey {
+	round := func(v float64) int32 { return int32(math.Round(v * routeCacheResolution)) }
+	return routeCacheKey{
+		fromLat: round(fromLat),
+		fromLon: round(fromLon),
+		toLat:   round(toLat),
+		toLon:   round(toLon),
+	}
+}
+
+// cachedRoute returns the unexpired route cached for key. Lookup errors are logged
+// and treated as a miss so routing keeps working without the cache.
+func (s *Server) cachedRoute(ctx context.Context, key routeCacheKey) (CalculateRouteResponse, bool) {
+	row, err := s.queries.GetCachedRoute(ctx, db.GetCachedRouteParams{
+		FromLatKey: key.fromLat,
+		FromLonKey: key.fromLon,
+		ToLatKey:   key.toLat,
+		ToLonKey:   key.toLon,
+	})
+	if err != nil {
+		if !isNotFound(err) {
+			s.log.Warn().Err(err).Msg("failed to read route cache")
+		}
+		return CalculateRouteResponse{}, false
+	}
+	return CalculateRouteResponse{
+		RouteGeoJSON:             row.RouteGeojson,
+		RouteLengthMeters:        row.RouteLengthMeters,
+		EstimatedDurationSeconds: row.EstimatedDurationSeconds,
+		AlreadyAtDestination:     row.AlreadyAtDestination,
+	}, true
+}
+
+// cacheRoute stores a calculated route under key for ROUTING_ROUTE_CACHE_TTL.
+func (s *Server) cacheRoute(ctx context.Context, key routeCacheKey, route CalculateRouteResponse) {
+	err := s.queries.UpsertCachedRoute(ctx, db.UpsertCachedRouteParams{
+		FromLatKey:               key.fromLat,
+		FromLonKey:               key.fromLon,
+		ToLatKey:                 key.toLat,
+		ToLonKey:                 key.toLon,
+		RouteGeojson:             route.RouteGeoJSON,
+		RouteLengthMeters:        route.RouteLengthMeters,
+		EstimatedDurationSeconds: route.EstimatedDurationSeconds,
+		AlreadyAtDestination:     route.AlreadyAtDestination,
+		Ttl:                      pgtype.Interval{Microseconds: s.cfg.Routing.RouteCacheTTL.Microseconds(), Valid: true},
+	})
+	if err != nil {
+		s.log.Warn().Err(err).Msg("failed to store route in cache")
+	}
+}
+
+// startRouteCachePrune periodically deletes expired route_cache entries
+// (disabled when ROUTING_ROUTE_CACHE_TTL is 0).
+func (s *Server) startRouteCachePrune(ctx context.Context) {
+	ttl := s.cfg.Routing.RouteCacheTTL
+	if ttl <= 0 {
+		return
+	}
+	interval := max(ttl, time.Minute)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				pruned, err := s.queries.PruneRouteCache(ctx)
+				if err != nil {
+					s.log.Warn().Err(err).Msg("failed to prune route cache")
+					continue
+				}
+				if pruned > 0 {
+					s.log.Debug().Int64("pruned", pruned).Msg("pruned expired cached routes")
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ArchivedRouteResponse is a route a unit followed for an assignment, archived when it was removed.
+type ArchivedRouteResponse struct {
+	ID                       int64     `json:"id"`
+	AssignmentID             string    `json:"assignment_id"`
+	UnitID                   string    `json:"unit_id"`
+	InterventionID           *string   `json:"intervention_id,omitempty"`
+	RouteGeoJSON             string    `json:"route_geojson"`
+	RouteLengthMeters        float64   `json:"route_length_meters"`
+	EstimatedDurationSeconds float64   `json:"estimated_duration_seconds"`
+	ProgressPercent          float64   `json:"progress_percent"`
+	CalculatedAt             time.Time `json:"calculated_at"`
+	ArchivedAt               time.Time `json:"archived_at"`
+}
+
+// AssignmentRouteHistoryResponse is the response for GET /v1/assignments/{assignmentID}/route/history
+type AssignmentRouteHistoryResponse struct {
+	AssignmentID string                  `json:"assignment_id"`
+	Routes       []ArchivedRouteResponse `json:"routes"`
+}
+
+// archiveUnitRoute copies the unit's current intervention route into the history table
+// before it is deleted or replaced. It is a no-op unless ROUTING_ARCHIVE_ROUTES is set,
+// and failures are logged without blocking the route change.
+func (s *Server) archiveUnitRoute(ctx context.Context, unitID pgtype.UUID) {
+	if !s.cfg.Routing.ArchiveRoutes {
+		return
+	}
+	if _, err := s.queries.ArchiveUnitRoute(ctx, unitID); err != nil {
+		s.log.Warn().Err(err).Str("unit_id", uuidString(unitID)).Msg("failed to archive unit route")
+	}
+}
+
+// handleGetAssignmentRouteHistory lists the archived routes of an assignment, oldest first
+func (s *Server) handleGetAssignmentRouteHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	assignmentID, err := s.parseUUIDParam(r, "assignmentID")
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid assignment id", err.Error())
+		return
+	}
+
+	if _, err := s.queries.GetAssignmentContext(ctx, assignmentID); err != nil {
+		if isNotFound(err) {
+			s.writeError(w, http.StatusNotFound, "assignment not found", nil)
+			return
+		}
+		s.writeError(w, http.StatusInternalServerError, "failed to get assignment", err.Error())
+		return
+	}
+
+	rows, err := s.queries.ListAssignmentRouteHistory(ctx, assignmentID)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to list route history", err.Error())
+		return
+	}
+
+	resp := AssignmentRouteHistoryResponse{
+		AssignmentID: uuidString(assignmentID),
+		Routes:       make([]ArchivedRouteResponse, 0, len(rows)),
+	}
+	for _, row := range rows {
+		route := ArchivedRouteResponse{
+			ID:                       row.ID,
+			AssignmentID:             uuidString(row.AssignmentID),
+			UnitID:                   uuidString(row.UnitID),
+			RouteGeoJSON:             row.RouteGeojson,
+			RouteLengthMeters:        row.RouteLengthMeters,
+			EstimatedDurationSeconds: row.EstimatedDurationSeconds,
+			ProgressPercent:          row.ProgressPercent,
+			CalculatedAt:             row.CalculatedAt.Time,
+			ArchivedAt:               row.ArchivedAt.Time,
+		}
+		if row.InterventionID.Valid {
+			id := uuidString(row.InterventionID)
+			route.InterventionID = &id
+		}
+		resp.Routes = append(resp.Routes, route)
+	}
+
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
+// startRouteHistoryPrune periodically deletes archived routes older than
+// ROUTING_ROUTE_HISTORY_RETENTION (disabled when the retention is 0).
+func (s *Server) startRouteHistoryPrune(ctx context.Context) {
+	cfg := s.cfg.Routing
+	if cfg.RouteHistoryRetention <= 0 {
+		return
+	}
+	interval := cfg.RouteHistoryPruneInterval
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				cutoff := time.Now().Add(-cfg.RouteHistoryRetention)
+				pruned, err := s.queries.PruneUnitRouteHistory(ctx, pgtype.Timestamptz{Time: cutoff, Valid: true})
+				if err != nil {
+					s.log.Warn().Err(err).Msg("failed to prune route history")
+					continue
+				}
+				if pruned > 0 {
+					s.log.Info().Int64("pruned", pruned).Time("cutoff", cutoff).Msg("pruned archived routes")
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,223 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	db "fast/pin/internal/db/sqlc"
+)
+
+// startStaleRouteRefresh runs a background loop recalculating assignment routes
+// older than ROUTING_MAX_ROUTE_AGE, so ETAs follow the units' actual positions.
+func (s *Server) startStaleRouteRefresh(ctx context.Context) {
+	cfg := s.cfg.Routing
+	if cfg.MaxRouteAge <= 0 {
+		return
+	}
+	interval := cfg.RefreshInterval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	s.log.Info().
+		Dur("max_route_age", cfg.MaxRouteAge).
+		Dur("interval", interval).
+		Msg("stale route refresh enabled")
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.refreshStaleRoutes(ctx)
+			}
+		}
+	}()
+}
+
+// tryAcquireRepairSlot takes one of the ROUTING_MAX_CONCURRENT_REPAIRS slots without waiting.
+func (s *Server) tryAcquireRepairSlot() bool {
+	select {
+	case s.repairSlots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// acquireRepairSlot waits for a repair slot; it returns false if ctx ends first.
+func (s *Server) acquireRepairSlot(ctx context.Context) bool {
+	select {
+	case s.repairSlots <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (s *Server) releaseRepairSlot() {
+	<-s.repairSlots
+}
+
+// checkRouteDeviation recalculates a unit's route from its new position when the position
+// is more than ROUTING_ROUTE_DEVIATION_METERS away from the active route, since progress
+// along the stored geometry no longer gives a meaningful ETA. The recalculation runs in the
+// background and is skipped while another repair of the same unit is in progress.
+func (s *Server) checkRouteDeviation(ctx context.Context, unitID pgtype.UUID, lon, lat float64) {
+	threshold := s.cfg.Routing.RouteDeviationMeters
+	if threshold <= 0 {
+		return
+	}
+
+	route, err := s.queries.GetUnitRouteDeviation(ctx, db.GetUnitRouteDeviationParams{
+		Longitude: lon,
+		Latitude:  lat,
+		UnitID:    unitID,
+	})
+	if err != nil {
+		if !isNotFound(err) {
+			s.log.Warn().Err(err).Str("unit_id", uuidString(unitID)).Msg("failed to check route deviation")
+		}
+		return
+	}
+	if route.DeviationMeters <= threshold {
+		return
+	}
+
+	key := uuidString(unitID)
+	if _, loaded := s.repairLocks.LoadOrStore(key, struct{}{}); loaded {
+		return
+	}
+
+	s.log.Info().
+		Str("unit_id", key).
+		Str("intervention_id", uuidString(route.InterventionID)).
+		Float64("deviation_meters", route.DeviationMeters).
+		Msg("unit off route, recalculating")
+	if err := s.logRouteDeviation(ctx, unitID, route.InterventionID, route.CallSign, route.DeviationMeters); err != nil {
+		s.log.Warn().Err(err).Str("unit_id", key).Msg("failed to log route deviation")
+	} else {
+		s.changes.notify()
+	}
+
+	go func() {
+		defer s.repairLocks.Delete(key)
+		ctx := context.Background()
+		if !s.acquireRepairSlot(ctx) {
+			return
+		}
+		defer s.releaseRepairSlot()
+		_ = s.calculateAndSaveRouteForAssignment(ctx, route.InterventionID, unitID, true)
+	}()
+}
+
+// refreshStaleRoutes performs a single refresh pass. Units already being repaired
+// (manually or by a previous pass) are skipped via repairLocks. A unit whose refresh
+// fails is left out of the following passes for ROUTING_MAX_ROUTE_AGE.
+func (s *Server) refreshStaleRoutes(ctx context.Context) {
+	cfg := s.cfg.Routing
+	batchSize := cfg.RefreshBatchSize
+	if batchSize <= 0 {
+		batchSize = 50
+	}
+	concurrency := cfg.RefreshConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	stale, err := s.queries.ListStaleAssignmentRoutes(ctx, db.ListStaleAssignmentRoutesParams{
+		MaxAge:      pgtype.Interval{Microseconds: cfg.MaxRouteAge.Microseconds(), Valid: true},
+		SkipUnitIds: s.routeRefreshBackedOff(),
+		MaxRoutes:   batchSize,
+	})
+	if err != nil {
+		s.log.Error().Err(err).Msg("failed to list stale routes")
+		return
+	}
+	if len(stale) == 0 {
+		return
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	refreshed, failed, skipped := 0, 0, 0
+
+	for _, route := range stale {
+		key := uuidString(route.UnitID)
+		if _, loaded := s.repairLocks.LoadOrStore(key, struct{}{}); loaded {
+			skipped++
+			continue
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(route db.ListStaleAssignmentRoutesRow, key string) {
+			defer func() {
+				s.repairLocks.Delete(key)
+				<-sem
+				wg.Done()
+			}()
+			if !s.acquireRepairSlot(ctx) {
+				return
+			}
+			defer s.releaseRepairSlot()
+
+			s.log.Info().
+				Str("unit_id", key).
+				Str("intervention_id", uuidString(route.InterventionID)).
+				Dur("route_age", time.Since(route.CalculatedAt.Time)).
+				Msg("recalculating stale route")
+			err := s.calculateAndSaveRouteForAssignment(ctx, route.InterventionID, route.UnitID, true)
+			if err != nil {
+				s.routeRefreshBackoff.Store(key, routeRefreshRetry{unitID: route.UnitID, at: time.Now().Add(cfg.MaxRouteAge)})
+			}
+
+			mu.Lock()
+			if err != nil {
+				failed++
+			} else {
+				refreshed++
+			}
+			mu.Unlock()
+		}(route, key)
+	}
+	wg.Wait()
+
+	s.log.Info().
+		Int("stale", len(stale)).
+		Int("refreshed", refreshed).
+		Int("failed", failed).
+		Int("skipped_in_progress", skipped).
+		Msg("stale route refresh pass completed")
+}
+
+// routeRefreshRetry is a routeRefreshBackoff entry: unitID is not refreshed again before at.
+type routeRefreshRetry struct {
+	unitID pgtype.UUID
+	at     time.Time
+}
+
+// routeRefreshBackedOff returns the units still backing off after a failed refresh and
+// forgets the ones whose backoff has elapsed.
+func (s *Server) routeRefreshBackedOff() []pgtype.UUID {
+	now := time.Now()
+	units := make([]pgtype.UUID, 0)
+	s.routeRefreshBackoff.Range(func(key, value any) bool {
+		retry := value.(routeRefreshRetry)
+		if now.Before(retry.at) {
+			units = append(units, retry.unitID)
+		} else {
+			s.routeRefreshBackoff.Delete(key)
+		}
+		return true
+	})
+	return units
+}
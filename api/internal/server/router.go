@@ -2,6 +2,8 @@ package server
 
 import (
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -18,7 +20,7 @@ func (s *Server) routes() http.Handler {
 	r.Use(s.metricsMiddleware)
 	r.Use(s.loggingMiddleware)
 	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(60 * time.Second))
+	r.Use(exceptStreams(middleware.Timeout(60 * time.Second)))
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"http://localhost:8080", "http://fast-pin-pon.4loop.org", "https://fast-pin-pon.4loop.org", "https://loan-mgt.github.io"},
 		AllowedMethods:   []string{"GET", "POST", "PATCH", "PUT", "DELETE", "OPTIONS"},
@@ -32,37 +34,69 @@ func (s *Server) routes() http.Handler {
 	r.Route("/v1", func(v1 chi.Router) {
 		// Apply JWT authentication to all v1 routes
 		v1.Use(s.authMw.Middleware)
+		v1.Use(s.changeNotifyMiddleware)
 
+		v1.Get("/auth/introspect", s.handleIntrospectToken)
+		v1.Get("/admin/config", s.handleGetAdminConfig)
 		v1.Get("/event-types", s.handleListEventTypes)
 		v1.Get("/unit-types", s.handleListUnitTypes)
 		v1.Get("/buildings", s.handleListBuildings)
+		v1.Get("/bases/rebalance", s.handleGetBaseRebalance)
 		v1.Get("/sync", s.handleSync)
+		v1.Get("/sync/poll", s.handleSyncPoll)
+		v1.Get("/stats/events/timeline", s.handleGetEventTimeline)
+		v1.Get("/stats/bases", s.handleGetBaseResponseTimes)
+		v1.Get("/stats/speed", s.handleGetUnitTypeSpeeds)
+		v1.Get("/events/heatmap/timeseries", s.handleGetEventHeatmapTimeseries)
+		v1.Get("/events/in-bounds", s.handleListEventsInBounds)
+		v1.Get("/events/extent", s.handleGetEventsExtent)
+		v1.Get("/events/search", s.handleSearchEvents)
 
 		v1.Get("/events", s.handleListEvents)
 		v1.Post("/events", s.handleCreateEvent)
 		v1.Get("/events/{eventID}", s.handleGetEvent)
+		v1.Patch("/events/{eventID}", s.handleUpdateEvent)
+		v1.Delete("/events/{eventID}", s.handleDeleteEvent)
 		v1.Get("/events/{eventID}/logs", s.handleListEventLogs)
 		v1.Post("/events/{eventID}/logs", s.handleCreateEventLog)
 		v1.Get("/event-logs/recent", s.handleListRecentEventLogs)
 		v1.Get("/events/{eventID}/interventions", s.handleListInterventionsForEvent)
+		v1.Get("/events/{eventID}/nearest-units", s.handleGetNearestUnits)
+		v1.Get("/events/{eventID}/staffing-recommendation", s.handleGetStaffingRecommendation)
 		v1.Patch("/events/{eventID}/auto-simulated", s.handleUpdateEventAutoSimulated)
+		v1.Post("/events/{eventID}/stand-down", s.handleStandDownEvent)
+		v1.Post("/events/{eventID}/acknowledge", s.handleAcknowledgeEvent)
+		v1.Post("/events/bulk-acknowledge", s.handleBulkAcknowledgeEvents)
 
+		v1.Get("/interventions", s.handleListInterventions)
+		v1.Get("/interventions/overlaps", s.handleListInterventionOverlaps)
 		v1.Post("/interventions", s.handleCreateIntervention)
 		v1.Get("/interventions/{interventionID}", s.handleGetIntervention)
 		v1.Patch("/interventions/{interventionID}/status", s.handleUpdateInterventionStatus)
 		v1.Post("/interventions/{interventionID}/assignments", s.handleCreateAssignment)
 		v1.Delete("/interventions/{interventionID}/assignments/{unitID}", s.handleReleaseAssignment)
 		v1.Get("/interventions/{interventionID}/assignments", s.handleListAssignmentsForIntervention)
+		v1.Get("/interventions/{interventionID}/export", s.handleExportIntervention)
+		v1.Get("/assignments/active", s.handleListActiveAssignments)
 		v1.Patch("/assignments/{assignmentID}/status", s.handleUpdateAssignmentStatus)
+		v1.Get("/assignments/{assignmentID}/route", s.handleGetAssignmentRoute)
+		v1.Get("/assignments/{assignmentID}/route/history", s.handleGetAssignmentRouteHistory)
 
 		v1.Get("/units", s.handleListUnits)
 		v1.Get("/units/nearby", s.handleListUnitsNearby)
+		v1.Get("/units/away", s.handleListUnitsAway)
+		v1.Get("/units/stream", s.handleUnitStream)
 		v1.Post("/units", s.handleCreateUnit)
 		v1.Delete("/units/{unitID}", s.handleDeleteUnit)
+		v1.Patch("/units/locations", s.handleBatchUpdateUnitLocations)
 		v1.Patch("/units/{unitID}/status", s.handleUpdateUnitStatus)
 		v1.Patch("/units/{unitID}/location", s.handleUpdateUnitLocation)
 		v1.Patch("/units/{unitID}/station", s.handleUpdateUnitStation)
+		v1.Get("/units/{unitID}/telemetry", s.handleListUnitTelemetry)
+		v1.Get("/units/{unitID}/track.geojson", s.handleGetUnitTrack)
 		v1.Post("/units/{unitID}/telemetry", s.handleInsertTelemetry)
+		v1.Get("/units/{unitID}/telemetry/gaps", s.handleListTelemetryGaps)
+		v1.Post("/units/{unitID}/checkin", s.handleUnitCheckin)
 		v1.Put("/units/{unitID}/microbit", s.handleAssignMicrobit)
 		v1.Delete("/units/{unitID}/microbit", s.handleUnassignMicrobit)
 		v1.Get("/units/by-microbit/{microbitID}", s.handleGetUnitByMicrobit)
@@ -70,19 +104,36 @@ func (s *Server) routes() http.Handler {
 		// Dispatch endpoints
 		v1.Get("/dispatch/config", s.handleGetDispatchConfig)
 		v1.Put("/dispatch/config", s.handleUpdateDispatchConfig)
+		v1.Put("/dispatch/config/batch", s.handleBatchUpdateDispatchConfig)
+		v1.Get("/dispatch/config/effective", s.handleGetEffectiveDispatchConfig)
+		v1.Post("/dispatch/config/reset", s.handleResetDispatchConfig)
+		v1.Get("/dispatch/config/{key}/history", s.handleGetDispatchConfigHistory)
 		v1.Get("/dispatch/static", s.handleGetDispatchStatic)
 		v1.Get("/dispatch/pending", s.handleListPendingInterventions)
+		v1.Get("/dispatch/missing-routes", s.handleListMissingRoutes)
+		v1.Get("/dispatch/health", s.handleGetDispatchHealth)
+		v1.Get("/dispatch/health/stream", s.handleDispatchHealthStream)
 		v1.Get("/interventions/{interventionID}/candidates", s.handleGetDispatchCandidates)
+		v1.Post("/interventions/{interventionID}/candidates/{unitID}/assign", s.handleAssignCandidate)
+		v1.Post("/interventions/{interventionID}/auto-assign", s.handleAutoAssign)
 		v1.Get("/interventions/{interventionID}/dispatch-info", s.handleGetInterventionDispatchInfo)
+		v1.Get("/interventions/{interventionID}/readiness", s.handleGetInterventionReadiness)
 
 		// Routing endpoints (pgRouting)
 		v1.Post("/routing/calculate", s.handleCalculateRoute)
+		v1.Post("/routing/calculate-with-exclusions", s.handleCalculateRouteWithExclusions)
+		v1.Post("/routing/calculate-multi", s.handleCalculateMultiRoute)
+		v1.Post("/routing/nearest-by-time", s.handleNearestByTime)
+		v1.Get("/routing/network-stats", s.handleGetRoutingNetworkStats)
 		v1.Get("/units/{unitID}/route", s.handleGetUnitRoute)
 		v1.Post("/units/{unitID}/route", s.handleSaveUnitRoute)
 		v1.Delete("/units/{unitID}/route", s.handleDeleteUnitRoute)
 		v1.Post("/units/{unitID}/route/repair", s.handleRepairUnitRoute)
+		v1.Post("/units/{unitID}/route/reset", s.handleResetUnitRoute)
 		v1.Patch("/units/{unitID}/route/progress", s.handleUpdateRouteProgress)
 		v1.Get("/units/{unitID}/route/position", s.handleGetRoutePosition)
+		v1.Post("/units/{unitID}/route/snap", s.handleSnapToUnitRoute)
+		v1.Get("/units/{unitID}/bearing", s.handleGetUnitBearing)
 
 	})
 
@@ -91,6 +142,20 @@ func (s *Server) routes() http.Handler {
 	return r
 }
 
+// exceptStreams applies mw to every request but the long-lived stream endpoints (paths ending in /stream).
+func exceptStreams(mw func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		wrapped := mw(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/stream") {
+				next.ServeHTTP(w, r)
+				return
+			}
+			wrapped.ServeHTTP(w, r)
+		})
+	}
+}
+
 func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
@@ -100,9 +165,35 @@ func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
 		s.log.Info().
 			Str("method", r.Method).
 			Str("path", r.URL.Path).
+			Str("query", s.redactQuery(r.URL.RawQuery)).
 			Int("status", ww.Status()).
 			Int("bytes", ww.BytesWritten()).
 			Dur("duration", duration).
 			Msg("http request")
 	})
 }
+
+// redactQuery returns the raw query with the values of HTTP_LOG_REDACT_PARAMS replaced by ***,
+// so tokens passed in the URL (e.g. the stream access_token) never reach the logs.
+func (s *Server) redactQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	pairs := strings.Split(rawQuery, "&")
+	for i, pair := range pairs {
+		key, _, hasValue := strings.Cut(pair, "=")
+		if !hasValue {
+			continue
+		}
+		if name, err := url.QueryUnescape(key); err == nil {
+			key = name
+		}
+		for _, param := range s.cfg.HTTP.LogRedactParams {
+			if strings.EqualFold(key, strings.TrimSpace(param)) {
+				pairs[i] = pair[:strings.IndexByte(pair, '=')+1] + "***"
+				break
+			}
+		}
+	}
+	return strings.Join(pairs, "&")
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -29,19 +30,94 @@ type Server struct {
 	startedAt time.Time
 	// repairLocks prevents concurrent repair attempts for the same unit
 	repairLocks sync.Map
+	// repairSlots bounds the route repairs running at once across all units
+	repairSlots chan struct{}
+	// routeRefreshBackoff maps a unit id to the time before which a failed stale route
+	// refresh is not retried, so unroutable units do not fill every batch
+	routeRefreshBackoff sync.Map
 
 	// lastMicrobitMessage tracks the timestamp of the last update received from the bridge
 	lastMicrobitMessage atomic.Value
+
+	// syncDefaultDeny is the intervention status deny set applied by /v1/sync when none is requested
+	syncDefaultDeny map[db.InterventionStatus]struct{}
+
+	// disallowedEventCombos are the event type/severity pairs refused on create and update
+	disallowedEventCombos map[eventCombination]struct{}
+
+	// interventionTransitions are the allowed intervention status changes; empty allows any
+	interventionTransitions map[interventionTransition]struct{}
+
+	// networkStats caches the routing graph statistics, which are expensive to compute
+	networkStatsMu sync.Mutex
+	networkStats   *RoutingNetworkStatsResponse
+
+	// staticData caches the encoded /v1/dispatch/static response; nil means not loaded
+	staticData atomic.Pointer[staticDataSnapshot]
+	// staticDataGen is bumped by invalidateStaticData; snapshots built under an older generation are discarded
+	staticDataGen atomic.Uint64
+
+	// streamConns counts open stream connections per JWT subject
+	streamConns streamLimiter
+
+	// changes wakes long-polling clients when a write request succeeds
+	changes changeHub
+
+	// dispatchHealthCache is the last dispatch health summary, shared by requests and streams
+	dispatchHealthMu    sync.Mutex
+	dispatchHealthCache *DispatchHealth
+
+	// unitChanges fans unit_changes notifications out to /v1/units/stream clients
+	unitChanges unitBroker
+
+	// assignRouteSem bounds the background route calculations started by new assignments
+	assignRouteSem chan struct{}
+
+	// engineRefreshTimer delays the engine refresh until config edits settle
+	engineRefreshMu    sync.Mutex
+	engineRefreshTimer *time.Timer
 }
 
 // New instantiates the HTTP server, runs DB migrations and prepares shared dependencies.
 func New(ctx context.Context, cfg config.Config, log zerolog.Logger) (*Server, error) {
+	syncDefaultDeny, err := newDenySet(cfg.Sync.DefaultDenyStatuses)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SYNC_DEFAULT_DENY_STATUSES: %w", err)
+	}
+
+	disallowedEventCombos, err := parseEventCombinations(cfg.Event.DisallowedCombinations)
+	if err != nil {
+		return nil, fmt.Errorf("invalid EVENT_DISALLOWED_COMBINATIONS: %w", err)
+	}
+
+	interventionTransitions, err := parseInterventionTransitions(cfg.Intervention.StatusTransitions)
+	if err != nil {
+		return nil, fmt.Errorf("invalid INTERVENTION_STATUS_TRANSITIONS: %w", err)
+	}
+
+	if err := validateSeverityPriorities(cfg.Intervention.SeverityPriorities); err != nil {
+		return nil, fmt.Errorf("invalid INTERVENTION_SEVERITY_PRIORITIES: %w", err)
+	}
+
+	switch cfg.Telemetry.SpeedCeilingMode {
+	case speedCeilingReject, speedCeilingClamp:
+	default:
+		return nil, fmt.Errorf("invalid TELEMETRY_SPEED_CEILING_MODE %q: must be %q or %q",
+			cfg.Telemetry.SpeedCeilingMode, speedCeilingReject, speedCeilingClamp)
+	}
+
 	pool, err := database.Connect(ctx, cfg, log)
 	if err != nil {
 		return nil, err
 	}
 
-	validate := newValidator()
+	unitStatuses, err := loadUnitStatuses(ctx, db.New(pool), cfg.Unit.Statuses)
+	if err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("invalid UNIT_STATUSES: %w", err)
+	}
+
+	validate := newValidator(unitStatuses)
 
 	authMw, err := NewAuthMiddleware(ctx, cfg.Keycloak, log)
 	if err != nil {
@@ -57,13 +133,22 @@ func New(ctx context.Context, cfg config.Config, log zerolog.Logger) (*Server, e
 		validate:  validate,
 		authMw:    authMw,
 		startedAt: time.Now().UTC(),
+
+		syncDefaultDeny:         syncDefaultDeny,
+		disallowedEventCombos:   disallowedEventCombos,
+		interventionTransitions: interventionTransitions,
+		assignRouteSem:          make(chan struct{}, max(cfg.Routing.AssignRouteConcurrency, 1)),
+		repairSlots:             make(chan struct{}, max(cfg.Routing.MaxConcurrentRepairs, 1)),
 	}
+	// Cursors from before this process started are considered stale
+	srv.changes.notify()
 
 	return srv, nil
 }
 
-// Close releases database resources.
+// Close sends any pending engine refresh and releases database resources.
 func (s *Server) Close() {
+	s.flushEngineRefresh()
 	if s.authMw != nil {
 		s.authMw.Close()
 	}
@@ -77,6 +162,27 @@ func (s *Server) Run(ctx context.Context) error {
 	// Start background incident metrics sync (syncs every 30 seconds)
 	StartIncidentMetricsSync(ctx, s.queries, s.log, 30*time.Second)
 
+	// Live unit counts per status for the operations dashboard
+	StartUnitStatusMetricsSync(ctx, s.queries, s.log, 15*time.Second)
+
+	// Expose connection pool saturation (acquired vs max conns)
+	StartPoolMetricsSync(ctx, s.pool, 10*time.Second)
+
+	// Periodically recalculate stale assignment routes (disabled unless ROUTING_MAX_ROUTE_AGE is set)
+	s.startStaleRouteRefresh(ctx)
+
+	// Drop archived routes past ROUTING_ROUTE_HISTORY_RETENTION
+	s.startRouteHistoryPrune(ctx)
+
+	// Drop cached routes past ROUTING_ROUTE_CACHE_TTL
+	s.startRouteCachePrune(ctx)
+
+	// Flag or close open events left without activity (disabled unless EVENT_STALE_AFTER is set)
+	s.startStaleEventWorker(ctx)
+
+	// Feed /v1/units/stream from the unit_changes NOTIFY channel
+	s.startUnitChangeListener(ctx)
+
 	httpServer := &http.Server{
 		Addr:         s.cfg.HTTP.Address,
 		Handler:      s.routes(),
@@ -102,7 +208,7 @@ func (s *Server) Run(ctx context.Context) error {
 	return nil
 }
 
-func newValidator() *validator.Validate {
+func newValidator(unitStatuses map[string]struct{}) *validator.Validate {
 	v := validator.New(validator.WithRequiredStructEnabled())
 	_ = v.RegisterValidation("latitude", func(fl validator.FieldLevel) bool {
 		val, ok := fl.Field().Interface().(float64)
@@ -118,5 +224,39 @@ func newValidator() *validator.Validate {
 		}
 		return val >= -180 && val <= 180
 	})
+	// unit_status replaces a static oneof so deployments can enable extra states through UNIT_STATUSES
+	_ = v.RegisterValidation("unit_status", func(fl validator.FieldLevel) bool {
+		_, ok := unitStatuses[fl.Field().String()]
+		return ok
+	})
 	return v
 }
+
+// loadUnitStatuses checks the configured unit statuses against the unit_status enum
+// and returns them as a lookup set.
+func loadUnitStatuses(ctx context.Context, queries *db.Queries, configured []string) (map[string]struct{}, error) {
+	values, err := queries.ListUnitStatusValues(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list unit_status values: %w", err)
+	}
+	known := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		known[v] = struct{}{}
+	}
+
+	statuses := make(map[string]struct{}, len(configured))
+	for _, status := range configured {
+		status = strings.TrimSpace(status)
+		if status == "" {
+			continue
+		}
+		if _, ok := known[status]; !ok {
+			return nil, fmt.Errorf("%q is not a value of the unit_status enum", status)
+		}
+		statuses[status] = struct{}{}
+	}
+	if len(statuses) == 0 {
+		return nil, errors.New("no unit status configured")
+	}
+	return statuses, nil
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// streamLimiter counts open stream connections per user.
+type streamLimiter struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// acquire reserves a connection slot for key. It returns false when the user
+// already holds max connections; max <= 0 means unlimited.
+func (l *streamLimiter) acquire(key string, max int) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.counts == nil {
+		l.counts = make(map[string]int)
+	}
+	if max > 0 && l.counts[key] >= max {
+		return false
+	}
+	l.counts[key]++
+	return true
+}
+
+// release frees a slot previously reserved with acquire.
+func (l *streamLimiter) release(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.counts[key] <= 1 {
+		delete(l.counts, key)
+		return
+	}
+	l.counts[key]--
+}
+
+// streamSubject identifies the user owning a stream connection: the JWT subject,
+// falling back to the username then the remote address.
+func streamSubject(r *http.Request) string {
+	if claims, ok := GetUserFromContext(r.Context()); ok {
+		if claims.Subject != "" {
+			return claims.Subject
+		}
+		if claims.PreferredUsername != "" {
+			return claims.PreferredUsername
+		}
+	}
+	return r.RemoteAddr
+}
+
+// acquireStreamSlot must be called by stream handlers before they start
+// streaming. When the user is over STREAM_MAX_CONNECTIONS_PER_USER it writes a
+// 429 and returns false; otherwise the caller must defer the returned release.
+func (s *Server) acquireStreamSlot(w http.ResponseWriter, r *http.Request) (func(), bool) {
+	subject := streamSubject(r)
+	max := s.cfg.Stream.MaxConnectionsPerUser
+	if !s.streamConns.acquire(subject, max) {
+		s.log.Warn().Str("subject", subject).Int("max", max).Msg("stream connection limit reached")
+		w.Header().Set("Retry-After", strconv.Itoa(5))
+		s.writeError(w, http.StatusTooManyRequests, "too many stream connections", map[string]int{"max_connections": max})
+		return nil, false
+	}
+	return func() { s.streamConns.release(subject) }, true
+}
+
+// startEventStream writes the Server-Sent Events headers and lifts the write
+// deadline so the stream can outlive HTTP_WRITE_TIMEOUT. It returns false when
+// the response cannot be flushed, in which case nothing can be streamed.
+func (s *Server) startEventStream(w http.ResponseWriter) (*http.ResponseController, bool) {
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		s.log.Debug().Err(err).Msg("could not clear write deadline for stream")
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		s.log.Warn().Err(err).Msg("stream does not support flushing")
+		return nil, false
+	}
+	return rc, true
+}
+
+// streamKeepAlive returns the interval between SSE keep-alive comments.
+func (s *Server) streamKeepAlive() time.Duration {
+	if s.cfg.Stream.KeepAlive <= 0 {
+		return 15 * time.Second
+	}
+	return s.cfg.Stream.KeepAlive
+}
@@ -0,0 +1,148 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// unitChangesChannel is the PostgreSQL NOTIFY channel fed by the units_notify_change trigger.
+const unitChangesChannel = "unit_changes"
+
+// unitBroker fans unit change notifications out to stream subscribers.
+// Subscribers that fall behind lose messages rather than blocking the others.
+type unitBroker struct {
+	mu   sync.Mutex
+	subs map[chan []byte]struct{}
+}
+
+// subscribe registers a new subscriber with a buffer of size messages.
+func (b *unitBroker) subscribe(size int) chan []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subs == nil {
+		b.subs = make(map[chan []byte]struct{})
+	}
+	ch := make(chan []byte, size)
+	b.subs[ch] = struct{}{}
+	return ch
+}
+
+// unsubscribe removes and closes a subscriber channel.
+func (b *unitBroker) unsubscribe(ch chan []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.subs[ch]; ok {
+		delete(b.subs, ch)
+		close(ch)
+	}
+}
+
+// publish sends payload to every subscriber and returns how many had to drop it.
+func (b *unitBroker) publish(payload []byte) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	dropped := 0
+	for ch := range b.subs {
+		select {
+		case ch <- payload:
+		default:
+			dropped++
+		}
+	}
+	return dropped
+}
+
+// startUnitChangeListener holds a dedicated connection listening on unitChangesChannel
+// and publishes every notification to the unit broker, reconnecting after failures.
+func (s *Server) startUnitChangeListener(ctx context.Context) {
+	go func() {
+		backoff := time.Second
+		for {
+			err := s.listenUnitChanges(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			s.log.Warn().Err(err).Dur("retry_in", backoff).Msg("unit change listener stopped")
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, 30*time.Second)
+		}
+	}()
+}
+
+func (s *Server) listenUnitChanges(ctx context.Context) error {
+	pooled, err := s.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire connection: %w", err)
+	}
+	// Take the connection out of the pool so the LISTEN state never leaks to other users
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+unitChangesChannel); err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+	s.log.Info().Str("channel", unitChangesChannel).Msg("listening for unit changes")
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		if dropped := s.unitChanges.publish([]byte(notification.Payload)); dropped > 0 {
+			s.log.Debug().Int("subscribers", dropped).Msg("dropped unit change for slow stream subscribers")
+		}
+	}
+}
+
+// handleUnitStream godoc
+// @Title Unit change stream
+// @Description Server-Sent Events stream emitting a data frame with the unit id, call sign, status, location and updated_at whenever a unit's location or status changes. A keep-alive comment is sent every STREAM_KEEP_ALIVE (15s).
+// @Resource Units
+// @Produce text/event-stream
+// @Success 200 {string} string "event stream"
+// @Failure 429 {object} APIError
+// @Failure 500 {object} APIError
+// @Route /v1/units/stream [get]
+func (s *Server) handleUnitStream(w http.ResponseWriter, r *http.Request) {
+	release, ok := s.acquireStreamSlot(w, r)
+	if !ok {
+		return
+	}
+	defer release()
+
+	updates := s.unitChanges.subscribe(s.cfg.Stream.SubscriberBuffer)
+	defer s.unitChanges.unsubscribe(updates)
+
+	rc, ok := s.startEventStream(w)
+	if !ok {
+		return
+	}
+
+	ticker := time.NewTicker(s.streamKeepAlive())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case payload := <-updates:
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", payload); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
@@ -9,6 +9,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"os"
 	"os/signal"
@@ -51,7 +52,15 @@ func newLogger(cfg config.Config) zerolog.Logger {
 	if err != nil {
 		level = zerolog.InfoLevel
 	}
-	writers := []io.Writer{openLogFile(cfg.LogFile)}
+	var writers []io.Writer
+	logFile, logFileErr := openLogFile(cfg.LogFile)
+	if logFileErr != nil {
+		if cfg.LogFileRequired {
+			log.Fatal().Err(logFileErr).Msg("open log file")
+		}
+	} else {
+		writers = append(writers, logFile)
+	}
 	if cfg.Env == "development" {
 		writers = append(writers, zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: time.RFC822})
 	} else {
@@ -63,22 +72,26 @@ func newLogger(cfg config.Config) zerolog.Logger {
 		Str("app", cfg.AppName).
 		Logger().
 		Output(zerolog.MultiLevelWriter(writers...))
+	if logFileErr != nil {
+		logger.Warn().Err(logFileErr).Str("path", cfg.LogFile).Msg("LOG FILE UNAVAILABLE: logging to stdout only (set LOG_FILE_REQUIRED=true to fail instead)")
+	}
 	return logger
 }
 
-func openLogFile(path string) *os.File {
+// openLogFile creates the log directory if needed and opens path for appending.
+func openLogFile(path string) (*os.File, error) {
 	if path == "" {
 		path = "logs/api.log"
 	}
 	dir := filepath.Dir(path)
 	if dir != "." && dir != "" {
 		if err := os.MkdirAll(dir, 0o755); err != nil {
-			log.Fatal().Err(err).Str("path", dir).Msg("create log directory")
+			return nil, fmt.Errorf("create log directory %s: %w", dir, err)
 		}
 	}
 	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
 	if err != nil {
-		log.Fatal().Err(err).Str("path", path).Msg("open log file")
+		return nil, fmt.Errorf("open log file %s: %w", path, err)
 	}
-	return file
+	return file, nil
 }
@@ -0,0 +1,17 @@
+-- +migrate Up
+-- Recommended number of units per type, e.g. {"FPT": 2, "EPA": 1}
+ALTER TABLE event_types ADD COLUMN recommended_unit_counts JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+-- Backfill: one unit of each recommended type
+UPDATE event_types et
+SET recommended_unit_counts = COALESCE(
+    (SELECT jsonb_object_agg(t, 1) FROM unnest(et.recommended_unit_types) AS t),
+    '{}'::jsonb
+);
+
+-- Response standards that require more than one unit of a type
+UPDATE event_types SET recommended_unit_counts = '{"FPT": 2, "EPA": 1}'::jsonb WHERE code = 'FIRE_URBAN';
+UPDATE event_types SET recommended_unit_counts = '{"FPT": 2, "FPTL": 1, "EPA": 1}'::jsonb WHERE code = 'FIRE_INDUSTRIAL';
+
+-- +migrate Down
+ALTER TABLE event_types DROP COLUMN IF EXISTS recommended_unit_counts;
@@ -0,0 +1,10 @@
+-- +migrate Up
+-- When the route geometry was last (re)calculated; updated_at also moves on progress updates
+ALTER TABLE unit_routes ADD COLUMN calculated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+UPDATE unit_routes SET calculated_at = updated_at;
+
+CREATE INDEX IF NOT EXISTS unit_routes_calculated_at_idx ON unit_routes (calculated_at);
+
+-- +migrate Down
+DROP INDEX IF EXISTS unit_routes_calculated_at_idx;
+ALTER TABLE unit_routes DROP COLUMN IF EXISTS calculated_at;
@@ -0,0 +1,26 @@
+-- +migrate Up
+-- +migrate StatementBegin
+BEGIN;
+
+-- Seeded default of each config key, used by POST /v1/dispatch/config/reset
+ALTER TABLE dispatch_config ADD COLUMN default_value NUMERIC;
+
+-- Keys without a known seed keep their current value as default
+UPDATE dispatch_config SET default_value = value;
+
+UPDATE dispatch_config SET default_value = 0.70 WHERE key = 'weight_travel_time';
+UPDATE dispatch_config SET default_value = 1.50 WHERE key = 'weight_coverage_penalty';
+UPDATE dispatch_config SET default_value = 0.2 WHERE key = 'weight_en_route_progress';
+UPDATE dispatch_config SET default_value = 5.0 WHERE key = 'weight_preemption_delta';
+UPDATE dispatch_config SET default_value = 85.0 WHERE key = 'weight_reassignment_cost';
+UPDATE dispatch_config SET default_value = 1.0 WHERE key = 'min_reserve_per_base';
+UPDATE dispatch_config SET default_value = 2.0 WHERE key = 'preemption_severity_threshold';
+UPDATE dispatch_config SET default_value = 10.0 WHERE key = 'max_candidates_per_dispatch';
+
+ALTER TABLE dispatch_config ALTER COLUMN default_value SET NOT NULL;
+
+COMMIT;
+-- +migrate StatementEnd
+
+-- +migrate Down
+ALTER TABLE dispatch_config DROP COLUMN IF EXISTS default_value;
@@ -0,0 +1,7 @@
+-- +migrate Up
+-- Planar index for viewport queries (ST_MakeEnvelope) that compare against location::geometry;
+-- events_location_idx only serves geography operators
+CREATE INDEX IF NOT EXISTS events_location_geom_idx ON events USING GIST ((location::geometry));
+
+-- +migrate Down
+DROP INDEX IF EXISTS events_location_geom_idx;
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE events ADD COLUMN acknowledged_at TIMESTAMPTZ;
+ALTER TABLE events ADD COLUMN acknowledged_by TEXT;
+
+-- +migrate Down
+ALTER TABLE events DROP COLUMN IF EXISTS acknowledged_by;
+ALTER TABLE events DROP COLUMN IF EXISTS acknowledged_at;
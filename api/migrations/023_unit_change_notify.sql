@@ -0,0 +1,27 @@
+-- +migrate Up
+-- Publishes unit location/status changes on the unit_changes channel for the /v1/units/stream SSE endpoint
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION notify_unit_change() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('unit_changes', json_build_object(
+        'id', NEW.id,
+        'call_sign', NEW.call_sign,
+        'status', NEW.status,
+        'longitude', ST_X(NEW.location::geometry),
+        'latitude', ST_Y(NEW.location::geometry),
+        'updated_at', NEW.updated_at
+    )::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER units_notify_change
+AFTER UPDATE OF location, status ON units
+FOR EACH ROW
+WHEN (OLD.location IS DISTINCT FROM NEW.location OR OLD.status IS DISTINCT FROM NEW.status)
+EXECUTE FUNCTION notify_unit_change();
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS units_notify_change ON units;
+DROP FUNCTION IF EXISTS notify_unit_change();
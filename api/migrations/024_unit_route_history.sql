@@ -0,0 +1,20 @@
+-- +migrate Up
+-- Routes of released assignments, kept for after-action replay when ROUTING_ARCHIVE_ROUTES is enabled
+CREATE TABLE IF NOT EXISTS unit_route_history (
+    id BIGSERIAL PRIMARY KEY,
+    assignment_id UUID REFERENCES intervention_assignments(id) ON DELETE CASCADE,
+    unit_id UUID NOT NULL REFERENCES units(id) ON DELETE CASCADE,
+    intervention_id UUID REFERENCES interventions(id) ON DELETE SET NULL,
+    route_geometry GEOMETRY(LINESTRING, 4326) NOT NULL,
+    route_length_meters DOUBLE PRECISION NOT NULL,
+    estimated_duration_seconds DOUBLE PRECISION NOT NULL,
+    progress_percent DOUBLE PRECISION NOT NULL,
+    calculated_at TIMESTAMPTZ NOT NULL,
+    archived_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS unit_route_history_assignment_idx ON unit_route_history (assignment_id, archived_at);
+CREATE INDEX IF NOT EXISTS unit_route_history_archived_at_idx ON unit_route_history (archived_at);
+
+-- +migrate Down
+DROP TABLE IF EXISTS unit_route_history;
@@ -0,0 +1,7 @@
+-- +migrate Up
+-- Full-text index backing GET /v1/events/search; the expression must match SearchEvents exactly
+CREATE INDEX IF NOT EXISTS events_search_idx ON events
+    USING GIN (to_tsvector('simple'::regconfig, title || ' ' || COALESCE(description, '') || ' ' || COALESCE(address, '')));
+
+-- +migrate Down
+DROP INDEX IF EXISTS events_search_idx;
@@ -0,0 +1,6 @@
+-- +migrate Up
+-- Soft-deleted events are hidden from the event lists but kept for audit
+ALTER TABLE events ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- +migrate Down
+ALTER TABLE events DROP COLUMN IF EXISTS deleted_at;
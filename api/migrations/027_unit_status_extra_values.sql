@@ -0,0 +1,7 @@
+-- +migrate Up
+-- Optional operational states; they are only accepted by the API once listed in UNIT_STATUSES
+ALTER TYPE unit_status ADD VALUE IF NOT EXISTS 'maintenance';
+ALTER TYPE unit_status ADD VALUE IF NOT EXISTS 'returning';
+
+-- +migrate Down
+-- PostgreSQL cannot drop enum values; units using them must be moved back manually
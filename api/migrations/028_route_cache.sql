@@ -0,0 +1,21 @@
+-- +migrate Up
+-- Routes between recently requested point pairs, keyed by coordinates rounded to 1e-4 degrees (~10 m).
+-- Entries expire after ROUTING_ROUTE_CACHE_TTL.
+CREATE TABLE IF NOT EXISTS route_cache (
+    from_lat_key INTEGER NOT NULL,
+    from_lon_key INTEGER NOT NULL,
+    to_lat_key INTEGER NOT NULL,
+    to_lon_key INTEGER NOT NULL,
+    route_geojson TEXT NOT NULL,
+    route_length_meters DOUBLE PRECISION NOT NULL,
+    estimated_duration_seconds DOUBLE PRECISION NOT NULL,
+    already_at_destination BOOLEAN NOT NULL DEFAULT FALSE,
+    calculated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (from_lat_key, from_lon_key, to_lat_key, to_lon_key)
+);
+
+CREATE INDEX IF NOT EXISTS route_cache_expires_at_idx ON route_cache (expires_at);
+
+-- +migrate Down
+DROP TABLE IF EXISTS route_cache;
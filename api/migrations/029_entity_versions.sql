@@ -0,0 +1,57 @@
+-- +migrate Up
+-- Row versions for optimistic concurrency on unit and event updates.
+-- The triggers bump the version only when a user-editable column changes, so position,
+-- telemetry and last_contact writes from moving units do not invalidate a version a
+-- dispatcher is editing against.
+ALTER TABLE units ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION bump_unit_version() RETURNS trigger AS $$
+BEGIN
+    IF ROW(NEW.call_sign, NEW.unit_type_code, NEW.status, NEW.microbit_id, NEW.location_id)
+       IS DISTINCT FROM ROW(OLD.call_sign, OLD.unit_type_code, OLD.status, OLD.microbit_id, OLD.location_id) THEN
+        NEW.version := OLD.version + 1;
+    ELSE
+        NEW.version := OLD.version;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION bump_event_version() RETURNS trigger AS $$
+BEGIN
+    IF ROW(NEW.title, NEW.description, NEW.report_source, NEW.address, NEW.location::text,
+           NEW.severity, NEW.event_type_code, NEW.closed_at, NEW.auto_simulated,
+           NEW.acknowledged_at, NEW.acknowledged_by, NEW.deleted_at)
+       IS DISTINCT FROM ROW(OLD.title, OLD.description, OLD.report_source, OLD.address, OLD.location::text,
+           OLD.severity, OLD.event_type_code, OLD.closed_at, OLD.auto_simulated,
+           OLD.acknowledged_at, OLD.acknowledged_by, OLD.deleted_at) THEN
+        NEW.version := OLD.version + 1;
+    ELSE
+        NEW.version := OLD.version;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER units_bump_version
+BEFORE UPDATE ON units
+FOR EACH ROW
+EXECUTE FUNCTION bump_unit_version();
+
+CREATE TRIGGER events_bump_version
+BEFORE UPDATE ON events
+FOR EACH ROW
+EXECUTE FUNCTION bump_event_version();
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS events_bump_version ON events;
+DROP TRIGGER IF EXISTS units_bump_version ON units;
+DROP FUNCTION IF EXISTS bump_event_version();
+DROP FUNCTION IF EXISTS bump_unit_version();
+ALTER TABLE events DROP COLUMN IF EXISTS version;
+ALTER TABLE units DROP COLUMN IF EXISTS version;
@@ -0,0 +1,27 @@
+-- +migrate Up
+-- Dispatch config value changes, written in the same transaction as the update so the
+-- history cannot miss a committed change.
+CREATE TABLE IF NOT EXISTS dispatch_config_history (
+    id BIGSERIAL PRIMARY KEY,
+    config_key TEXT NOT NULL REFERENCES dispatch_config(key) ON DELETE CASCADE,
+    old_value NUMERIC NOT NULL,
+    new_value NUMERIC NOT NULL,
+    actor TEXT,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS dispatch_config_history_key_changed_at_idx
+    ON dispatch_config_history (config_key, changed_at DESC);
+
+-- Carry over the changes recorded so far as activity logs
+INSERT INTO dispatch_config_history (config_key, old_value, new_value, actor, changed_at)
+SELECT al.metadata->>'key', al.old_value::numeric, al.new_value::numeric, al.actor, al.created_at
+FROM activity_logs al
+JOIN dispatch_config dc ON dc.key = al.metadata->>'key'
+WHERE al.entity_type = 'dispatch_config'
+  AND al.old_value IS NOT NULL
+  AND al.new_value IS NOT NULL
+ORDER BY al.created_at;
+
+-- +migrate Down
+DROP TABLE IF EXISTS dispatch_config_history;
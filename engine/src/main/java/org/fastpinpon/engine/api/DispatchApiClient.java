@@ -45,6 +45,8 @@ public interface DispatchApiClient {
     /**
      * Update unit status.
      * PATCH /v1/units/{unitId}/status
+     * The update is refused with 409 when the unit changed since version was read;
+     * a null version skips the check.
      */
-    void updateUnitStatus(String unitId, String status);
+    void updateUnitStatus(String unitId, String status, Integer version);
 }
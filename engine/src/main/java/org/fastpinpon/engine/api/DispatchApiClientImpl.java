@@ -84,9 +84,10 @@ public final class DispatchApiClientImpl implements DispatchApiClient {
     }
 
     @Override
-    public void updateUnitStatus(String unitId, String status) {
+    public void updateUnitStatus(String unitId, String status, Integer version) {
         StatusRequest request = new StatusRequest(status);
-        executeVoid(api.updateUnitStatus(unitId, request), "PATCH /v1/units/{id}/status");
+        String ifMatch = version != null ? "\"" + version + "\"" : null;
+        executeVoid(api.updateUnitStatus(unitId, ifMatch, request), "PATCH /v1/units/{id}/status");
     }
 
     /**
@@ -141,8 +142,9 @@ public final class DispatchApiClientImpl implements DispatchApiClient {
         @PATCH("v1/assignments/{assignmentId}/status")
         Call<Void> updateAssignmentStatus(@Path("assignmentId") String assignmentId, @Body StatusRequest request);
 
+        // A null If-Match is left out by Retrofit and the update is unconditional
         @PATCH("v1/units/{unitId}/status")
-        Call<Void> updateUnitStatus(@Path("unitId") String unitId, @Body StatusRequest request);
+        Call<Void> updateUnitStatus(@Path("unitId") String unitId, @Header("If-Match") String ifMatch, @Body StatusRequest request);
     }
 
     /**
@@ -105,6 +105,13 @@ public final class DispatchConfig {
         return (int) getOrDefault(MAX_CANDIDATES_PER_DISPATCH, 10.0);
     }
 
+    /**
+     * Returns all configured values, as loaded from the API.
+     */
+    public Map<String, Double> asMap() {
+        return values;
+    }
+
     @Override
     public String toString() {
         return "DispatchConfig" + values;
@@ -1,21 +1,25 @@
 package org.fastpinpon.engine.http;
 
+import com.fasterxml.jackson.core.JsonProcessingException;
+import com.fasterxml.jackson.databind.ObjectMapper;
 import org.fastpinpon.engine.cache.StaticDataCache;
 import org.fastpinpon.engine.domain.service.DispatchService;
 import spark.Service;
 
+import java.util.Collections;
 import java.util.Objects;
 import java.util.logging.Level;
 import java.util.logging.Logger;
 
 /**
  * HTTP server for engine callbacks from the API.
- * Exposes endpoints for refresh and dispatch triggers.
+ * Exposes endpoints for refresh, dispatch triggers and the currently loaded config.
  */
 public final class CallbackServer {
 
     private static final Logger LOG = Logger.getLogger(CallbackServer.class.getName());
     private static final String APPLICATION_JSON = "application/json";
+    private static final ObjectMapper MAPPER = new ObjectMapper();
 
     private final Service http;
     private final StaticDataCache cache;
@@ -38,6 +42,17 @@ public final class CallbackServer {
             return String.format("{\"status\":\"%s\"}", status);
         });
 
+        http.get("/config", (req, res) -> {
+            res.type(APPLICATION_JSON);
+            try {
+                return MAPPER.writeValueAsString(Collections.singletonMap("values", cache.getConfig().asMap()));
+            } catch (JsonProcessingException e) {
+                LOG.log(Level.SEVERE, e, () -> "Failed to serialize config");
+                res.status(500);
+                return "{\"error\":\"config serialization failed\"}";
+            }
+        });
+
         http.post("/refresh", (req, res) -> {
             res.type(APPLICATION_JSON);
             LOG.info("Received refresh request");
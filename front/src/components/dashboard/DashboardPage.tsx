@@ -89,11 +89,14 @@ export function DashboardPage({ units, buildings = [], selectedStationId, onStat
   const handleEditUnit = async (updates: { status?: UnitStatus; locationId?: string | null }) => {
     if (!editingUnit) return
 
+    // Each update is checked against the version the unit was displayed with
+    let version = editingUnit.version
     if (updates.status) {
-      await fastPinPonService.updateUnitStatus(editingUnit.id, updates.status, token ?? undefined)
+      const updated = await fastPinPonService.updateUnitStatus(editingUnit.id, updates.status, version, token ?? undefined)
+      version = updated.version
     }
     if (updates.locationId !== undefined) {
-      await fastPinPonService.updateUnitStation(editingUnit.id, updates.locationId, token ?? undefined)
+      await fastPinPonService.updateUnitStation(editingUnit.id, updates.locationId, version, token ?? undefined)
     }
     if (onRefresh) onRefresh()
   }
@@ -96,7 +96,7 @@ export function EventDetailPanel({
     const newValue = !localAutoSimulated
     setIsTogglingAuto(true)
     try {
-      await fastPinPonService.toggleEventAutoSimulated(event.id, newValue, token ?? undefined)
+      await fastPinPonService.toggleEventAutoSimulated(event.id, newValue, event.version, token ?? undefined)
       setLocalAutoSimulated(newValue)
       if (onRefresh) {
         await onRefresh()
@@ -4,16 +4,26 @@ import type { CreateEventRequest, EventType } from '../types/eventTypes'
 class FastPinPonService {
   private readonly API_BASE_URL = import.meta.env.VITE_API_BASE_URL || 'https://api.fast-pin-pon.4loop.org/v1'
 
-  private buildHeaders(token?: string): HeadersInit {
+  private buildHeaders(token?: string, ifMatch?: string): HeadersInit {
     const headers: HeadersInit = {
       'Content-Type': 'application/json',
     }
     if (token) {
       headers['Authorization'] = `Bearer ${token}`
     }
+    if (ifMatch) {
+      headers['If-Match'] = ifMatch
+    }
     return headers
   }
 
+  /**
+   * If-Match value for a row version, so the API refuses the update when the row changed since it was read.
+   */
+  private versionMatch(version?: number): string | undefined {
+    return version === undefined ? undefined : `"${version}"`
+  }
+
   async getEvents(limit = 10, token?: string, denyStatuses?: string[], offset = 0): Promise<EventSummary[]> {
     const params = new URLSearchParams({ limit: String(limit), offset: String(offset) })
     if (denyStatuses && denyStatuses.length > 0) {
@@ -153,10 +163,10 @@ class FastPinPonService {
     return response.json()
   }
 
-  async updateUnitStatus(unitId: string, status: string, token?: string): Promise<UnitSummary> {
+  async updateUnitStatus(unitId: string, status: string, version?: number, token?: string): Promise<UnitSummary> {
     const response = await fetch(`${this.API_BASE_URL}/units/${unitId}/status`, {
       method: 'PATCH',
-      headers: this.buildHeaders(token),
+      headers: this.buildHeaders(token, this.versionMatch(version)),
       body: JSON.stringify({ status }),
     })
     if (!response.ok) {
@@ -165,10 +175,10 @@ class FastPinPonService {
     return response.json()
   }
 
-  async updateUnitStation(unitId: string, locationId: string | null, token?: string): Promise<UnitSummary> {
+  async updateUnitStation(unitId: string, locationId: string | null, version?: number, token?: string): Promise<UnitSummary> {
     const response = await fetch(`${this.API_BASE_URL}/units/${unitId}/station`, {
       method: 'PATCH',
-      headers: this.buildHeaders(token),
+      headers: this.buildHeaders(token, this.versionMatch(version)),
       body: JSON.stringify({ location_id: locationId }),
     })
     if (!response.ok) {
@@ -248,10 +258,10 @@ class FastPinPonService {
    * Toggle auto simulation mode for an event.
    * When disabled, the event won't be processed by the dispatch engine or simulation.
    */
-  async toggleEventAutoSimulated(eventId: string, autoSimulated: boolean, token?: string): Promise<{ id: string, auto_simulated: boolean, updated_at: string }> {
+  async toggleEventAutoSimulated(eventId: string, autoSimulated: boolean, version?: number, token?: string): Promise<{ id: string, auto_simulated: boolean, updated_at: string, version: number }> {
     const response = await fetch(`${this.API_BASE_URL}/events/${eventId}/auto-simulated`, {
       method: 'PATCH',
-      headers: this.buildHeaders(token),
+      headers: this.buildHeaders(token, this.versionMatch(version)),
       body: JSON.stringify({ auto_simulated: autoSimulated }),
     })
     if (!response.ok) {
@@ -22,6 +22,7 @@ export type EventSummary = {
     started_at?: string
     completed_at?: string
     assigned_units?: UnitSummary[]
+    version?: number
 }
 
 export type UnitSummary = {
@@ -37,6 +38,7 @@ export type UnitSummary = {
     last_contact_at: string
     created_at: string
     updated_at: string
+    version?: number
 }
 
 export type EventLog = {
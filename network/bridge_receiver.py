@@ -29,6 +29,9 @@ load_dotenv()
 # Global authenticated session (initialized in main)
 _session: Optional[AuthenticatedSession] = None
 
+# Last known row version of each unit, sent as If-Match on status updates
+_unit_versions: Dict[str, int] = {}
+
 
 # Configuration
 API_DEFAULT_URL = "http://localhost:8081"
@@ -153,6 +156,8 @@ def load_microbit_cache(api_url: str) -> Tuple[Dict[str, str], Dict[str, str]]:
         for unit in response.json():
             microbit_id = unit.get("microbit_id")
             unit_id = unit.get("id")
+            if unit_id and unit.get("version") is not None:
+                _unit_versions[unit_id] = unit["version"]
             if microbit_id and unit_id:
                 microbit_to_unit[microbit_id] = unit_id
                 unit_to_microbit[unit_id] = microbit_id
@@ -171,10 +176,11 @@ def update_unit_location(api_url: str, unit_id: str, lat: float, lon: float) ->
             "longitude": lon,
             "recorded_at": datetime.now(timezone.utc).isoformat().replace("+00:00", "Z"),
         }
+        # Positions are telemetry and do not change the unit version: If-Match: * skips the check
         if _session:
-            response = _session.patch(url, json=payload, timeout=5)
+            response = _session.patch(url, json=payload, headers={"If-Match": "*"}, timeout=5)
         else:
-            response = requests.patch(url, json=payload, timeout=5)
+            response = requests.patch(url, json=payload, headers={"If-Match": "*"}, timeout=5)
         return response.status_code < 400
     except requests.RequestException:
         return False
@@ -185,16 +191,34 @@ def update_unit_status(api_url: str, unit_id: str, status: str) -> bool:
     try:
         new_status = normalize_status(status)
         url = f"{api_url.rstrip('/')}/v1/units/{unit_id}/status"
+        headers = {}
+        if unit_id in _unit_versions:
+            headers["If-Match"] = f'"{_unit_versions[unit_id]}"'
         if _session:
-            response = _session.patch(url, json={"status": new_status}, timeout=5)
+            response = _session.patch(url, json={"status": new_status}, headers=headers, timeout=5)
         else:
-            response = requests.patch(url, json={"status": new_status}, timeout=5)
+            response = requests.patch(url, json={"status": new_status}, headers=headers, timeout=5)
+        remember_unit_version(unit_id, response.headers.get("ETag"))
+        if response.status_code == 409:
+            # The unit was changed from the dispatch side since it was read: that change wins
+            # and is not overwritten by retrying with the new version
+            print(f"[WARN] Status {new_status} for {unit_id} not applied: unit changed since last read")
+            return True
         return response.status_code < 400
     except requests.RequestException:
         print(f"[ERROR] Failed to update status for {unit_id}")
         return False
 
 
+def remember_unit_version(unit_id: str, etag: Optional[str]) -> None:
+    if not etag:
+        return
+    try:
+        _unit_versions[unit_id] = int(etag.removeprefix("W/").strip('"'))
+    except ValueError:
+        pass
+
+
 def wait_for_api(api_url: str) -> None:
     print("[INFO] Attente de l'API...")
     while True:
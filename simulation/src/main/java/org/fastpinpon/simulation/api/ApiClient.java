@@ -10,6 +10,8 @@ import retrofit2.Retrofit;
 import retrofit2.converter.jackson.JacksonConverterFactory;
 import retrofit2.http.Body;
 import retrofit2.http.GET;
+import retrofit2.http.Header;
+import retrofit2.http.Headers;
 import retrofit2.http.PATCH;
 import retrofit2.http.POST;
 import retrofit2.http.Path;
@@ -19,7 +21,9 @@ import java.time.Instant;
 import java.time.format.DateTimeFormatter;
 import java.util.ArrayList;
 import java.util.List;
+import java.util.Map;
 import java.util.Random;
+import java.util.concurrent.ConcurrentHashMap;
 import org.slf4j.Logger;
 import org.slf4j.LoggerFactory;
 
@@ -30,6 +34,9 @@ public final class ApiClient {
     private final Random random = new Random();
     private final List<String> eventTypeCodes = new ArrayList<>();
     public final List<String> unitTypeCodes = new ArrayList<>();
+    // Last known row version of each unit, sent as If-Match so a status change made by a
+    // dispatcher since the unit was read is not overwritten
+    private final Map<String, Integer> unitVersions = new ConcurrentHashMap<>();
     private final ApiService api;
 
     public ApiClient(String baseUrlRaw, String tokenUrl, String clientId, String clientSecret) {
@@ -255,6 +262,9 @@ public final class ApiClient {
             if (dto.getId() == null) {
                 continue;
             }
+            if (dto.getVersion() != null) {
+                unitVersions.put(dto.getId(), dto.getVersion());
+            }
             Double lat = dto.getLatitude();
             if (lat == null && dto.getLocation() != null) {
                 lat = dto.getLocation().getLatitude();
@@ -312,7 +322,34 @@ public final class ApiClient {
             return;
         }
         StatusRequest payload = new StatusRequest(status);
-        executeVoid(api.updateUnitStatus(unitId, payload), "PATCH /v1/units/{id}/status", payload);
+        Integer version = unitVersions.get(unitId);
+        String ifMatch = version != null ? "\"" + version + "\"" : null;
+        try {
+            Response<Void> resp = api.updateUnitStatus(unitId, ifMatch, payload).execute();
+            rememberUnitVersion(unitId, resp.headers().get("ETag"));
+            if (resp.code() == 409) {
+                log.warn("PATCH /v1/units/{id}/status -> 409, unit {} changed since it was read, status {} not applied", unitId, status);
+            } else if (!resp.isSuccessful()) {
+                log.error("PATCH /v1/units/{id}/status -> {} body={} payload={}",
+                        resp.code(), errorBody(resp), serializePayload(payload));
+            }
+        } catch (Exception e) {
+            if (wasInterrupted(e)) {
+                return;
+            }
+            log.error("PATCH /v1/units/{id}/status error payload={}", serializePayload(payload), e);
+        }
+    }
+
+    private void rememberUnitVersion(String unitId, String etag) {
+        if (etag == null) {
+            return;
+        }
+        try {
+            unitVersions.put(unitId, Integer.parseInt(etag.replace("W/", "").replace("\"", "")));
+        } catch (NumberFormatException ignored) {
+            // Not a row version ETag
+        }
     }
 
     /**
@@ -569,9 +606,12 @@ public final class ApiClient {
         @PATCH("/v1/interventions/{interventionId}/status")
         Call<Void> updateInterventionStatus(@Path("interventionId") String interventionId, @Body StatusRequest body);
 
+        // A null If-Match is left out by Retrofit and the update is unconditional
         @PATCH("/v1/units/{unitId}/status")
-        Call<Void> updateUnitStatus(@Path("unitId") String unitId, @Body StatusRequest body);
+        Call<Void> updateUnitStatus(@Path("unitId") String unitId, @Header("If-Match") String ifMatch, @Body StatusRequest body);
 
+        // Positions are telemetry: they do not change the unit version and are written unconditionally
+        @Headers("If-Match: *")
         @PATCH("/v1/units/{unitId}/location")
         Call<Void> updateUnitLocation(@Path("unitId") String unitId, @Body LocationRequest body);
 
@@ -609,6 +649,8 @@ public final class ApiClient {
         private Double longitude;
         @JsonProperty("location")
         private LocationDto location;
+        @JsonProperty("version")
+        private Integer version;
 
         public String getId() {
             return id;
@@ -641,6 +683,10 @@ public final class ApiClient {
         public LocationDto getLocation() {
             return location;
         }
+
+        public Integer getVersion() {
+            return version;
+        }
     }
 
     @JsonIgnoreProperties(ignoreUnknown = true)